@@ -2,19 +2,25 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
-	httpapi "github.com/ovk741/TasksStream/internal/api/http"
 	"github.com/ovk741/TasksStream/internal/api/http/middleware"
 	"github.com/ovk741/TasksStream/internal/infra/auth"
+	"github.com/ovk741/TasksStream/internal/infra/events"
+	"github.com/ovk741/TasksStream/internal/infra/mail"
+	"github.com/ovk741/TasksStream/internal/infra/oidc"
 	"github.com/ovk741/TasksStream/internal/infra/security"
 	"github.com/ovk741/TasksStream/internal/service"
+	"github.com/ovk741/TasksStream/internal/storage"
+	"github.com/ovk741/TasksStream/internal/storage/memory"
 	"github.com/ovk741/TasksStream/internal/storage/postgres"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -24,8 +30,6 @@ func main() {
 	_ = godotenv.Load()
 
 	dsn := os.Getenv("DATABASE_DSN")
-	accessSecret := os.Getenv("ACCESS_SECRET")
-	refreshSecret := os.Getenv("REFRESH_SECRET")
 
 	accessTTLMinutes, _ := strconv.Atoi(os.Getenv("ACCESS_TTL_MINUTES"))
 	refreshTTLHours, _ := strconv.Atoi(os.Getenv("REFRESH_TTL_HOURS"))
@@ -42,91 +46,228 @@ func main() {
 	boardRepo := postgres.NewBoardRepository(pool)
 	columnRepo := postgres.NewColumnRepository(pool)
 	taskRepo := postgres.NewTaskRepository(pool)
+	taskAssigneeRepo := postgres.NewTaskAssigneeRepository(pool)
+	labelRepo := postgres.NewLabelRepository(pool)
+	customFieldRepo := postgres.NewCustomFieldRepository(pool)
+	checklistRepo := postgres.NewChecklistRepository(pool)
 	userRepo := postgres.NewUserRepository(pool)
 	boardMemberRepo := postgres.NewBoardMemberRepository(pool)
+	boardEventRepo := postgres.NewBoardEventRepository(pool)
+	refreshTokenRepo := postgres.NewRefreshTokenRepository(pool)
+	sessionRepo := postgres.NewSessionRepository(pool)
+	personalTokenRepo := postgres.NewPersonalTokenRepository(pool)
+	recoveryCodeRepo := postgres.NewRecoveryCodeRepository(pool)
+	userTokenRepo := postgres.NewUserTokenRepository(pool)
+	userIdentityRepo := postgres.NewUserIdentityRepository(pool)
+	oidcStateRepo := postgres.NewOIDCStateRepository(pool)
+	invitationRepo := postgres.NewBoardInvitationRepository(pool)
+	inviteLinkRepo := postgres.NewBoardInviteLinkRepository(pool)
+	boardRoleRepo := postgres.NewBoardRoleRepository(pool)
+	workspaceRepo := postgres.NewWorkspaceRepository(pool)
+	workspaceMemberRepo := postgres.NewWorkspaceMemberRepository(pool)
+	boardShareRepo := postgres.NewBoardShareRepository(pool)
+	teamRepo := postgres.NewTeamRepository(pool)
+	teamMemberRepo := postgres.NewTeamMemberRepository(pool)
+	boardTeamRepo := postgres.NewBoardTeamRepository(pool)
 
-	jwtManager := auth.NewJWTManager(accessSecret, refreshSecret, accessTTL, refreshTTL)
+	keyRing, err := loadKeyRing(os.Getenv("JWT_KEYS_DIR"), os.Getenv("JWT_ACTIVE_KEY_ID"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	jwtManager := auth.NewJWTManager(keyRing, accessTTL, refreshTTL)
 
 	hasher := security.NewBcryptHasher(bcrypt.DefaultCost)
 
-	authService := service.NewAuthService(userRepo, hasher, jwtManager, generateID)
+	mailer, err := newMailer()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	baseURL := os.Getenv("APP_BASE_URL")
+
+	boardPolicy := service.NewBoardPolicy(boardMemberRepo, boardRoleRepo, workspaceMemberRepo)
+
+	eventService := service.NewEventService(events.NewBroker(), boardEventRepo, boardRepo, boardPolicy)
+
+	invitationService := service.NewInvitationService(
+		invitationRepo,
+		boardRepo,
+		boardMemberRepo,
+		boardPolicy,
+		userRepo,
+		mailer,
+		eventService,
+		baseURL,
+		generateID,
+	)
+
+	accountService := service.NewAccountService(
+		userRepo,
+		userTokenRepo,
+		refreshTokenRepo,
+		sessionRepo,
+		hasher,
+		mailer,
+		invitationService,
+		baseURL,
+		generateID,
+	)
+
+	requireVerifiedEmail := os.Getenv("REQUIRE_EMAIL_VERIFICATION") == "true"
+
+	loginLimiter := service.NewLoginLimiter(newLoginAttemptRepository(pool), generateID)
+
+	authService := service.NewAuthService(
+		userRepo,
+		refreshTokenRepo,
+		sessionRepo,
+		recoveryCodeRepo,
+		accountService,
+		loginLimiter,
+		hasher,
+		jwtManager,
+		requireVerifiedEmail,
+		generateID,
+	)
+	twoFactorService := service.NewTwoFactorService(userRepo, recoveryCodeRepo, hasher, loginLimiter, generateID)
+
+	personalTokenService := service.NewPersonalTokenService(personalTokenRepo, generateID)
+	authenticator := service.NewAuthenticator(jwtManager, personalTokenRepo, userRepo)
+
+	userService := service.NewUserService(userRepo)
+
+	boardService := service.NewBoardService(boardRepo, columnRepo, taskRepo, boardMemberRepo, taskAssigneeRepo, boardPolicy, inviteLinkRepo, eventService, generateID)
+	columnService := service.NewColumnService(columnRepo, boardRepo, boardPolicy, taskRepo, eventService, generateID)
+	boardRoleService := service.NewBoardRoleService(boardRoleRepo, boardPolicy, generateID)
+	workspaceService := service.NewWorkspaceService(
+		workspaceRepo,
+		workspaceMemberRepo,
+		boardRepo,
+		boardMemberRepo,
+		taskAssigneeRepo,
+		userRepo,
+		boardPolicy,
+		eventService,
+		generateID,
+	)
+	boardShareService := service.NewBoardShareService(boardShareRepo, boardRepo, columnRepo, taskRepo, boardPolicy)
+	teamService := service.NewTeamService(
+		teamRepo,
+		teamMemberRepo,
+		boardTeamRepo,
+		boardMemberRepo,
+		taskAssigneeRepo,
+		userRepo,
+		boardPolicy,
+		eventService,
+		generateID,
+	)
+	taskService := service.NewTaskService(taskRepo, columnRepo, customFieldRepo, boardMemberRepo, boardPolicy, eventService, generateID)
+	taskAssigneeService := service.NewTaskAssigneeService(taskAssigneeRepo, taskRepo, columnRepo, boardMemberRepo, boardPolicy, eventService)
+	labelService := service.NewLabelService(labelRepo, taskRepo, columnRepo, boardPolicy, eventService, generateID)
+	customFieldService := service.NewCustomFieldService(customFieldRepo, boardPolicy, eventService, generateID)
+	checklistService := service.NewChecklistService(checklistRepo, taskRepo, columnRepo, boardPolicy, eventService, generateID)
+	adminService := service.NewAdminService(userRepo, boardRepo, boardMemberRepo, authService, eventService, generateID)
+
+	var oidcService service.OIDCService
+	oidcRedirectURL := os.Getenv("OIDC_REDIRECT_URL")
+	// браузер возвращается с провайдера на OIDC_REDIRECT_URL, поэтому
+	// cookie со state защищённая, если этот адрес https
+	oidcSecureCookie := strings.HasPrefix(oidcRedirectURL, "https://")
+	// вход через OpenID Connect включается, если задан OIDC_ISSUER
+	if issuer := os.Getenv("OIDC_ISSUER"); issuer != "" {
+		oidcClient := oidc.NewClient(
+			issuer,
+			os.Getenv("OIDC_CLIENT_ID"),
+			os.Getenv("OIDC_CLIENT_SECRET"),
+			oidcRedirectURL,
+			nil,
+		)
+		sessionStarter := service.NewSessionStarter(refreshTokenRepo, sessionRepo, jwtManager, generateID)
+		oidcService = service.NewOIDCService(oidcClient, oidcStateRepo, userIdentityRepo, userRepo, sessionStarter, invitationService, generateID)
+	}
 
-	boardService := service.NewBoardService(boardRepo, columnRepo, taskRepo, boardMemberRepo, generateID)
-	columnService := service.NewColumnService(columnRepo, boardRepo, boardMemberRepo, taskRepo, generateID)
-	taskService := service.NewTaskService(taskRepo, columnRepo, boardMemberRepo, generateID)
 	mux := http.NewServeMux()
-	authMW := middleware.AuthMiddleware(jwtManager)
-
-	mux.Handle("/auth/register", httpapi.RegisterHandler(authService))
-	mux.Handle("/auth/login", httpapi.LoginHandler(authService))
-	mux.Handle("/auth/refresh", httpapi.RefreshHandler(authService))
-
-	mux.Handle("/boards", authMW(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-
-		case http.MethodPost:
-
-			handler := httpapi.CreateBoardHandler(boardService)
-			handler(w, r)
-
-		case http.MethodGet:
-			handler := httpapi.GetBoardsHandler(boardService)
-			handler(w, r)
-
-		case http.MethodPut:
-			handler := httpapi.UpdateBoardHandler(boardService)
-			handler(w, r)
-
-		case http.MethodDelete:
-			handler := httpapi.DeleteBoardHandler(boardService)
-			handler(w, r)
-
-		default:
-			w.WriteHeader(http.StatusMethodNotAllowed)
-		}
-
-	})))
-
-	mux.Handle("/boards/invite", authMW(httpapi.InviteToBoardHandler(boardService)))
-	mux.Handle("/boards/members", authMW(httpapi.GetBoardMembersHandler(boardService)))
-	mux.Handle("/boards/members/remove", authMW(httpapi.RemoveBoardMemberHandler(boardService)))
-
-	mux.Handle("/columns", authMW(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case http.MethodPost:
-			httpapi.CreateColumnHandler(columnService)(w, r)
-		case http.MethodGet:
-			httpapi.GetColumnsByBoardHandler(columnService)(w, r)
-		case http.MethodPut:
-			httpapi.UpdateColumnHandler(columnService)(w, r)
-		case http.MethodDelete:
-			httpapi.DeleteColumnHandler(columnService)(w, r)
-		default:
-			w.WriteHeader(http.StatusMethodNotAllowed)
-		}
-	})))
-
-	mux.Handle("/columns/move", authMW(httpapi.MoveColumnHandler(columnService)))
-
-	mux.Handle("/tasks", authMW(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case http.MethodPost:
-			httpapi.CreateTaskHandler(taskService)(w, r)
-		case http.MethodGet:
-			httpapi.GetTasksByColumnHandler(taskService)(w, r)
-		case http.MethodPut:
-			httpapi.UpdateTaskHandler(taskService)(w, r)
-		case http.MethodDelete:
-			httpapi.DeleteTaskHandler(taskService)(w, r)
-		default:
-			w.WriteHeader(http.StatusMethodNotAllowed)
-		}
-	})))
-
-	mux.Handle("/tasks/move", authMW(httpapi.MoveTaskHandler(taskService)))
+	registerRoutes(mux, authenticator, jwtManager, services{
+		auth:             authService,
+		account:          accountService,
+		twoFactor:        twoFactorService,
+		personalTokens:   personalTokenService,
+		users:            userService,
+		boards:           boardService,
+		boardRoles:       boardRoleService,
+		workspaces:       workspaceService,
+		boardShares:      boardShareService,
+		teams:            teamService,
+		admin:            adminService,
+		events:           eventService,
+		invitations:      invitationService,
+		columns:          columnService,
+		tasks:            taskService,
+		taskAssignees:    taskAssigneeService,
+		labels:           labelService,
+		customFields:     customFieldService,
+		checklists:       checklistService,
+		oidc:             oidcService,
+		oidcSecureCookie: oidcSecureCookie,
+	})
+
+	var handler http.Handler = mux
+	if os.Getenv("TRUST_PROXY_HEADERS") == "true" {
+		// по умолчанию перед сервисом один прокси
+		trustedHops, _ := strconv.Atoi(os.Getenv("TRUSTED_PROXY_HOPS"))
+		handler = middleware.RealIP(trustedHops)(handler)
+	}
 
 	port := os.Getenv("PORT")
-	log.Fatal(http.ListenAndServe(":"+port, mux))
+	log.Fatal(http.ListenAndServe(":"+port, handler))
+}
+
+func loadKeyRing(dir, activeKID string) (*auth.KeyRing, error) {
+	if dir != "" {
+		return auth.LoadKeyRing(dir, activeKID)
+	}
+
+	log.Println("JWT_KEYS_DIR is not set, using an ephemeral signing key: tokens will not survive a restart")
+
+	key, err := auth.GenerateEd25519Key("ephemeral-" + generateID())
+	if err != nil {
+		return nil, err
+	}
+
+	return auth.NewKeyRing("", key)
+}
+
+// счётчики неудачных входов по умолчанию хранятся в postgres, чтобы
+// блокировка действовала на всех экземплярах сервиса
+func newLoginAttemptRepository(pool *pgxpool.Pool) storage.LoginAttemptRepository {
+	if os.Getenv("LOGIN_ATTEMPTS_STORE") == "memory" {
+		return memory.NewLoginAttemptRepository()
+	}
+	return postgres.NewLoginAttemptRepository(pool)
+}
+
+// newMailer выбирает способ отправки писем по MAIL_DRIVER: smtp, file или log (по умолчанию)
+func newMailer() (service.Mailer, error) {
+	from := os.Getenv("MAIL_FROM")
+
+	switch driver := os.Getenv("MAIL_DRIVER"); driver {
+	case "smtp":
+		return mail.NewSMTPMailer(
+			os.Getenv("SMTP_HOST"),
+			os.Getenv("SMTP_PORT"),
+			os.Getenv("SMTP_USERNAME"),
+			os.Getenv("SMTP_PASSWORD"),
+			from,
+		), nil
+	case "file":
+		return mail.NewFileMailer(os.Getenv("MAIL_DIR"), from)
+	case "", "log":
+		return mail.NewLogMailer(), nil
+	default:
+		return nil, fmt.Errorf("unknown MAIL_DRIVER %q", driver)
+	}
 }
 
 func generateID() string {
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"net/http"
+
+	httpapi "github.com/ovk741/TasksStream/internal/api/http"
+	"github.com/ovk741/TasksStream/internal/api/http/middleware"
+	"github.com/ovk741/TasksStream/internal/service"
+)
+
+// services — сервисы, которые обработчики получают при регистрации маршрутов
+type services struct {
+	auth           service.AuthService
+	account        service.AccountService
+	twoFactor      service.TwoFactorService
+	personalTokens service.PersonalTokenService
+	users          service.UserService
+	boards         service.BoardService
+	boardRoles     service.BoardRoleService
+	workspaces     service.WorkspaceService
+	boardShares    service.BoardShareService
+	teams          service.TeamService
+	admin          service.AdminService
+	events         service.EventService
+	invitations    service.InvitationService
+	columns        service.ColumnService
+	tasks          service.TaskService
+	taskAssignees  service.TaskAssigneeService
+	labels         service.LabelService
+	customFields   service.CustomFieldService
+	checklists     service.ChecklistService
+	// oidc равен nil, если вход через OpenID Connect не настроен
+	oidc service.OIDCService
+	// oidcSecureCookie включает Secure у cookie со state
+	oidcSecureCookie bool
+}
+
+func registerRoutes(mux *http.ServeMux, authenticator middleware.Authenticator, keys httpapi.JWKSProvider, s services) {
+	authMW := middleware.AuthMiddleware(authenticator)
+	// управление учётной записью доступно только по обычному JWT
+	sessionMW := func(next http.Handler) http.Handler {
+		return authMW(middleware.RequireSessionToken(next))
+	}
+
+	mux.Handle("/.well-known/jwks.json", httpapi.JWKSHandler(keys))
+
+	mux.Handle("/auth/register", httpapi.RegisterHandler(s.auth))
+	mux.Handle("/auth/login", httpapi.LoginHandler(s.auth))
+	mux.Handle("/auth/login/2fa", httpapi.LoginTwoFactorHandler(s.auth))
+	mux.Handle("/auth/refresh", httpapi.RefreshHandler(s.auth))
+	// вход через OpenID Connect включается, если задан OIDC_ISSUER
+	if s.oidc != nil {
+		mux.Handle("GET /auth/oidc/login", httpapi.OIDCLoginHandler(s.oidc, s.oidcSecureCookie))
+		mux.Handle("GET /auth/oidc/callback", httpapi.OIDCCallbackHandler(s.oidc, s.oidcSecureCookie))
+	}
+
+	mux.Handle("POST /auth/password/forgot", httpapi.ForgotPasswordHandler(s.account))
+	mux.Handle("POST /auth/password/reset", httpapi.ResetPasswordHandler(s.account))
+	mux.Handle("POST /auth/verify-email", httpapi.VerifyEmailHandler(s.account))
+	mux.Handle("POST /auth/verify-email/resend", httpapi.ResendVerificationHandler(s.account))
+	mux.Handle("/auth/logout", httpapi.LogoutHandler(s.auth))
+	mux.Handle("/auth/logout-all", sessionMW(httpapi.LogoutAllHandler(s.auth)))
+	mux.Handle("GET /auth/sessions", sessionMW(httpapi.GetSessionsHandler(s.auth)))
+	mux.Handle("DELETE /auth/sessions/{id}", sessionMW(httpapi.RevokeSessionHandler(s.auth)))
+
+	mux.Handle("POST /auth/2fa/setup", sessionMW(httpapi.SetupTwoFactorHandler(s.twoFactor)))
+	mux.Handle("POST /auth/2fa/verify", sessionMW(httpapi.VerifyTwoFactorHandler(s.twoFactor)))
+	mux.Handle("DELETE /auth/2fa", sessionMW(httpapi.DisableTwoFactorHandler(s.twoFactor)))
+
+	mux.Handle("POST /auth/tokens", sessionMW(httpapi.CreatePersonalTokenHandler(s.personalTokens)))
+	mux.Handle("GET /auth/tokens", sessionMW(httpapi.GetPersonalTokensHandler(s.personalTokens)))
+	mux.Handle("DELETE /auth/tokens/{id}", sessionMW(httpapi.RevokePersonalTokenHandler(s.personalTokens)))
+
+	mux.Handle("GET /users/me", authMW(httpapi.GetMeHandler(s.users)))
+	mux.Handle("PUT /users/me", sessionMW(httpapi.UpdateMeHandler(s.users)))
+	mux.Handle("GET /users", authMW(httpapi.SearchUsersHandler(s.users)))
+
+	mux.Handle("/boards", authMW(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+
+		case http.MethodPost:
+
+			handler := httpapi.CreateBoardHandler(s.boards)
+			handler(w, r)
+
+		case http.MethodGet:
+			handler := httpapi.GetBoardsHandler(s.boards)
+			handler(w, r)
+
+		case http.MethodPut:
+			handler := httpapi.UpdateBoardHandler(s.boards)
+			handler(w, r)
+
+		case http.MethodDelete:
+			handler := httpapi.DeleteBoardHandler(s.boards)
+			handler(w, r)
+
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+
+	})))
+
+	mux.Handle("GET /boards/{id}/roles", authMW(httpapi.GetBoardRolesHandler(s.boardRoles)))
+	mux.Handle("POST /boards/{id}/roles", authMW(httpapi.CreateBoardRoleHandler(s.boardRoles)))
+	mux.Handle("PUT /boards/{id}/roles/{name}", authMW(httpapi.UpdateBoardRoleHandler(s.boardRoles)))
+	mux.Handle("DELETE /boards/{id}/roles/{name}", authMW(httpapi.DeleteBoardRoleHandler(s.boardRoles)))
+	mux.Handle("PUT /boards/{id}/workspace", authMW(httpapi.MoveBoardHandler(s.workspaces)))
+
+	mux.Handle("POST /workspaces", authMW(httpapi.CreateWorkspaceHandler(s.workspaces)))
+	mux.Handle("GET /workspaces", authMW(httpapi.GetWorkspacesHandler(s.workspaces)))
+	mux.Handle("GET /workspaces/{id}/members", authMW(httpapi.GetWorkspaceMembersHandler(s.workspaces)))
+	mux.Handle("POST /workspaces/{id}/members", authMW(httpapi.AddWorkspaceMemberHandler(s.workspaces)))
+	mux.Handle("DELETE /workspaces/{id}/members/{user_id}", authMW(httpapi.RemoveWorkspaceMemberHandler(s.workspaces)))
+	mux.Handle("GET /workspaces/{id}/boards", authMW(httpapi.GetWorkspaceBoardsHandler(s.workspaces)))
+	mux.Handle("POST /workspaces/{id}/boards", authMW(httpapi.CreateWorkspaceBoardHandler(s.workspaces)))
+
+	// публичная ссылка живёт под /share/link: шаблон /boards/{id}/share
+	// пересекался бы с DELETE /boards/invite-links/{id}
+	mux.Handle("GET /boards/{id}/share/link", authMW(httpapi.GetBoardShareHandler(s.boardShares)))
+	mux.Handle("POST /boards/{id}/share/link", authMW(httpapi.EnableBoardShareHandler(s.boardShares)))
+	mux.Handle("DELETE /boards/{id}/share/link", authMW(httpapi.DisableBoardShareHandler(s.boardShares)))
+	// публичная доска открывается по токену ссылки, без аутентификации
+	mux.Handle("GET /public/boards/{token}", httpapi.PublicBoardHandler(s.boardShares))
+
+	mux.Handle("POST /teams", authMW(httpapi.CreateTeamHandler(s.teams)))
+	mux.Handle("GET /teams", authMW(httpapi.GetTeamsHandler(s.teams)))
+	mux.Handle("GET /teams/{id}/members", authMW(httpapi.GetTeamMembersHandler(s.teams)))
+	mux.Handle("POST /teams/{id}/members", authMW(httpapi.AddTeamMemberHandler(s.teams)))
+	mux.Handle("DELETE /teams/{id}/members/{user_id}", authMW(httpapi.RemoveTeamMemberHandler(s.teams)))
+	mux.Handle("GET /boards/{id}/teams", authMW(httpapi.GetBoardTeamsHandler(s.teams)))
+	mux.Handle("PUT /boards/{id}/teams/{team_id}", authMW(httpapi.GrantBoardTeamHandler(s.teams)))
+	mux.Handle("DELETE /boards/{id}/teams/{team_id}", authMW(httpapi.RevokeBoardTeamHandler(s.teams)))
+
+	// административный API доступен только по обычному JWT
+	mux.Handle("GET /admin/users", sessionMW(httpapi.AdminListUsersHandler(s.admin)))
+	mux.Handle("POST /admin/users/{id}/disable", sessionMW(httpapi.AdminSetUserDisabledHandler(s.admin, true)))
+	mux.Handle("POST /admin/users/{id}/enable", sessionMW(httpapi.AdminSetUserDisabledHandler(s.admin, false)))
+	mux.Handle("POST /admin/users/{id}/logout", sessionMW(httpapi.AdminForceLogoutHandler(s.admin)))
+	mux.Handle("GET /admin/boards", sessionMW(httpapi.AdminListBoardsHandler(s.admin)))
+	mux.Handle("PUT /admin/boards/{id}/owner", sessionMW(httpapi.AdminReassignOwnerHandler(s.admin)))
+
+	mux.Handle("GET /boards/{id}/stream", authMW(httpapi.BoardStreamHandler(s.events)))
+	mux.Handle("GET /boards/{id}/events", authMW(httpapi.BoardEventsSSEHandler(s.events)))
+
+	mux.Handle("/boards/invite", authMW(httpapi.InviteToBoardHandler(s.boards)))
+	mux.Handle("/boards/members", authMW(httpapi.GetBoardMembersHandler(s.boards)))
+	mux.Handle("/boards/members/remove", authMW(httpapi.RemoveBoardMemberHandler(s.boards)))
+	mux.Handle("PUT /boards/members/role", authMW(httpapi.ChangeMemberRoleHandler(s.boards)))
+	mux.Handle("POST /boards/transfer-ownership", authMW(httpapi.TransferOwnershipHandler(s.boards)))
+	mux.Handle("POST /boards/leave", authMW(httpapi.LeaveBoardHandler(s.boards)))
+
+	mux.Handle("POST /boards/invite-links", authMW(httpapi.CreateInviteLinkHandler(s.boards)))
+	mux.Handle("GET /boards/invite-links", authMW(httpapi.GetInviteLinksHandler(s.boards)))
+	mux.Handle("DELETE /boards/invite-links/{id}", authMW(httpapi.RevokeInviteLinkHandler(s.boards)))
+	mux.Handle("POST /boards/join", authMW(httpapi.JoinByInviteLinkHandler(s.boards)))
+
+	mux.Handle("POST /boards/invitations", authMW(httpapi.CreateInvitationHandler(s.invitations)))
+	mux.Handle("GET /boards/invitations", authMW(httpapi.GetBoardInvitationsHandler(s.invitations)))
+	mux.Handle("DELETE /boards/invitations/{id}", authMW(httpapi.RevokeInvitationHandler(s.invitations)))
+
+	mux.Handle("GET /invitations", authMW(httpapi.GetMyInvitationsHandler(s.invitations)))
+	mux.Handle("POST /invitations/accept", authMW(httpapi.AcceptInvitationByTokenHandler(s.invitations)))
+	mux.Handle("POST /invitations/{id}/accept", authMW(httpapi.AcceptInvitationHandler(s.invitations)))
+	mux.Handle("POST /invitations/{id}/decline", authMW(httpapi.DeclineInvitationHandler(s.invitations)))
+
+	mux.Handle("/columns", authMW(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			httpapi.CreateColumnHandler(s.columns)(w, r)
+		case http.MethodGet:
+			httpapi.GetColumnsByBoardHandler(s.columns)(w, r)
+		case http.MethodPut:
+			httpapi.UpdateColumnHandler(s.columns)(w, r)
+		case http.MethodDelete:
+			httpapi.DeleteColumnHandler(s.columns)(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	mux.Handle("/columns/move", authMW(httpapi.MoveColumnHandler(s.columns)))
+
+	mux.Handle("/tasks", authMW(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			httpapi.CreateTaskHandler(s.tasks)(w, r)
+		case http.MethodGet:
+			httpapi.GetTasksByColumnHandler(s.tasks)(w, r)
+		case http.MethodPut:
+			httpapi.UpdateTaskHandler(s.tasks)(w, r)
+		case http.MethodDelete:
+			httpapi.DeleteTaskHandler(s.tasks)(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	mux.Handle("/tasks/move", authMW(httpapi.MoveTaskHandler(s.tasks)))
+	mux.Handle("GET /tasks/{id}/assignees", authMW(httpapi.GetTaskAssigneesHandler(s.taskAssignees)))
+	mux.Handle("PUT /tasks/{id}/assignees/{user_id}", authMW(httpapi.AssignTaskHandler(s.taskAssignees)))
+	mux.Handle("DELETE /tasks/{id}/assignees/{user_id}", authMW(httpapi.UnassignTaskHandler(s.taskAssignees)))
+	mux.Handle("GET /me/tasks", authMW(httpapi.GetMyTasksHandler(s.taskAssignees)))
+	mux.Handle("GET /me/tasks/overdue", authMW(httpapi.GetMyOverdueTasksHandler(s.taskAssignees)))
+	mux.Handle("GET /me/tasks/due", authMW(httpapi.GetMyDueTasksHandler(s.taskAssignees)))
+	mux.Handle("GET /boards/{id}/tasks/overdue", authMW(httpapi.GetOverdueTasksHandler(s.tasks)))
+	mux.Handle("GET /boards/{id}/tasks/due", authMW(httpapi.GetDueTasksHandler(s.tasks)))
+	mux.Handle("GET /boards/{id}/labels", authMW(httpapi.GetLabelsHandler(s.labels)))
+	mux.Handle("POST /boards/{id}/labels", authMW(httpapi.CreateLabelHandler(s.labels)))
+	mux.Handle("PUT /labels/{id}", authMW(httpapi.UpdateLabelHandler(s.labels)))
+	mux.Handle("DELETE /labels/{id}", authMW(httpapi.DeleteLabelHandler(s.labels)))
+	mux.Handle("PUT /tasks/{id}/labels/{label_id}", authMW(httpapi.AttachLabelHandler(s.labels)))
+	mux.Handle("DELETE /tasks/{id}/labels/{label_id}", authMW(httpapi.DetachLabelHandler(s.labels)))
+	mux.Handle("GET /boards/{id}/fields", authMW(httpapi.GetCustomFieldsHandler(s.customFields)))
+	mux.Handle("POST /boards/{id}/fields", authMW(httpapi.CreateCustomFieldHandler(s.customFields)))
+	mux.Handle("PUT /fields/{id}", authMW(httpapi.UpdateCustomFieldHandler(s.customFields)))
+	mux.Handle("DELETE /fields/{id}", authMW(httpapi.DeleteCustomFieldHandler(s.customFields)))
+	mux.Handle("GET /tasks/{id}/checklists", authMW(httpapi.GetChecklistsHandler(s.checklists)))
+	mux.Handle("POST /tasks/{id}/checklists", authMW(httpapi.CreateChecklistHandler(s.checklists)))
+	mux.Handle("PUT /checklists/{id}", authMW(httpapi.RenameChecklistHandler(s.checklists)))
+	mux.Handle("DELETE /checklists/{id}", authMW(httpapi.DeleteChecklistHandler(s.checklists)))
+	mux.Handle("POST /checklists/{id}/items", authMW(httpapi.AddChecklistItemHandler(s.checklists)))
+	mux.Handle("PUT /checklists/items/{id}", authMW(httpapi.UpdateChecklistItemHandler(s.checklists)))
+	mux.Handle("DELETE /checklists/items/{id}", authMW(httpapi.DeleteChecklistItemHandler(s.checklists)))
+	mux.Handle("PUT /checklists/items/{id}/move", authMW(httpapi.MoveChecklistItemHandler(s.checklists)))
+	mux.Handle("POST /checklists/items/{id}/convert", authMW(httpapi.ConvertChecklistItemHandler(s.checklists)))
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ovk741/TasksStream/internal/service"
+)
+
+type stubOIDCService struct {
+	service.OIDCService
+}
+
+func TestRegisterRoutesHasNoConflicts(t *testing.T) {
+	mux := http.NewServeMux()
+
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Fatalf("route registration panicked: %v", r)
+			}
+		}()
+		registerRoutes(mux, nil, nil, services{oidc: stubOIDCService{}})
+	}()
+
+	tests := []struct {
+		method  string
+		path    string
+		pattern string
+	}{
+		{http.MethodGet, "/boards/b1/share/link", "GET /boards/{id}/share/link"},
+		{http.MethodDelete, "/boards/b1/share/link", "DELETE /boards/{id}/share/link"},
+		{http.MethodDelete, "/boards/invite-links/l1", "DELETE /boards/invite-links/{id}"},
+		{http.MethodDelete, "/boards/invitations/i1", "DELETE /boards/invitations/{id}"},
+		{http.MethodGet, "/auth/oidc/callback", "GET /auth/oidc/callback"},
+		{http.MethodPut, "/checklists/items/c1/move", "PUT /checklists/items/{id}/move"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		if _, pattern := mux.Handler(req); pattern != tt.pattern {
+			t.Errorf("%s %s: expected pattern %q, got %q", tt.method, tt.path, tt.pattern, pattern)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/service"
+)
+
+func ForgotPasswordHandler(accountService service.AccountService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		var input struct {
+			Email string `json:"email"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			HandleError(w, domain.ErrInvalidInput)
+			return
+		}
+
+		if err := accountService.RequestPasswordReset(input.Email); err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		// ответ одинаковый для существующих и несуществующих адресов
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+func ResetPasswordHandler(accountService service.AccountService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		var input struct {
+			Token    string `json:"token"`
+			Password string `json:"password"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			HandleError(w, domain.ErrInvalidInput)
+			return
+		}
+
+		if err := accountService.ResetPassword(input.Token, input.Password); err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func VerifyEmailHandler(accountService service.AccountService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		var input struct {
+			Token string `json:"token"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			HandleError(w, domain.ErrInvalidInput)
+			return
+		}
+
+		if err := accountService.VerifyEmail(input.Token); err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func ResendVerificationHandler(accountService service.AccountService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		var input struct {
+			Email string `json:"email"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			HandleError(w, domain.ErrInvalidInput)
+			return
+		}
+
+		if err := accountService.ResendVerification(input.Email); err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
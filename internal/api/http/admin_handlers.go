@@ -0,0 +1,163 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/service"
+)
+
+func AdminListUsersHandler(adminService service.AdminService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		limit, offset, err := pageParams(r)
+		if err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		users, err := adminService.ListUsers(userID, r.URL.Query().Get("q"), limit, offset)
+		if err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(users)
+	}
+}
+
+func AdminSetUserDisabledHandler(adminService service.AdminService, disabled bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		if err := adminService.SetUserDisabled(userID, r.PathValue("id"), disabled); err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func AdminForceLogoutHandler(adminService service.AdminService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		if err := adminService.ForceLogout(userID, r.PathValue("id")); err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func AdminListBoardsHandler(adminService service.AdminService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		limit, offset, err := pageParams(r)
+		if err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		boards, err := adminService.ListBoards(userID, limit, offset)
+		if err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(boards)
+	}
+}
+
+func AdminReassignOwnerHandler(adminService service.AdminService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		var req struct {
+			UserID string `json:"user_id"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			HandleError(w, domain.ErrInvalidInput)
+			return
+		}
+
+		if err := adminService.ReassignOwner(userID, r.PathValue("id"), req.UserID); err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// pageParams читает необязательные limit и offset; ноль означает значение по умолчанию
+func pageParams(r *http.Request) (int, int, error) {
+	var limit, offset int
+
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return 0, 0, domain.ErrInvalidInput
+		}
+		limit = n
+	}
+
+	if v := r.URL.Query().Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return 0, 0, domain.ErrInvalidInput
+		}
+		offset = n
+	}
+
+	return limit, offset, nil
+}
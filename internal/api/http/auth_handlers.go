@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/ovk741/TasksStream/internal/infra/auth"
 	"github.com/ovk741/TasksStream/internal/service"
 )
 
@@ -50,7 +51,47 @@ func LoginHandler(authService service.AuthService) http.HandlerFunc {
 			return
 		}
 
-		tokens, err := authService.Login(input.Email, input.Password)
+		result, err := authService.Login(input.Email, input.Password, GetClientInfo(r))
+		if err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if result.ChallengeToken != "" {
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"two_factor_required": true,
+				"challenge_token":     result.ChallengeToken,
+			})
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"access_token":  result.Tokens.AccessToken,
+			"refresh_token": result.Tokens.RefreshToken,
+		})
+	}
+}
+
+func LoginTwoFactorHandler(authService service.AuthService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		var input struct {
+			ChallengeToken string `json:"challenge_token"`
+			Code           string `json:"code"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			SendError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		tokens, err := authService.LoginTwoFactor(input.ChallengeToken, input.Code, GetClientInfo(r))
 		if err != nil {
 			HandleError(w, err)
 			return
@@ -80,7 +121,7 @@ func RefreshHandler(authService service.AuthService) http.HandlerFunc {
 			return
 		}
 
-		tokens, err := authService.Refresh(input.RefreshToken)
+		tokens, err := authService.Refresh(input.RefreshToken, GetClientInfo(r))
 		if err != nil {
 			HandleError(w, err)
 			return
@@ -93,3 +134,110 @@ func RefreshHandler(authService service.AuthService) http.HandlerFunc {
 		})
 	}
 }
+
+func LogoutHandler(authService service.AuthService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		var input struct {
+			RefreshToken string `json:"refresh_token"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			SendError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := authService.Logout(input.RefreshToken); err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func LogoutAllHandler(authService service.AuthService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		if err := authService.LogoutAll(userID); err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func GetSessionsHandler(authService service.AuthService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		sessions, err := authService.ListSessions(userID)
+		if err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(sessions)
+	}
+}
+
+func RevokeSessionHandler(authService service.AuthService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		if err := authService.RevokeSession(userID, r.PathValue("id")); err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+type JWKSProvider interface {
+	JWKS() auth.JWKSet
+}
+
+func JWKSHandler(keys JWKSProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		_ = json.NewEncoder(w).Encode(keys.JWKS())
+	}
+}
@@ -1,9 +1,12 @@
 package httpapi
 
 import (
+	"net"
 	"net/http"
 
 	"github.com/ovk741/TasksStream/internal/api/http/middleware"
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/service"
 )
 
 func GetUserID(r *http.Request) (string, bool) {
@@ -19,3 +22,80 @@ func MustGetUserID(w http.ResponseWriter, r *http.Request) (string, bool) {
 	}
 	return userID, true
 }
+
+func GetPrincipal(r *http.Request) domain.Principal {
+	principal, ok := r.Context().Value(middleware.PrincipalKey).(domain.Principal)
+	if !ok {
+		userID, _ := GetUserID(r)
+		return domain.Principal{UserID: userID}
+	}
+	return principal
+}
+
+func scopedBoardService(r *http.Request, boardService service.BoardService) service.BoardService {
+	return service.NewScopedBoardService(boardService, GetPrincipal(r))
+}
+
+func scopedColumnService(r *http.Request, columnService service.ColumnService) service.ColumnService {
+	return service.NewScopedColumnService(columnService, GetPrincipal(r))
+}
+
+func scopedTaskService(r *http.Request, taskService service.TaskService) service.TaskService {
+	return service.NewScopedTaskService(taskService, GetPrincipal(r))
+}
+
+func scopedTaskAssigneeService(r *http.Request, taskAssigneeService service.TaskAssigneeService) service.TaskAssigneeService {
+	return service.NewScopedTaskAssigneeService(taskAssigneeService, GetPrincipal(r))
+}
+
+func scopedLabelService(r *http.Request, labelService service.LabelService) service.LabelService {
+	return service.NewScopedLabelService(labelService, GetPrincipal(r))
+}
+
+func scopedCustomFieldService(r *http.Request, customFieldService service.CustomFieldService) service.CustomFieldService {
+	return service.NewScopedCustomFieldService(customFieldService, GetPrincipal(r))
+}
+
+func scopedChecklistService(r *http.Request, checklistService service.ChecklistService) service.ChecklistService {
+	return service.NewScopedChecklistService(checklistService, GetPrincipal(r))
+}
+
+func scopedEventService(r *http.Request, eventService service.EventService) service.EventService {
+	return service.NewScopedEventService(eventService, GetPrincipal(r))
+}
+
+func scopedUserService(r *http.Request, userService service.UserService) service.UserService {
+	return service.NewScopedUserService(userService, GetPrincipal(r))
+}
+
+func scopedBoardRoleService(r *http.Request, boardRoleService service.BoardRoleService) service.BoardRoleService {
+	return service.NewScopedBoardRoleService(boardRoleService, GetPrincipal(r))
+}
+
+func scopedWorkspaceService(r *http.Request, workspaceService service.WorkspaceService) service.WorkspaceService {
+	return service.NewScopedWorkspaceService(workspaceService, GetPrincipal(r))
+}
+
+func scopedTeamService(r *http.Request, teamService service.TeamService) service.TeamService {
+	return service.NewScopedTeamService(teamService, GetPrincipal(r))
+}
+
+func scopedBoardShareService(r *http.Request, boardShareService service.BoardShareService) service.BoardShareService {
+	return service.NewScopedBoardShareService(boardShareService, GetPrincipal(r))
+}
+
+func scopedInvitationService(r *http.Request, invitationService service.InvitationService) service.InvitationService {
+	return service.NewScopedInvitationService(invitationService, GetPrincipal(r))
+}
+
+func GetClientInfo(r *http.Request) service.ClientInfo {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	return service.ClientInfo{
+		UserAgent: r.UserAgent(),
+		IP:        ip,
+	}
+}
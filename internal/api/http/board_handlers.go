@@ -3,6 +3,7 @@ package httpapi
 import (
 	"encoding/json"
 	"net/http"
+	"time"
 
 	"github.com/ovk741/TasksStream/internal/domain"
 	"github.com/ovk741/TasksStream/internal/service"
@@ -29,7 +30,7 @@ func CreateBoardHandler(boardService service.BoardService) http.HandlerFunc {
 			return
 		}
 
-		board, err := boardService.Create(userID, input.Name)
+		board, err := scopedBoardService(r, boardService).Create(userID, input.Name)
 		if err != nil {
 			HandleError(w, err)
 			return
@@ -53,7 +54,7 @@ func GetBoardsHandler(boardService service.BoardService) http.HandlerFunc {
 			return
 		}
 
-		boards, err := boardService.GetAll(userID)
+		boards, err := scopedBoardService(r, boardService).GetAll(userID)
 		if err != nil {
 			HandleError(w, err)
 			return
@@ -92,7 +93,7 @@ func UpdateBoardHandler(boardService service.BoardService) http.HandlerFunc {
 			return
 		}
 
-		board, err := boardService.Update(userID, boardID, input.Name)
+		board, err := scopedBoardService(r, boardService).Update(userID, boardID, input.Name)
 		if err != nil {
 			HandleError(w, err)
 			return
@@ -119,7 +120,7 @@ func DeleteBoardHandler(boardService service.BoardService) http.HandlerFunc {
 			return
 		}
 
-		err := boardService.Delete(userID, boardID)
+		err := scopedBoardService(r, boardService).Delete(userID, boardID)
 		if err != nil {
 			HandleError(w, err)
 			return
@@ -154,7 +155,7 @@ func InviteToBoardHandler(boardService service.BoardService) http.HandlerFunc {
 			return
 		}
 
-		if err := boardService.InviteUser(
+		if err := scopedBoardService(r, boardService).InviteUser(
 			userID,
 			input.BoardID,
 			input.UserID,
@@ -190,7 +191,7 @@ func GetBoardMembersHandler(boardService service.BoardService) http.HandlerFunc
 			return
 		}
 
-		members, err := boardService.GetMembers(userID, boardID)
+		members, err := scopedBoardService(r, boardService).GetMembers(userID, boardID)
 		if err != nil {
 			HandleError(w, err)
 			return
@@ -229,7 +230,7 @@ func RemoveBoardMemberHandler(boardService service.BoardService) http.HandlerFun
 			return
 		}
 
-		if err := boardService.RemoveUser(requesterID, input.BoardID, input.UserID); err != nil {
+		if err := scopedBoardService(r, boardService).RemoveUser(requesterID, input.BoardID, input.UserID); err != nil {
 			HandleError(w, err)
 			return
 		}
@@ -237,3 +238,233 @@ func RemoveBoardMemberHandler(boardService service.BoardService) http.HandlerFun
 		w.WriteHeader(http.StatusNoContent)
 	}
 }
+
+func ChangeMemberRoleHandler(boardService service.BoardService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		var input struct {
+			BoardID string           `json:"board_id"`
+			UserID  string           `json:"user_id"`
+			Role    domain.BoardRole `json:"role"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			HandleError(w, domain.ErrInvalidInput)
+			return
+		}
+
+		if err := scopedBoardService(r, boardService).ChangeMemberRole(
+			userID,
+			input.BoardID,
+			input.UserID,
+			input.Role,
+		); err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TransferOwnershipHandler(boardService service.BoardService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		var input struct {
+			BoardID string `json:"board_id"`
+			UserID  string `json:"user_id"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			HandleError(w, domain.ErrInvalidInput)
+			return
+		}
+
+		if err := scopedBoardService(r, boardService).TransferOwnership(userID, input.BoardID, input.UserID); err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func LeaveBoardHandler(boardService service.BoardService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		var input struct {
+			BoardID string `json:"board_id"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			HandleError(w, domain.ErrInvalidInput)
+			return
+		}
+
+		if err := scopedBoardService(r, boardService).Leave(userID, input.BoardID); err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func CreateInviteLinkHandler(boardService service.BoardService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		var input struct {
+			BoardID       string           `json:"board_id"`
+			Role          domain.BoardRole `json:"role"`
+			MaxUses       *int             `json:"max_uses"`
+			ExpiresInDays int              `json:"expires_in_days"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			HandleError(w, domain.ErrInvalidInput)
+			return
+		}
+
+		if input.ExpiresInDays < 0 {
+			HandleError(w, domain.ErrInvalidInput)
+			return
+		}
+
+		var expiresAt *time.Time
+		if input.ExpiresInDays > 0 {
+			t := time.Now().AddDate(0, 0, input.ExpiresInDays)
+			expiresAt = &t
+		}
+
+		link, token, err := scopedBoardService(r, boardService).CreateInviteLink(
+			userID,
+			input.BoardID,
+			input.Role,
+			input.MaxUses,
+			expiresAt,
+		)
+		if err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(struct {
+			domain.BoardInviteLink
+			Token string `json:"token"`
+		}{link, token})
+	}
+}
+
+func GetInviteLinksHandler(boardService service.BoardService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		links, err := scopedBoardService(r, boardService).GetInviteLinks(userID, r.URL.Query().Get("board_id"))
+		if err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(links)
+	}
+}
+
+func RevokeInviteLinkHandler(boardService service.BoardService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		if err := scopedBoardService(r, boardService).RevokeInviteLink(userID, r.PathValue("id")); err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func JoinByInviteLinkHandler(boardService service.BoardService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		var input struct {
+			Token string `json:"token"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			HandleError(w, domain.ErrInvalidInput)
+			return
+		}
+
+		board, err := scopedBoardService(r, boardService).JoinByInviteLink(userID, input.Token)
+		if err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(board)
+	}
+}
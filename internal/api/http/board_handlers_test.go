@@ -11,6 +11,7 @@ import (
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/infra/events"
 	"github.com/ovk741/TasksStream/internal/service"
 	"github.com/ovk741/TasksStream/internal/storage/postgres"
 )
@@ -28,8 +29,9 @@ func TestCreateBoardHandler(t *testing.T) {
 	columnRepo := postgres.NewColumnRepository(pool)
 	taskRepo := postgres.NewTaskRepository(pool)
 	boardMemberRepo := postgres.NewBoardMemberRepository(pool)
+	inviteLinkRepo := postgres.NewBoardInviteLinkRepository(pool)
 
-	boardService := service.NewBoardService(boardRepo, columnRepo, taskRepo, boardMemberRepo, func() string {
+	boardService := service.NewBoardService(boardRepo, columnRepo, taskRepo, boardMemberRepo, postgres.NewTaskAssigneeRepository(pool), service.NewBoardPolicy(boardMemberRepo, postgres.NewBoardRoleRepository(pool), postgres.NewWorkspaceMemberRepository(pool)), inviteLinkRepo, events.NewBroker(), func() string {
 		return "new-id"
 	})
 
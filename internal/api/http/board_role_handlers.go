@@ -0,0 +1,128 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/service"
+)
+
+func GetBoardRolesHandler(boardRoleService service.BoardRoleService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		roles, err := scopedBoardRoleService(r, boardRoleService).List(userID, r.PathValue("id"))
+		if err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(roles)
+	}
+}
+
+func CreateBoardRoleHandler(boardRoleService service.BoardRoleService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		var input struct {
+			Name        domain.BoardRole `json:"name"`
+			Permissions []domain.Action  `json:"permissions"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			HandleError(w, domain.ErrInvalidInput)
+			return
+		}
+
+		role, err := scopedBoardRoleService(r, boardRoleService).Create(userID, r.PathValue("id"), input.Name, input.Permissions)
+		if err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(role)
+	}
+}
+
+func UpdateBoardRoleHandler(boardRoleService service.BoardRoleService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		var input struct {
+			Permissions []domain.Action `json:"permissions"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			HandleError(w, domain.ErrInvalidInput)
+			return
+		}
+
+		role, err := scopedBoardRoleService(r, boardRoleService).Update(
+			userID,
+			r.PathValue("id"),
+			domain.BoardRole(r.PathValue("name")),
+			input.Permissions,
+		)
+		if err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(role)
+	}
+}
+
+func DeleteBoardRoleHandler(boardRoleService service.BoardRoleService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		if err := scopedBoardRoleService(r, boardRoleService).Delete(
+			userID,
+			r.PathValue("id"),
+			domain.BoardRole(r.PathValue("name")),
+		); err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,103 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/service"
+)
+
+func GetBoardShareHandler(boardShareService service.BoardShareService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		share, err := scopedBoardShareService(r, boardShareService).Get(userID, r.PathValue("id"))
+		if err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(share)
+	}
+}
+
+func EnableBoardShareHandler(boardShareService service.BoardShareService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		share, token, err := scopedBoardShareService(r, boardShareService).Enable(userID, r.PathValue("id"))
+		if err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(struct {
+			domain.BoardShare
+			Token string `json:"token"`
+		}{share, token})
+	}
+}
+
+func DisableBoardShareHandler(boardShareService service.BoardShareService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		if err := scopedBoardShareService(r, boardShareService).Disable(userID, r.PathValue("id")); err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// PublicBoardHandler отдаёт опубликованную доску без аутентификации
+func PublicBoardHandler(boardShareService service.BoardShareService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		board, err := boardShareService.GetPublicBoard(r.PathValue("token"))
+		if err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		// после отключения или смены токена доска не должна оставаться в кэшах,
+		// а токен — утекать через Referer
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Referrer-Policy", "no-referrer")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(board)
+	}
+}
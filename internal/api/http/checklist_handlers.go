@@ -0,0 +1,266 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/service"
+)
+
+type checklistTitleInput struct {
+	Title string `json:"title"`
+}
+
+func GetChecklistsHandler(checklistService service.ChecklistService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		checklists, err := scopedChecklistService(r, checklistService).List(userID, r.PathValue("id"))
+		if err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(checklists)
+	}
+}
+
+func CreateChecklistHandler(checklistService service.ChecklistService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		var input checklistTitleInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			HandleError(w, domain.ErrInvalidInput)
+			return
+		}
+
+		checklist, err := scopedChecklistService(r, checklistService).Create(userID, r.PathValue("id"), input.Title)
+		if err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(checklist)
+	}
+}
+
+func RenameChecklistHandler(checklistService service.ChecklistService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		var input checklistTitleInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			HandleError(w, domain.ErrInvalidInput)
+			return
+		}
+
+		checklist, err := scopedChecklistService(r, checklistService).Rename(userID, r.PathValue("id"), input.Title)
+		if err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(checklist)
+	}
+}
+
+func DeleteChecklistHandler(checklistService service.ChecklistService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		if err := scopedChecklistService(r, checklistService).Delete(userID, r.PathValue("id")); err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func AddChecklistItemHandler(checklistService service.ChecklistService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		var input checklistTitleInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			HandleError(w, domain.ErrInvalidInput)
+			return
+		}
+
+		item, err := scopedChecklistService(r, checklistService).AddItem(userID, r.PathValue("id"), input.Title)
+		if err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(item)
+	}
+}
+
+func UpdateChecklistItemHandler(checklistService service.ChecklistService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		var input struct {
+			Title string `json:"title"`
+			Done  bool   `json:"done"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			HandleError(w, domain.ErrInvalidInput)
+			return
+		}
+
+		item, err := scopedChecklistService(r, checklistService).UpdateItem(userID, r.PathValue("id"), input.Title, input.Done)
+		if err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(item)
+	}
+}
+
+func MoveChecklistItemHandler(checklistService service.ChecklistService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		var input struct {
+			ChecklistID string `json:"checklist_id"`
+			Position    int    `json:"position"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			HandleError(w, domain.ErrInvalidInput)
+			return
+		}
+
+		item, err := scopedChecklistService(r, checklistService).MoveItem(userID, r.PathValue("id"), input.ChecklistID, input.Position)
+		if err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(item)
+	}
+}
+
+func DeleteChecklistItemHandler(checklistService service.ChecklistService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		if err := scopedChecklistService(r, checklistService).DeleteItem(userID, r.PathValue("id")); err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func ConvertChecklistItemHandler(checklistService service.ChecklistService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		var input struct {
+			ColumnID string `json:"column_id"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			HandleError(w, domain.ErrInvalidInput)
+			return
+		}
+
+		task, err := scopedChecklistService(r, checklistService).ConvertItem(userID, r.PathValue("id"), input.ColumnID)
+		if err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(task)
+	}
+}
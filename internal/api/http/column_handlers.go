@@ -33,7 +33,7 @@ func CreateColumnHandler(columnService service.ColumnService) http.HandlerFunc {
 			return
 		}
 
-		column, err := columnService.Create(userID, input.Title, input.BoardID)
+		column, err := scopedColumnService(r, columnService).Create(userID, input.Title, input.BoardID)
 		if err != nil {
 			HandleError(w, err)
 			return
@@ -64,7 +64,7 @@ func GetColumnsByBoardHandler(columnService service.ColumnService) http.HandlerF
 			return
 		}
 
-		columns, err := columnService.GetByBoardID(userID, boardID)
+		columns, err := scopedColumnService(r, columnService).GetByBoardID(userID, boardID)
 		if err != nil {
 			HandleError(w, err)
 			return
@@ -103,7 +103,7 @@ func UpdateColumnHandler(columnService service.ColumnService) http.HandlerFunc {
 			return
 		}
 
-		column, err := columnService.Update(userID, columnID, input.Title)
+		column, err := scopedColumnService(r, columnService).Update(userID, columnID, input.Title)
 		if err != nil {
 			HandleError(w, err)
 			return
@@ -131,7 +131,7 @@ func DeleteColumnHandler(columnService service.ColumnService) http.HandlerFunc {
 			w.WriteHeader(http.StatusBadRequest)
 			return
 		}
-		err := columnService.Delete(userID, columnID)
+		err := scopedColumnService(r, columnService).Delete(userID, columnID)
 		if err != nil {
 			HandleError(w, err)
 			return
@@ -169,7 +169,7 @@ func MoveColumnHandler(columnService service.ColumnService) http.HandlerFunc {
 			return
 		}
 
-		column, err := columnService.Move(userID, columnID, input.Position)
+		column, err := scopedColumnService(r, columnService).Move(userID, columnID, input.Position)
 		if err != nil {
 			HandleError(w, err)
 			return
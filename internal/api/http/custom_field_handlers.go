@@ -0,0 +1,121 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/service"
+)
+
+func GetCustomFieldsHandler(customFieldService service.CustomFieldService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		fields, err := scopedCustomFieldService(r, customFieldService).List(userID, r.PathValue("id"))
+		if err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(fields)
+	}
+}
+
+func CreateCustomFieldHandler(customFieldService service.CustomFieldService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		var input struct {
+			Name    string                 `json:"name"`
+			Type    domain.CustomFieldType `json:"type"`
+			Options []string               `json:"options"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			HandleError(w, domain.ErrInvalidInput)
+			return
+		}
+
+		field, err := scopedCustomFieldService(r, customFieldService).Create(userID, r.PathValue("id"), input.Name, input.Type, input.Options)
+		if err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(field)
+	}
+}
+
+func UpdateCustomFieldHandler(customFieldService service.CustomFieldService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		var input struct {
+			Name    string   `json:"name"`
+			Options []string `json:"options"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			HandleError(w, domain.ErrInvalidInput)
+			return
+		}
+
+		field, err := scopedCustomFieldService(r, customFieldService).Update(userID, r.PathValue("id"), input.Name, input.Options)
+		if err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(field)
+	}
+}
+
+func DeleteCustomFieldHandler(customFieldService service.CustomFieldService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		if err := scopedCustomFieldService(r, customFieldService).Delete(userID, r.PathValue("id")); err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
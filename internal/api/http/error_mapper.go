@@ -3,7 +3,9 @@ package httpapi
 import (
 	"errors"
 	"log"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/ovk741/TasksStream/internal/domain"
 )
@@ -19,9 +21,19 @@ func HandleError(w http.ResponseWriter, err error) {
 	case errors.Is(err, domain.ErrInvalidCredentials):
 		SendError(w, http.StatusUnauthorized, err)
 
-	case errors.Is(err, domain.ErrForbidden):
+	case errors.Is(err, domain.ErrForbidden),
+		errors.Is(err, domain.ErrEmailNotVerified),
+		errors.Is(err, domain.ErrUserDisabled):
 		SendError(w, http.StatusForbidden, err)
 
+	case errors.Is(err, domain.ErrTooManyRequests):
+		var rateLimit *domain.RateLimitError
+		if errors.As(err, &rateLimit) {
+			seconds := int(math.Ceil(rateLimit.RetryAfter.Seconds()))
+			w.Header().Set("Retry-After", strconv.Itoa(seconds))
+		}
+		SendError(w, http.StatusTooManyRequests, err)
+
 	case errors.Is(err, domain.ErrUserAlreadyExists),
 		errors.Is(err, domain.ErrConflict):
 		SendError(w, http.StatusConflict, err)
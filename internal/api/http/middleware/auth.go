@@ -2,44 +2,89 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strings"
+
+	"github.com/ovk741/TasksStream/internal/domain"
 )
 
 type contextKey string
 
-const UserIDKey contextKey = "userID"
+const (
+	UserIDKey    contextKey = "userID"
+	PrincipalKey contextKey = "principal"
+)
 
-type AccessTokenParser interface {
-	ParseAccessToken(token string) (string, error)
+type Authenticator interface {
+	Authenticate(token string) (domain.Principal, error)
 }
 
-func AuthMiddleware(jwt AccessTokenParser) func(http.Handler) http.Handler {
+func AuthMiddleware(authenticator Authenticator) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
-			authHeader := r.Header.Get("Authorization")
-			if authHeader == "" {
-				http.Error(w, "missing authorization header", http.StatusUnauthorized)
+			token, ok := bearerToken(w, r)
+			if !ok {
 				return
 			}
 
-			const prefix = "Bearer "
-			if !strings.HasPrefix(authHeader, prefix) {
-				http.Error(w, "invalid authorization header", http.StatusUnauthorized)
+			principal, err := authenticator.Authenticate(token)
+			if errors.Is(err, domain.ErrUserDisabled) {
+				http.Error(w, "account disabled", http.StatusForbidden)
 				return
 			}
-
-			token := strings.TrimPrefix(authHeader, prefix)
-
-			userID, err := jwt.ParseAccessToken(token)
 			if err != nil {
 				http.Error(w, "invalid token", http.StatusUnauthorized)
 				return
 			}
 
-			ctx := context.WithValue(r.Context(), UserIDKey, userID)
+			ctx := context.WithValue(r.Context(), UserIDKey, principal.UserID)
+			ctx = context.WithValue(ctx, PrincipalKey, principal)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
+
+// RequireSessionToken пропускает только запросы с обычным JWT: управлять
+// сессиями и персональными токенами с помощью персонального токена нельзя.
+func RequireSessionToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, ok := r.Context().Value(PrincipalKey).(domain.Principal)
+		if !ok || principal.Scopes != nil {
+			http.Error(w, "personal access tokens are not allowed here", http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func bearerToken(w http.ResponseWriter, r *http.Request) (string, bool) {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		// браузерные WebSocket и EventSource не умеют передавать заголовки,
+		// поэтому для потоковых запросов токен можно передать в query
+		if isStreamRequest(r) {
+			if token := r.URL.Query().Get("access_token"); token != "" {
+				return token, true
+			}
+		}
+
+		http.Error(w, "missing authorization header", http.StatusUnauthorized)
+		return "", false
+	}
+
+	const prefix = "Bearer "
+	if !strings.HasPrefix(authHeader, prefix) {
+		http.Error(w, "invalid authorization header", http.StatusUnauthorized)
+		return "", false
+	}
+
+	return strings.TrimPrefix(authHeader, prefix), true
+}
+
+func isStreamRequest(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket") ||
+		strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// RealIP подставляет в RemoteAddr адрес клиента из заголовков прокси.
+// Включать только если сервис стоит за доверенным reverse proxy,
+// иначе клиент сможет подделать свой адрес.
+//
+// trustedHops — сколько доверенных прокси стоит перед сервисом. Каждый прокси
+// дописывает адрес собеседника в конец X-Forwarded-For, поэтому адрес клиента
+// берётся на trustedHops позиций от правого края: всё левее клиент мог
+// прислать сам.
+func RealIP(trustedHops int) func(http.Handler) http.Handler {
+	if trustedHops < 1 {
+		trustedHops = 1
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := r.Header.Get("X-Real-IP")
+
+			// заголовок может прийти несколькими строками, порядок сохраняется
+			var hops []string
+			for _, value := range r.Header.Values("X-Forwarded-For") {
+				hops = append(hops, strings.Split(value, ",")...)
+			}
+			if len(hops) >= trustedHops {
+				ip = hops[len(hops)-trustedHops]
+			}
+
+			ip = strings.TrimSpace(ip)
+			if net.ParseIP(ip) != nil {
+				r.RemoteAddr = net.JoinHostPort(ip, "0")
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRealIP(t *testing.T) {
+	cases := []struct {
+		name      string
+		hops      int
+		forwarded []string
+		realIP    string
+		want      string
+	}{
+		{"no headers", 1, nil, "", "192.0.2.1:1234"},
+		{"single proxy", 1, []string{"203.0.113.7"}, "", "203.0.113.7:0"},
+		{"spoofed entry is ignored", 1, []string{"10.6.6.6, 203.0.113.7"}, "", "203.0.113.7:0"},
+		{"two proxies", 2, []string{"10.6.6.6, 203.0.113.7, 172.16.0.2"}, "", "203.0.113.7:0"},
+		{"split header", 2, []string{"10.6.6.6, 203.0.113.7", "172.16.0.2"}, "", "203.0.113.7:0"},
+		{"fewer entries than hops", 2, []string{"203.0.113.7"}, "198.51.100.4", "198.51.100.4:0"},
+		{"x-real-ip", 1, nil, "198.51.100.4", "198.51.100.4:0"},
+		{"garbage", 1, []string{"not-an-ip"}, "", "192.0.2.1:1234"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got string
+			handler := RealIP(tc.hops)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.RemoteAddr
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = "192.0.2.1:1234"
+			for _, value := range tc.forwarded {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+			if tc.realIP != "" {
+				req.Header.Set("X-Real-IP", tc.realIP)
+			}
+
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if got != tc.want {
+				t.Errorf("expected %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
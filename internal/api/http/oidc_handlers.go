@@ -0,0 +1,97 @@
+package httpapi
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/service"
+)
+
+// state дублируется в cookie: callback принимается только в том браузере,
+// где вход был начат, иначе чужой code можно было бы подсунуть жертве
+const oidcStateCookie = "oidc_state"
+
+// stateCookie собирает cookie со state. Удаляющая cookie должна совпадать
+// с исходной по атрибутам, поэтому обе создаются здесь. secure задаётся
+// конфигурацией, а не r.TLS: за прокси, снимающим TLS, r.TLS всегда nil.
+func stateCookie(value string, maxAge int, secure bool) *http.Cookie {
+	return &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    value,
+		Path:     "/auth/oidc",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+func OIDCLoginHandler(oidcService service.OIDCService, secureCookie bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		redirectURL, state, err := oidcService.Begin()
+		if err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		http.SetCookie(w, stateCookie(state, 600, secureCookie))
+
+		http.Redirect(w, r, redirectURL, http.StatusFound)
+	}
+}
+
+func OIDCCallbackHandler(oidcService service.OIDCService, secureCookie bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		query := r.URL.Query()
+
+		if providerErr := query.Get("error"); providerErr != "" {
+			HandleError(w, fmt.Errorf("%w: %s", domain.ErrInvalidCredentials, providerErr))
+			return
+		}
+
+		state := query.Get("state")
+
+		cookie, err := r.Cookie(oidcStateCookie)
+		if err != nil || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(state)) != 1 {
+			HandleError(w, domain.ErrInvalidCredentials)
+			return
+		}
+
+		http.SetCookie(w, stateCookie("", -1, secureCookie))
+
+		result, err := oidcService.Complete(state, query.Get("code"), GetClientInfo(r))
+		if err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		// 2FA проверяется так же, как при входе по паролю: через /auth/login/2fa
+		if result.ChallengeToken != "" {
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"two_factor_required": true,
+				"challenge_token":     result.ChallengeToken,
+			})
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"access_token":  result.Tokens.AccessToken,
+			"refresh_token": result.Tokens.RefreshToken,
+		})
+	}
+}
@@ -0,0 +1,310 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/infra/oidc"
+	"github.com/ovk741/TasksStream/internal/infra/oidc/oidctest"
+	"github.com/ovk741/TasksStream/internal/service"
+	"github.com/ovk741/TasksStream/internal/storage"
+)
+
+type oidcTestStore struct {
+	storage.UserRepository
+
+	mu         sync.Mutex
+	states     map[string]domain.OIDCState
+	identities map[string]domain.UserIdentity
+	users      map[string]domain.User
+}
+
+func newOIDCTestStore() *oidcTestStore {
+	return &oidcTestStore{
+		states:     make(map[string]domain.OIDCState),
+		identities: make(map[string]domain.UserIdentity),
+		users:      make(map[string]domain.User),
+	}
+}
+
+type oidcStateStore struct{ *oidcTestStore }
+
+func (s oidcStateStore) Create(state domain.OIDCState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states[state.ID] = state
+	return nil
+}
+
+func (s oidcStateStore) Consume(id string, now time.Time) (domain.OIDCState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.states[id]
+	delete(s.states, id)
+	if !ok || !state.ExpiresAt.After(now) {
+		return domain.OIDCState{}, domain.ErrNotFound
+	}
+	return state, nil
+}
+
+type oidcIdentityStore struct{ *oidcTestStore }
+
+func (s oidcIdentityStore) Create(identity domain.UserIdentity) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.identities[identity.Provider+"|"+identity.Subject] = identity
+	return nil
+}
+
+func (s oidcIdentityStore) Get(provider, subject string) (domain.UserIdentity, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	identity, ok := s.identities[provider+"|"+subject]
+	if !ok {
+		return domain.UserIdentity{}, domain.ErrNotFound
+	}
+	return identity, nil
+}
+
+func (s *oidcTestStore) Create(user domain.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[user.ID] = user
+	return nil
+}
+
+func (s *oidcTestStore) GetByID(id string) (domain.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user, ok := s.users[id]
+	if !ok {
+		return domain.User{}, domain.ErrNotFound
+	}
+	return user, nil
+}
+
+func (s *oidcTestStore) GetByEmail(email string) (domain.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, user := range s.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return domain.User{}, domain.ErrNotFound
+}
+
+type noopInvitationClaimer struct{}
+
+func (noopInvitationClaimer) ClaimPending(domain.User) error { return nil }
+
+type recordingClaimer struct{ claimed []string }
+
+func (c *recordingClaimer) ClaimPending(user domain.User) error {
+	c.claimed = append(c.claimed, user.ID)
+	return nil
+}
+
+type sessionStarterFunc func(user domain.User, client service.ClientInfo) (service.LoginResult, error)
+
+func (f sessionStarterFunc) StartSession(user domain.User, client service.ClientInfo) (service.LoginResult, error) {
+	return f(user, client)
+}
+
+func TestOIDCLoginAgainstStubProvider(t *testing.T) {
+	provider := oidctest.NewProvider(t, "tasksstream")
+	provider.SetUser(oidctest.Identity{Subject: "sub-1", Email: "user@example.com", EmailVerified: true})
+
+	store := newOIDCTestStore()
+	store.users["existing"] = domain.User{ID: "existing", Email: "user@example.com", EmailVerified: true}
+
+	var startedFor string
+	sessions := sessionStarterFunc(func(user domain.User, client service.ClientInfo) (service.LoginResult, error) {
+		startedFor = user.ID
+		return service.LoginResult{Tokens: service.Tokens{AccessToken: "access", RefreshToken: "refresh"}}, nil
+	})
+
+	oidcService := newTestOIDCService(provider, store, sessions, noopInvitationClaimer{})
+
+	login := httptest.NewRecorder()
+	OIDCLoginHandler(oidcService, false).ServeHTTP(login, httptest.NewRequest(http.MethodGet, "/auth/oidc/login", nil))
+
+	if login.Code != http.StatusFound {
+		t.Fatalf("expected redirect, got %d", login.Code)
+	}
+
+	callbackURL, err := provider.Authorize(login.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// без cookie из того же браузера callback не принимается
+	foreign := httptest.NewRecorder()
+	OIDCCallbackHandler(oidcService, false).ServeHTTP(foreign, httptest.NewRequest(http.MethodGet, callbackURL.String(), nil))
+	if foreign.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without state cookie, got %d", foreign.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, callbackURL.String(), nil)
+	for _, cookie := range login.Result().Cookies() {
+		req.AddCookie(cookie)
+	}
+
+	callback := httptest.NewRecorder()
+	OIDCCallbackHandler(oidcService, false).ServeHTTP(callback, req)
+
+	if callback.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", callback.Code, callback.Body.String())
+	}
+
+	var tokens map[string]string
+	if err := json.NewDecoder(callback.Body).Decode(&tokens); err != nil {
+		t.Fatal(err)
+	}
+	if tokens["access_token"] != "access" {
+		t.Errorf("unexpected tokens: %v", tokens)
+	}
+
+	// подтверждённый адрес привязывается к существующему пользователю
+	if startedFor != "existing" {
+		t.Errorf("expected session for existing user, got %q", startedFor)
+	}
+	if _, err := (oidcIdentityStore{store}).Get(provider.Issuer(), "sub-1"); err != nil {
+		t.Errorf("expected identity to be linked: %v", err)
+	}
+}
+
+// completeOIDCLogin проходит вход у провайдера и вызывает Complete напрямую, без cookie
+func completeOIDCLogin(t *testing.T, provider *oidctest.Provider, oidcService service.OIDCService) (service.LoginResult, error) {
+	t.Helper()
+
+	redirectURL, state, err := oidcService.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	callbackURL, err := provider.Authorize(redirectURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return oidcService.Complete(state, callbackURL.Query().Get("code"), service.ClientInfo{})
+}
+
+func newTestOIDCService(
+	provider *oidctest.Provider,
+	store *oidcTestStore,
+	sessions service.SessionStarter,
+	invitations service.InvitationClaimer,
+) service.OIDCService {
+	client := oidc.NewClient(provider.Issuer(), "tasksstream", "", "http://localhost/auth/oidc/callback", nil)
+	return service.NewOIDCService(
+		client,
+		oidcStateStore{store},
+		oidcIdentityStore{store},
+		store,
+		sessions,
+		invitations,
+		func() string { return "new-user" },
+	)
+}
+
+func TestOIDCLoginRejectsUnverifiedEmail(t *testing.T) {
+	provider := oidctest.NewProvider(t, "tasksstream")
+	provider.SetUser(oidctest.Identity{Subject: "sub-2", Email: "user@example.com", EmailVerified: false})
+
+	store := newOIDCTestStore()
+	store.users["existing"] = domain.User{ID: "existing", Email: "user@example.com", EmailVerified: true}
+
+	oidcService := newTestOIDCService(provider, store, sessionStarterFunc(func(domain.User, service.ClientInfo) (service.LoginResult, error) {
+		t.Error("session must not be started")
+		return service.LoginResult{}, nil
+	}), noopInvitationClaimer{})
+
+	if _, err := completeOIDCLogin(t, provider, oidcService); !errors.Is(err, domain.ErrEmailNotVerified) {
+		t.Fatalf("expected ErrEmailNotVerified, got %v", err)
+	}
+}
+
+func TestOIDCLoginDoesNotLinkUnverifiedAccount(t *testing.T) {
+	provider := oidctest.NewProvider(t, "tasksstream")
+	provider.SetUser(oidctest.Identity{Subject: "sub-3", Email: "user@example.com", EmailVerified: true})
+
+	// аккаунт с чужим адресом мог завести кто угодно, не владея этим адресом
+	store := newOIDCTestStore()
+	store.users["squatter"] = domain.User{ID: "squatter", Email: "user@example.com", PasswordHash: "hash"}
+
+	oidcService := newTestOIDCService(provider, store, sessionStarterFunc(func(domain.User, service.ClientInfo) (service.LoginResult, error) {
+		t.Error("session must not be started")
+		return service.LoginResult{}, nil
+	}), noopInvitationClaimer{})
+
+	if _, err := completeOIDCLogin(t, provider, oidcService); !errors.Is(err, domain.ErrEmailNotVerified) {
+		t.Fatalf("expected ErrEmailNotVerified, got %v", err)
+	}
+	if _, err := (oidcIdentityStore{store}).Get(provider.Issuer(), "sub-3"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("identity must not be linked, got %v", err)
+	}
+}
+
+func TestOIDCLoginClaimsInvitationsOfLinkedAccount(t *testing.T) {
+	provider := oidctest.NewProvider(t, "tasksstream")
+	provider.SetUser(oidctest.Identity{Subject: "sub-4", Email: "user@example.com", EmailVerified: true})
+
+	store := newOIDCTestStore()
+	store.users["existing"] = domain.User{ID: "existing", Email: "user@example.com", EmailVerified: true}
+
+	claimer := &recordingClaimer{}
+	oidcService := newTestOIDCService(provider, store, sessionStarterFunc(func(domain.User, service.ClientInfo) (service.LoginResult, error) {
+		return service.LoginResult{}, nil
+	}), claimer)
+
+	if _, err := completeOIDCLogin(t, provider, oidcService); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(claimer.claimed) != 1 || claimer.claimed[0] != "existing" {
+		t.Errorf("expected invitations of the linked user to be claimed, got %v", claimer.claimed)
+	}
+}
+
+func TestOIDCStateCookieAttributes(t *testing.T) {
+	provider := oidctest.NewProvider(t, "tasksstream")
+	oidcService := newTestOIDCService(provider, newOIDCTestStore(), sessionStarterFunc(func(domain.User, service.ClientInfo) (service.LoginResult, error) {
+		return service.LoginResult{}, nil
+	}), noopInvitationClaimer{})
+
+	// за прокси, снимающим TLS, запрос приходит без r.TLS,
+	// но cookie всё равно должна быть Secure
+	login := httptest.NewRecorder()
+	OIDCLoginHandler(oidcService, true).ServeHTTP(login, httptest.NewRequest(http.MethodGet, "/auth/oidc/login", nil))
+
+	cookies := login.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("expected state cookie, got %v", cookies)
+	}
+	set := cookies[0]
+
+	req := httptest.NewRequest(http.MethodGet, "/auth/oidc/callback?code=unknown&state="+set.Value, nil)
+	req.AddCookie(set)
+
+	callback := httptest.NewRecorder()
+	OIDCCallbackHandler(oidcService, true).ServeHTTP(callback, req)
+
+	cookies = callback.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].MaxAge >= 0 {
+		t.Fatalf("expected state cookie to be deleted, got %v", cookies)
+	}
+	deleted := cookies[0]
+
+	for _, cookie := range []*http.Cookie{set, deleted} {
+		if !cookie.Secure || !cookie.HttpOnly || cookie.SameSite != http.SameSiteLaxMode || cookie.Path != "/auth/oidc" {
+			t.Errorf("unexpected cookie attributes: %+v", cookie)
+		}
+	}
+}
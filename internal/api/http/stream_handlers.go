@@ -0,0 +1,234 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/service"
+)
+
+const (
+	streamWriteTimeout = 10 * time.Second
+	streamPongTimeout  = 60 * time.Second
+	streamPingInterval = 30 * time.Second
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	// авторизация идёт по токену, а не по cookie, поэтому Origin не проверяем
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+func BoardStreamHandler(eventService service.EventService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		boardID := r.PathValue("id")
+		if boardID == "" {
+			HandleError(w, domain.ErrInvalidInput)
+			return
+		}
+
+		events, stop, err := scopedEventService(r, eventService).Subscribe(userID, boardID)
+		if err != nil {
+			HandleError(w, err)
+			return
+		}
+		defer stop()
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			// Upgrade сам отправил ответ с ошибкой
+			return
+		}
+		defer conn.Close()
+
+		// клиент ничего не присылает, но чтение нужно для обработки pong и close
+		go func() {
+			defer stop()
+
+			conn.SetReadLimit(512)
+			_ = conn.SetReadDeadline(time.Now().Add(streamPongTimeout))
+			conn.SetPongHandler(func(string) error {
+				return conn.SetReadDeadline(time.Now().Add(streamPongTimeout))
+			})
+
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+
+		ticker := time.NewTicker(streamPingInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case event, ok := <-events:
+				_ = conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+				if !ok {
+					_ = conn.WriteMessage(
+						websocket.CloseMessage,
+						websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+					)
+					return
+				}
+
+				if err := conn.WriteJSON(event); err != nil {
+					return
+				}
+
+			case <-ticker.C:
+				_ = conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+				if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+
+func BoardEventsSSEHandler(eventService service.EventService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		boardID := r.PathValue("id")
+		if boardID == "" {
+			HandleError(w, domain.ErrInvalidInput)
+			return
+		}
+
+		// EventSource сам присылает Last-Event-ID при переподключении,
+		// query-параметр нужен для первого подключения с известной позиции
+		lastEventID := r.Header.Get("Last-Event-ID")
+		if lastEventID == "" {
+			lastEventID = r.URL.Query().Get("last_event_id")
+		}
+
+		var lastID int64
+		if lastEventID != "" {
+			id, err := strconv.ParseInt(lastEventID, 10, 64)
+			if err != nil || id < 0 {
+				HandleError(w, domain.ErrInvalidInput)
+				return
+			}
+			lastID = id
+		}
+
+		stream := scopedEventService(r, eventService)
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			HandleError(w, fmt.Errorf("streaming is not supported"))
+			return
+		}
+
+		// подписываемся до чтения истории, чтобы не потерять события между ними
+		events, stop, err := stream.Subscribe(userID, boardID)
+		if err != nil {
+			HandleError(w, err)
+			return
+		}
+		defer stop()
+
+		history, err := stream.History(userID, boardID, lastID)
+		if err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+
+		for len(history) > 0 {
+			for _, event := range history {
+				if err := writeSSEEvent(w, event); err != nil {
+					return
+				}
+				lastID = event.ID
+			}
+			flusher.Flush()
+
+			history, err = stream.History(userID, boardID, lastID)
+			if err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+
+		ticker := time.NewTicker(streamPingInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+
+				// уже отправлено из истории
+				if event.ID != 0 && event.ID <= lastID {
+					continue
+				}
+
+				if err := writeSSEEvent(w, event); err != nil {
+					return
+				}
+				if event.ID != 0 {
+					lastID = event.ID
+				}
+				flusher.Flush()
+
+			case <-ticker.C:
+				if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+					return
+				}
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+func writeSSEEvent(w http.ResponseWriter, event domain.BoardEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	if event.ID != 0 {
+		if _, err := fmt.Fprintf(w, "id: %d\n", event.ID); err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+	return err
+}
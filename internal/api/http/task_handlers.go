@@ -3,6 +3,8 @@ package httpapi
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/ovk741/TasksStream/internal/domain"
 	"github.com/ovk741/TasksStream/internal/service"
@@ -23,9 +25,13 @@ func CreateTaskHandler(taskService service.TaskService) http.HandlerFunc {
 		}
 
 		var input struct {
-			Title       string `json:"title"`
-			ColumnID    string `json:"column_id"`
-			Description string `json:"description"`
+			Title       string     `json:"title"`
+			ColumnID    string     `json:"column_id"`
+			Description string     `json:"description"`
+			StartAt     *time.Time `json:"start_at"`
+			DueAt       *time.Time `json:"due_at"`
+
+			CustomFields domain.CustomFieldValues `json:"custom_fields"`
 		}
 
 		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
@@ -33,7 +39,10 @@ func CreateTaskHandler(taskService service.TaskService) http.HandlerFunc {
 			return
 		}
 
-		task, err := taskService.Create(userID, input.Title, input.Description, input.ColumnID)
+		task, err := scopedTaskService(r, taskService).Create(userID, input.Title, input.Description, input.ColumnID, service.TaskDates{
+			StartAt: input.StartAt,
+			DueAt:   input.DueAt,
+		}, input.CustomFields)
 		if err != nil {
 			HandleError(w, err)
 			return
@@ -65,7 +74,9 @@ func GetTasksByColumnHandler(taskService service.TaskService) http.HandlerFunc {
 			return
 		}
 
-		tasks, err := taskService.GetByColumnID(userID, columnID)
+		labelID := r.URL.Query().Get("label")
+
+		tasks, err := scopedTaskService(r, taskService).GetByColumnID(userID, columnID, labelID)
 		if err != nil {
 			HandleError(w, err)
 			return
@@ -94,9 +105,15 @@ func UpdateTaskHandler(taskService service.TaskService) http.HandlerFunc {
 			return
 		}
 
+		// необязательные поля читаются как есть, чтобы отличить
+		// не переданное поле от явного null
 		var input struct {
-			Title       string `json:"title"`
-			Description string `json:"description"`
+			Title       string          `json:"title"`
+			Description string          `json:"description"`
+			StartAt     json.RawMessage `json:"start_at"`
+			DueAt       json.RawMessage `json:"due_at"`
+
+			CustomFields json.RawMessage `json:"custom_fields"`
 		}
 
 		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
@@ -104,7 +121,13 @@ func UpdateTaskHandler(taskService service.TaskService) http.HandlerFunc {
 			return
 		}
 
-		task, err := taskService.Update(userID, taskID, input.Title, input.Description)
+		update, err := taskUpdateFromJSON(input.StartAt, input.DueAt, input.CustomFields)
+		if err != nil {
+			HandleError(w, domain.ErrInvalidInput)
+			return
+		}
+
+		task, err := scopedTaskService(r, taskService).Update(userID, taskID, input.Title, input.Description, update)
 		if err != nil {
 			HandleError(w, err)
 			return
@@ -115,6 +138,38 @@ func UpdateTaskHandler(taskService service.TaskService) http.HandlerFunc {
 	}
 }
 
+// taskUpdateFromJSON: не переданное поле оставляет значение задачи, null очищает его
+func taskUpdateFromJSON(startAt, dueAt, customFields json.RawMessage) (service.TaskUpdate, error) {
+	var update service.TaskUpdate
+
+	for _, field := range []struct {
+		raw    json.RawMessage
+		target *service.OptionalTime
+	}{
+		{startAt, &update.StartAt},
+		{dueAt, &update.DueAt},
+	} {
+		if field.raw == nil {
+			continue
+		}
+		if err := json.Unmarshal(field.raw, &field.target.Value); err != nil {
+			return service.TaskUpdate{}, err
+		}
+		field.target.Set = true
+	}
+
+	if customFields != nil {
+		if err := json.Unmarshal(customFields, &update.CustomFields); err != nil {
+			return service.TaskUpdate{}, err
+		}
+		if update.CustomFields == nil {
+			update.CustomFields = domain.CustomFieldValues{}
+		}
+	}
+
+	return update, nil
+}
+
 func DeleteTaskHandler(taskService service.TaskService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodDelete {
@@ -131,7 +186,7 @@ func DeleteTaskHandler(taskService service.TaskService) http.HandlerFunc {
 			HandleError(w, domain.ErrInvalidInput)
 			return
 		}
-		err := taskService.Delete(userID, taskID)
+		err := scopedTaskService(r, taskService).Delete(userID, taskID)
 		if err != nil {
 			HandleError(w, err)
 			return
@@ -170,7 +225,7 @@ func MoveTaskHandler(taskService service.TaskService) http.HandlerFunc {
 			return
 		}
 
-		task, err := taskService.Move(userID, taskID, input.ColumnID, input.Position)
+		task, err := scopedTaskService(r, taskService).Move(userID, taskID, input.ColumnID, input.Position)
 		if err != nil {
 			HandleError(w, err)
 			return
@@ -181,3 +236,72 @@ func MoveTaskHandler(taskService service.TaskService) http.HandlerFunc {
 		_ = json.NewEncoder(w).Encode(task)
 	}
 }
+
+func GetOverdueTasksHandler(taskService service.TaskService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		tasks, err := scopedTaskService(r, taskService).GetOverdue(userID, r.PathValue("id"))
+		if err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(tasks)
+	}
+}
+
+func GetDueTasksHandler(taskService service.TaskService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		days, err := dueWithinDays(r)
+		if err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		tasks, err := scopedTaskService(r, taskService).GetDueWithin(userID, r.PathValue("id"), days)
+		if err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(tasks)
+	}
+}
+
+// defaultDueWithinDays используется, если параметр days не передан
+const defaultDueWithinDays = 7
+
+func dueWithinDays(r *http.Request) (int, error) {
+	v := r.URL.Query().Get("days")
+	if v == "" {
+		return defaultDueWithinDays, nil
+	}
+
+	days, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, domain.ErrInvalidInput
+	}
+
+	return days, nil
+}
@@ -0,0 +1,88 @@
+package httpapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ovk741/TasksStream/internal/api/http/middleware"
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/service"
+)
+
+// updateRecorder запоминает, с чем handler вызвал Update
+type updateRecorder struct {
+	service.TaskService
+	update service.TaskUpdate
+}
+
+func (s *updateRecorder) Update(userID, taskID, title, description string, update service.TaskUpdate) (domain.Task, error) {
+	s.update = update
+	return domain.Task{ID: taskID, Title: title}, nil
+}
+
+func TestUpdateTaskHandlerKeepsOmittedFields(t *testing.T) {
+	cases := []struct {
+		name       string
+		body       string
+		wantStart  service.OptionalTime
+		wantDue    bool
+		wantFields domain.CustomFieldValues
+	}{
+		{
+			name: "omitted fields are kept",
+			body: `{"title":"Task"}`,
+		},
+		{
+			name:       "null clears",
+			body:       `{"title":"Task","start_at":null,"due_at":null,"custom_fields":null}`,
+			wantStart:  service.OptionalTime{Set: true},
+			wantDue:    true,
+			wantFields: domain.CustomFieldValues{},
+		},
+		{
+			name:       "values replace",
+			body:       `{"title":"Task","due_at":"2026-05-15T18:00:00+03:00","custom_fields":{"priority":"high"}}`,
+			wantDue:    true,
+			wantFields: domain.CustomFieldValues{"priority": "high"},
+		},
+	}
+
+	for _, tc := range cases {
+		recorder := &updateRecorder{}
+
+		req := httptest.NewRequest(http.MethodPut, "/tasks?id=task-1", strings.NewReader(tc.body))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, "user-1"))
+
+		rr := httptest.NewRecorder()
+		UpdateTaskHandler(recorder).ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d: %s", tc.name, rr.Code, rr.Body.String())
+		}
+
+		got := recorder.update
+		if got.StartAt != tc.wantStart {
+			t.Errorf("%s: expected start_at %+v, got %+v", tc.name, tc.wantStart, got.StartAt)
+		}
+		if got.DueAt.Set != tc.wantDue {
+			t.Errorf("%s: expected due_at set=%v, got %+v", tc.name, tc.wantDue, got.DueAt)
+		}
+		if (got.CustomFields == nil) != (tc.wantFields == nil) || len(got.CustomFields) != len(tc.wantFields) {
+			t.Errorf("%s: expected custom_fields %v, got %v", tc.name, tc.wantFields, got.CustomFields)
+		}
+	}
+
+	// строка вместо даты — это ошибка клиента, а не пропуск поля
+	req := httptest.NewRequest(http.MethodPut, "/tasks?id=task-1", strings.NewReader(`{"title":"Task","due_at":"15.05.2026"}`))
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, "user-1"))
+
+	rr := httptest.NewRecorder()
+	UpdateTaskHandler(&updateRecorder{}).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for malformed date, got %d", rr.Code)
+	}
+}
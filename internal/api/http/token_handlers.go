@@ -0,0 +1,103 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/service"
+)
+
+func CreatePersonalTokenHandler(tokenService service.PersonalTokenService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		var input struct {
+			Name          string              `json:"name"`
+			Scopes        []domain.TokenScope `json:"scopes"`
+			ExpiresInDays int                 `json:"expires_in_days"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			HandleError(w, domain.ErrInvalidInput)
+			return
+		}
+
+		if input.ExpiresInDays < 0 {
+			HandleError(w, domain.ErrInvalidInput)
+			return
+		}
+
+		var expiresAt *time.Time
+		if input.ExpiresInDays > 0 {
+			t := time.Now().AddDate(0, 0, input.ExpiresInDays)
+			expiresAt = &t
+		}
+
+		token, secret, err := tokenService.Create(userID, input.Name, input.Scopes, expiresAt)
+		if err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(struct {
+			domain.PersonalAccessToken
+			Token string `json:"token"`
+		}{token, secret})
+	}
+}
+
+func GetPersonalTokensHandler(tokenService service.PersonalTokenService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		tokens, err := tokenService.List(userID)
+		if err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(tokens)
+	}
+}
+
+func RevokePersonalTokenHandler(tokenService service.PersonalTokenService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		if err := tokenService.Revoke(userID, r.PathValue("id")); err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
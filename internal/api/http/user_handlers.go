@@ -0,0 +1,109 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/service"
+)
+
+type meResponse struct {
+	domain.UserProfile
+	EmailVerified    bool `json:"email_verified"`
+	TwoFactorEnabled bool `json:"two_factor_enabled"`
+}
+
+func newMeResponse(user domain.User) meResponse {
+	return meResponse{
+		UserProfile:      user.Profile(),
+		EmailVerified:    user.EmailVerified,
+		TwoFactorEnabled: user.TOTPEnabled,
+	}
+}
+
+func GetMeHandler(userService service.UserService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		user, err := scopedUserService(r, userService).GetMe(userID)
+		if err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(newMeResponse(user))
+	}
+}
+
+func UpdateMeHandler(userService service.UserService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		var input struct {
+			DisplayName string `json:"display_name"`
+			AvatarURL   string `json:"avatar_url"`
+			Timezone    string `json:"timezone"`
+			Locale      string `json:"locale"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			HandleError(w, domain.ErrInvalidInput)
+			return
+		}
+
+		user, err := scopedUserService(r, userService).UpdateProfile(userID, service.ProfileInput{
+			DisplayName: input.DisplayName,
+			AvatarURL:   input.AvatarURL,
+			Timezone:    input.Timezone,
+			Locale:      input.Locale,
+		})
+		if err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(newMeResponse(user))
+	}
+}
+
+func SearchUsersHandler(userService service.UserService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := MustGetUserID(w, r)
+		if !ok {
+			return
+		}
+
+		users, err := scopedUserService(r, userService).Search(userID, r.URL.Query().Get("query"))
+		if err != nil {
+			HandleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(users)
+	}
+}
@@ -0,0 +1,53 @@
+package domain
+
+// Action — действие над доской или её содержимым, которое проверяет политика доступа.
+type Action string
+
+const (
+	ActionBoardRead     Action = "board:read"
+	ActionBoardUpdate   Action = "board:update"
+	ActionBoardDelete   Action = "board:delete"
+	ActionBoardTransfer Action = "board:transfer"
+	ActionBoardShare    Action = "board:share"
+	ActionMemberManage  Action = "member:manage"
+	ActionFieldManage   Action = "field:manage"
+
+	ActionColumnWrite  Action = "column:write"
+	ActionColumnDelete Action = "column:delete"
+
+	ActionTaskWrite  Action = "task:write"
+	ActionTaskMove   Action = "task:move"
+	ActionTaskDelete Action = "task:delete"
+
+	ActionLabelManage Action = "label:manage"
+)
+
+// Actions перечисляет все действия; используется в тестах политики.
+var Actions = []Action{
+	ActionBoardRead,
+	ActionBoardUpdate,
+	ActionBoardDelete,
+	ActionBoardTransfer,
+	ActionBoardShare,
+	ActionMemberManage,
+	ActionFieldManage,
+	ActionColumnWrite,
+	ActionColumnDelete,
+	ActionTaskWrite,
+	ActionTaskMove,
+	ActionTaskDelete,
+	ActionLabelManage,
+}
+
+// CustomRoleActions — действия, которые можно включить в собственную роль доски.
+// Управление участниками, удаление и передача доски остаются за владельцем,
+// иначе собственная роль позволила бы выдать себе больше прав.
+var CustomRoleActions = []Action{
+	ActionBoardUpdate,
+	ActionColumnWrite,
+	ActionColumnDelete,
+	ActionTaskWrite,
+	ActionTaskMove,
+	ActionTaskDelete,
+	ActionLabelManage,
+}
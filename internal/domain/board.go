@@ -3,7 +3,18 @@ package domain
 import "time"
 
 type Board struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	CreatedAt time.Time `json:"created_at"`
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// пусто у личных досок, не входящих ни в одно пространство
+	WorkspaceID *string   `json:"workspace_id,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// BoardOverview — доска в административном списке: владелец и объём.
+// Owner пуст у «осиротевшей» доски, владелец которой удалён.
+type BoardOverview struct {
+	Board
+	Owner       *UserProfile `json:"owner,omitempty"`
+	MemberCount int          `json:"member_count"`
+	TaskCount   int          `json:"task_count"`
 }
@@ -0,0 +1,28 @@
+package domain
+
+import "time"
+
+type InvitationStatus string
+
+const (
+	InvitationPending  InvitationStatus = "pending"
+	InvitationAccepted InvitationStatus = "accepted"
+	InvitationDeclined InvitationStatus = "declined"
+	InvitationRevoked  InvitationStatus = "revoked"
+)
+
+// BoardInvitation — приглашение в доску по email. Адрес может ещё не быть
+// зарегистрирован. Токен из письма хранится только в виде хэша.
+type BoardInvitation struct {
+	ID          string           `json:"id"`
+	BoardID     string           `json:"board_id"`
+	BoardName   string           `json:"board_name,omitempty"`
+	Email       string           `json:"email"`
+	Role        BoardRole        `json:"role"`
+	InvitedBy   string           `json:"invited_by"`
+	TokenHash   string           `json:"-"`
+	Status      InvitationStatus `json:"status"`
+	CreatedAt   time.Time        `json:"created_at"`
+	ExpiresAt   time.Time        `json:"expires_at"`
+	RespondedAt *time.Time       `json:"responded_at,omitempty"`
+}
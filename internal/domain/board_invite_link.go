@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// BoardInviteLink — многоразовая ссылка для вступления в доску.
+// Сам токен выдаётся один раз при создании, хранится только хэш.
+type BoardInviteLink struct {
+	ID        string     `json:"id"`
+	BoardID   string     `json:"board_id"`
+	Role      BoardRole  `json:"role"`
+	CreatedBy string     `json:"created_by"`
+	TokenHash string     `json:"-"`
+	MaxUses   *int       `json:"max_uses,omitempty"`
+	Uses      int        `json:"uses"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Active сообщает, можно ли ещё вступить по ссылке.
+func (l BoardInviteLink) Active(now time.Time) bool {
+	if l.RevokedAt != nil {
+		return false
+	}
+	if l.ExpiresAt != nil && !l.ExpiresAt.After(now) {
+		return false
+	}
+	return l.MaxUses == nil || l.Uses < *l.MaxUses
+}
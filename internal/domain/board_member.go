@@ -10,10 +10,39 @@ const (
 	BoardRoleViewer BoardRole = "viewer"
 )
 
+// MemberAccess показывает, откуда у участника доступ к доске
+type MemberAccess string
+
+const (
+	MemberAccessDirect MemberAccess = "direct"
+	MemberAccessTeam   MemberAccess = "team"
+)
+
+// Rank упорядочивает роли, чтобы выбрать наибольшую из прямой и командных.
+// Собственная роль доски даёт не меньше viewer и не больше editor.
+func (r BoardRole) Rank() int {
+	switch r {
+	case BoardRoleOwner:
+		return 3
+	case BoardRoleEditor:
+		return 2
+	case BoardRoleViewer:
+		return 0
+	}
+	return 1
+}
+
 type BoardMember struct {
-	ID        string
-	BoardID   string
-	UserID    string
-	Role      BoardRole
-	CreatedAt time.Time
+	ID        string    `json:"-"`
+	BoardID   string    `json:"board_id"`
+	UserID    string    `json:"user_id"`
+	Role      BoardRole `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// заполняются при выборке списка участников: Role — итоговая роль,
+	// Access — direct, если участник добавлен напрямую, Teams — команды,
+	// через которые у него тоже есть доступ
+	Access MemberAccess `json:"access,omitempty"`
+	Teams  []TeamRef    `json:"teams,omitempty"`
+	User   *UserProfile `json:"user,omitempty"`
 }
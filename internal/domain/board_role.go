@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// BoardRoleDefinition — набор прав, заданный под именем роли.
+// Встроенные роли (owner, editor, viewer) одинаковы для всех досок,
+// собственные роли владелец задаёт для своей доски.
+type BoardRoleDefinition struct {
+	ID          string    `json:"-"`
+	BoardID     string    `json:"board_id,omitempty"`
+	Name        BoardRole `json:"name"`
+	Permissions []Action  `json:"permissions"`
+	BuiltIn     bool      `json:"built_in"`
+	CreatedAt   time.Time `json:"created_at,omitempty"`
+}
+
+// IsBuiltIn сообщает, что роль — одна из встроенных.
+func (r BoardRole) IsBuiltIn() bool {
+	return r == BoardRoleOwner || r == BoardRoleEditor || r == BoardRoleViewer
+}
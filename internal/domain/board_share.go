@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+// BoardShare — публичная ссылка на доску только для чтения.
+// Сам токен не хранится, только его хэш.
+type BoardShare struct {
+	BoardID   string    `json:"board_id"`
+	TokenHash string    `json:"-"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PublicBoard — то, что видно по публичной ссылке. Поля перечислены явно:
+// участники, авторы изменений и прочие данные о людях сюда не попадают.
+type PublicBoard struct {
+	ID      string         `json:"id"`
+	Name    string         `json:"name"`
+	Columns []PublicColumn `json:"columns"`
+}
+
+type PublicColumn struct {
+	ID       string       `json:"id"`
+	Title    string       `json:"title"`
+	Position int          `json:"position"`
+	Tasks    []PublicTask `json:"tasks"`
+}
+
+type PublicTask struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Position    int    `json:"position"`
+}
@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// Checklist — упорядоченный список пунктов внутри задачи. У задачи может
+// быть несколько чек-листов.
+type Checklist struct {
+	ID        string          `json:"id"`
+	TaskID    string          `json:"task_id"`
+	Title     string          `json:"title"`
+	Position  int             `json:"position"`
+	CreatedAt time.Time       `json:"created_at"`
+	Items     []ChecklistItem `json:"items"`
+}
+
+type ChecklistItem struct {
+	ID          string    `json:"id"`
+	ChecklistID string    `json:"checklist_id"`
+	Title       string    `json:"title"`
+	Done        bool      `json:"done"`
+	Position    int       `json:"position"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ChecklistProgress — сколько пунктов всех чек-листов задачи отмечено
+type ChecklistProgress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
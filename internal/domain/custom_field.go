@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+type CustomFieldType string
+
+const (
+	CustomFieldText        CustomFieldType = "text"
+	CustomFieldNumber      CustomFieldType = "number"
+	CustomFieldDate        CustomFieldType = "date"
+	CustomFieldSelect      CustomFieldType = "select"
+	CustomFieldMultiSelect CustomFieldType = "multi_select"
+	CustomFieldUser        CustomFieldType = "user"
+)
+
+// HasOptions сообщает, что значение поля выбирается из Options.
+func (t CustomFieldType) HasOptions() bool {
+	return t == CustomFieldSelect || t == CustomFieldMultiSelect
+}
+
+// CustomField — поле задачи, которое владелец задаёт для своей доски,
+// например приоритет или оценка в story points. Тип поля после создания
+// не меняется.
+type CustomField struct {
+	ID        string          `json:"id"`
+	BoardID   string          `json:"board_id"`
+	Name      string          `json:"name"`
+	Type      CustomFieldType `json:"type"`
+	Options   []string        `json:"options,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// CustomFieldValues — значения полей задачи по ID поля. Текст, дата
+// (YYYY-MM-DD), вариант выбора и ID пользователя хранятся строкой,
+// число — float64, множественный выбор — список строк.
+type CustomFieldValues map[string]any
@@ -1,6 +1,9 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 var (
 	ErrNotFound           = errors.New("not found")
@@ -10,4 +13,21 @@ var (
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	ErrUserAlreadyExists  = errors.New("user already exists")
 	ErrForbidden          = errors.New("forbidden")
+	ErrEmailNotVerified   = errors.New("email not verified")
+	ErrTooManyRequests    = errors.New("too many requests")
+	ErrUserDisabled       = errors.New("user disabled")
 )
+
+// RateLimitError сообщает, через сколько можно повторить запрос.
+// errors.Is(err, ErrTooManyRequests) для неё истинно.
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return ErrTooManyRequests.Error()
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return ErrTooManyRequests
+}
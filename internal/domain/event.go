@@ -0,0 +1,59 @@
+package domain
+
+import "time"
+
+type EventType string
+
+const (
+	EventBoardUpdated EventType = "board.updated"
+	EventBoardDeleted EventType = "board.deleted"
+
+	EventMemberAdded       EventType = "member.added"
+	EventMemberRemoved     EventType = "member.removed"
+	EventMemberRoleChanged EventType = "member.role_changed"
+
+	EventTeamGranted       EventType = "team.granted"
+	EventTeamRevoked       EventType = "team.revoked"
+	EventTeamMemberRemoved EventType = "team.member_removed"
+
+	EventWorkspaceMemberRemoved EventType = "workspace.member_removed"
+
+	EventColumnCreated EventType = "column.created"
+	EventColumnUpdated EventType = "column.updated"
+	EventColumnMoved   EventType = "column.moved"
+	EventColumnDeleted EventType = "column.deleted"
+
+	EventTaskCreated EventType = "task.created"
+	EventTaskUpdated EventType = "task.updated"
+	EventTaskMoved   EventType = "task.moved"
+	EventTaskDeleted EventType = "task.deleted"
+
+	EventTaskAssigned   EventType = "task.assigned"
+	EventTaskUnassigned EventType = "task.unassigned"
+
+	EventLabelCreated EventType = "label.created"
+	EventLabelUpdated EventType = "label.updated"
+	EventLabelDeleted EventType = "label.deleted"
+
+	EventTaskLabeled   EventType = "task.labeled"
+	EventTaskUnlabeled EventType = "task.unlabeled"
+
+	EventFieldCreated EventType = "field.created"
+	EventFieldUpdated EventType = "field.updated"
+	EventFieldDeleted EventType = "field.deleted"
+
+	EventChecklistCreated EventType = "checklist.created"
+	EventChecklistUpdated EventType = "checklist.updated"
+	EventChecklistDeleted EventType = "checklist.deleted"
+)
+
+// BoardEvent описывает успешное изменение доски, которое рассылается подписчикам.
+// ID монотонно возрастает в пределах одной доски.
+type BoardEvent struct {
+	ID        int64     `json:"id"`
+	Type      EventType `json:"type"`
+	BoardID   string    `json:"board_id"`
+	ActorID   string    `json:"actor_id"`
+	Payload   any       `json:"payload"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// Label — метка доски. Метки прикрепляются только к задачам той же доски.
+type Label struct {
+	ID        string    `json:"id"`
+	BoardID   string    `json:"board_id"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"`
+	CreatedAt time.Time `json:"created_at"`
+}
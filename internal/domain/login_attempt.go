@@ -0,0 +1,48 @@
+package domain
+
+import "time"
+
+// LoginAttempt — счётчик неудачных входов по ключу (адрес или IP).
+type LoginAttempt struct {
+	Key           string
+	Failures      int
+	LastFailureAt time.Time
+	LockedUntil   *time.Time
+}
+
+// LoginLockout — запись аудита о временной блокировке входа.
+type LoginLockout struct {
+	ID          string
+	Key         string
+	Failures    int
+	IP          string
+	LockedUntil time.Time
+	CreatedAt   time.Time
+}
+
+// LockoutPolicy — правила блокировки для одного вида ключа: после FreeAttempts
+// неудач каждая следующая удваивает блокировку, начиная с BaseLockout.
+type LockoutPolicy struct {
+	FreeAttempts int
+	// счётчик начинается заново, если неудач не было дольше Window
+	Window      time.Duration
+	BaseLockout time.Duration
+	MaxLockout  time.Duration
+}
+
+// Lockout возвращает срок блокировки после failures неудач или 0, если
+// попытки ещё бесплатные: 30s, 1m, 2m, 4m ... но не больше MaxLockout
+func (p LockoutPolicy) Lockout(failures int) time.Duration {
+	if failures < p.FreeAttempts {
+		return 0
+	}
+
+	d := p.BaseLockout
+	for i := p.FreeAttempts; i < failures && d < p.MaxLockout; i++ {
+		d *= 2
+	}
+	if d > p.MaxLockout {
+		d = p.MaxLockout
+	}
+	return d
+}
@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+type TokenScope string
+
+const (
+	ScopeReadBoards  TokenScope = "read:boards"
+	ScopeWriteBoards TokenScope = "write:boards"
+	ScopeReadTasks   TokenScope = "read:tasks"
+	ScopeWriteTasks  TokenScope = "write:tasks"
+)
+
+var TokenScopes = []TokenScope{
+	ScopeReadBoards,
+	ScopeWriteBoards,
+	ScopeReadTasks,
+	ScopeWriteTasks,
+}
+
+func (s TokenScope) Valid() bool {
+	for _, scope := range TokenScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type PersonalAccessToken struct {
+	ID         string       `json:"id"`
+	UserID     string       `json:"-"`
+	Name       string       `json:"name"`
+	TokenHash  string       `json:"-"`
+	Scopes     []TokenScope `json:"scopes"`
+	CreatedAt  time.Time    `json:"created_at"`
+	LastUsedAt *time.Time   `json:"last_used_at"`
+	ExpiresAt  *time.Time   `json:"expires_at"`
+	RevokedAt  *time.Time   `json:"-"`
+}
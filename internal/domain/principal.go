@@ -0,0 +1,22 @@
+package domain
+
+// Principal — аутентифицированный субъект запроса. Scopes == nil означает
+// обычную пользовательскую сессию (JWT) без ограничений, непустой список —
+// персональный токен, которому разрешены только перечисленные действия.
+type Principal struct {
+	UserID string
+	Scopes []TokenScope
+}
+
+func (p Principal) HasScope(scope TokenScope) bool {
+	if p.Scopes == nil {
+		return true
+	}
+
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
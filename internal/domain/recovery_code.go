@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// RecoveryCode — одноразовый код для входа без устройства с TOTP.
+type RecoveryCode struct {
+	ID        string
+	UserID    string
+	CodeHash  string
+	CreatedAt time.Time
+	UsedAt    *time.Time
+}
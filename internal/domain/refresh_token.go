@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// RefreshToken хранит хэш выданного refresh-токена. Все токены, полученные
+// ротацией из одного логина, образуют семейство с общим FamilyID.
+type RefreshToken struct {
+	ID        string
+	UserID    string
+	FamilyID  string
+	TokenHash string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+}
@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// Session соответствует одному логину на устройстве. ID сессии совпадает
+// с FamilyID её refresh-токенов.
+type Session struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"-"`
+	UserAgent  string     `json:"user_agent"`
+	IP         string     `json:"ip"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt time.Time  `json:"last_used_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	RevokedAt  *time.Time `json:"-"`
+}
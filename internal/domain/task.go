@@ -9,4 +9,15 @@ type Task struct {
 	Description string    `json:"description"`
 	Position    int       `json:"position"`
 	CreatedAt   time.Time `json:"created_at"`
+
+	// сроки необязательны; если заданы оба, начало не позже срока
+	StartAt *time.Time `json:"start_at,omitempty"`
+	DueAt   *time.Time `json:"due_at,omitempty"`
+
+	AssigneeIDs []string `json:"assignee_ids"`
+	LabelIDs    []string `json:"label_ids"`
+
+	CustomFields CustomFieldValues `json:"custom_fields"`
+
+	Checklist ChecklistProgress `json:"checklist"`
 }
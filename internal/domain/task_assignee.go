@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+type TaskAssignee struct {
+	TaskID     string       `json:"task_id"`
+	UserID     string       `json:"user_id"`
+	AssignedBy string       `json:"assigned_by"`
+	CreatedAt  time.Time    `json:"created_at"`
+	User       *UserProfile `json:"user,omitempty"`
+}
+
+// AssignedTask — задача, назначенная пользователю, вместе с её доской и колонкой.
+type AssignedTask struct {
+	Task
+	BoardID        string `json:"board_id"`
+	BoardName      string `json:"board_name"`
+	ColumnTitle    string `json:"column_title"`
+	ColumnPosition int    `json:"-"`
+}
+
+// AssignedBoard — доска в списке «мои задачи»: только колонки,
+// в которых есть назначенные пользователю задачи.
+type AssignedBoard struct {
+	ID      string           `json:"id"`
+	Name    string           `json:"name"`
+	Columns []AssignedColumn `json:"columns"`
+}
+
+type AssignedColumn struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Position int    `json:"position"`
+	Tasks    []Task `json:"tasks"`
+}
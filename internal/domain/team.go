@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+type TeamRole string
+
+const (
+	// мейнтейнер управляет составом команды и выдаёт ей доступ к доскам
+	TeamRoleMaintainer TeamRole = "maintainer"
+	TeamRoleMember     TeamRole = "member"
+)
+
+// Team — группа пользователей, которой роль на доске выдаётся целиком.
+type Team struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type TeamRef struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type TeamMember struct {
+	ID        string    `json:"-"`
+	TeamID    string    `json:"team_id"`
+	UserID    string    `json:"user_id"`
+	Role      TeamRole  `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// заполняется при выборке списка участников
+	User *UserProfile `json:"user,omitempty"`
+}
+
+// BoardTeamGrant — роль, выданная на доске всем участникам команды.
+type BoardTeamGrant struct {
+	BoardID   string    `json:"board_id"`
+	TeamID    string    `json:"team_id"`
+	TeamName  string    `json:"team_name,omitempty"`
+	Role      BoardRole `json:"role"`
+	GrantedBy string    `json:"granted_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
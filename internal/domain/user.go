@@ -7,4 +7,69 @@ type User struct {
 	Email        string
 	PasswordHash string
 	CreatedAt    time.Time
+
+	EmailVerified bool
+
+	// секрет TOTP заполняется при /auth/2fa/setup, но вход по второму
+	// фактору требуется только после подтверждения кодом (TOTPEnabled)
+	TOTPSecret  string
+	TOTPEnabled bool
+
+	DisplayName string
+	AvatarURL   string
+	Timezone    string
+	Locale      string
+
+	// администратор инстанса; выдаётся вручную в базе
+	IsAdmin bool
+	// отключённый пользователь не может войти, а его токены отклоняются
+	DisabledAt *time.Time
+}
+
+// Disabled сообщает, что учётная запись отключена администратором
+func (u User) Disabled() bool {
+	return u.DisabledAt != nil
+}
+
+// UserProfile — то, что о пользователе видят другие участники досок.
+type UserProfile struct {
+	ID          string `json:"id"`
+	Email       string `json:"email"`
+	DisplayName string `json:"display_name"`
+	AvatarURL   string `json:"avatar_url"`
+	Timezone    string `json:"timezone"`
+	Locale      string `json:"locale"`
+}
+
+func (u User) Profile() UserProfile {
+	return UserProfile{
+		ID:          u.ID,
+		Email:       u.Email,
+		DisplayName: u.DisplayName,
+		AvatarURL:   u.AvatarURL,
+		Timezone:    u.Timezone,
+		Locale:      u.Locale,
+	}
+}
+
+// UserAccount — учётная запись в административном API: профиль и состояние,
+// но без хэшей и секретов.
+type UserAccount struct {
+	UserProfile
+	EmailVerified bool       `json:"email_verified"`
+	TOTPEnabled   bool       `json:"two_factor_enabled"`
+	IsAdmin       bool       `json:"is_admin"`
+	DisabledAt    *time.Time `json:"disabled_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+func (u User) Account() UserAccount {
+	return UserAccount{
+		UserProfile:   u.Profile(),
+		EmailVerified: u.EmailVerified,
+		TOTPEnabled:   u.TOTPEnabled,
+		IsAdmin:       u.IsAdmin,
+		DisabledAt:    u.DisabledAt,
+		CreatedAt:     u.CreatedAt,
+	}
 }
@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// UserIdentity связывает пользователя с учётной записью внешнего
+// провайдера (OIDC). Provider — issuer провайдера, Subject — claim sub.
+type UserIdentity struct {
+	Provider  string
+	Subject   string
+	UserID    string
+	Email     string
+	CreatedAt time.Time
+}
+
+// OIDCState хранит параметры начатого входа через OIDC до возврата
+// пользователя с провайдера. ID — хэш параметра state.
+type OIDCState struct {
+	ID           string
+	Nonce        string
+	CodeVerifier string
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+}
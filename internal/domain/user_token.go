@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+type UserTokenPurpose string
+
+const (
+	TokenPurposePasswordReset     UserTokenPurpose = "password_reset"
+	TokenPurposeEmailVerification UserTokenPurpose = "email_verification"
+)
+
+// UserToken — одноразовый токен из письма (сброс пароля, подтверждение адреса).
+// Как и refresh-токены, хранится только в виде хэша.
+type UserToken struct {
+	ID        string
+	UserID    string
+	Purpose   UserTokenPurpose
+	TokenHash string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UsedAt    *time.Time
+}
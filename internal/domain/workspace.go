@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+type WorkspaceRole string
+
+const (
+	// администратор управляет составом пространства и неявно получает
+	// доступ ко всем его доскам
+	WorkspaceRoleAdmin  WorkspaceRole = "admin"
+	WorkspaceRoleMember WorkspaceRole = "member"
+)
+
+// Workspace объединяет доски и людей одной команды или организации.
+type Workspace struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type WorkspaceMember struct {
+	ID          string        `json:"-"`
+	WorkspaceID string        `json:"workspace_id"`
+	UserID      string        `json:"user_id"`
+	Role        WorkspaceRole `json:"role"`
+	CreatedAt   time.Time     `json:"created_at"`
+
+	// заполняется при выборке списка участников
+	User *UserProfile `json:"user,omitempty"`
+}
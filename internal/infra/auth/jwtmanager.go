@@ -1,30 +1,33 @@
 package auth
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
 )
 
+// challenge-токен выдаётся после проверки пароля и живёт ровно столько,
+// сколько нужно, чтобы ввести код второго фактора
+const challengeTTL = 5 * time.Minute
+
 type Manager struct {
-	accessSecret  []byte
-	refreshSecret []byte
-	accessTTL     time.Duration
-	refreshTTL    time.Duration
+	keys       *KeyRing
+	accessTTL  time.Duration
+	refreshTTL time.Duration
 }
 
 func NewJWTManager(
-	accessSecret string,
-	refreshSecret string,
+	keys *KeyRing,
 	accessTTL time.Duration,
 	refreshTTL time.Duration,
 ) *Manager {
 	return &Manager{
-		accessSecret:  []byte(accessSecret),
-		refreshSecret: []byte(refreshSecret),
-		accessTTL:     accessTTL,
-		refreshTTL:    refreshTTL,
+		keys:       keys,
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
 	}
 }
 
@@ -36,53 +39,70 @@ func (m *Manager) GenerateAccessToken(userID string) (string, error) {
 		"type": "access",
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	return token.SignedString(m.accessSecret)
+	return m.sign(claims)
 }
 
 func (m *Manager) GenerateRefreshToken(userID string) (string, error) {
+	jti, err := newTokenID()
+	if err != nil {
+		return "", err
+	}
+
+	// jti делает каждый refresh-токен уникальным, даже если
+	// два токена выпущены для одного пользователя в одну секунду
 	claims := jwt.MapClaims{
 		"sub":  userID,
 		"exp":  time.Now().Add(m.refreshTTL).Unix(),
 		"iat":  time.Now().Unix(),
+		"jti":  jti,
 		"type": "refresh",
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return m.sign(claims)
+}
+
+func (m *Manager) GenerateChallengeToken(userID string) (string, error) {
+	claims := jwt.MapClaims{
+		"sub":  userID,
+		"exp":  time.Now().Add(challengeTTL).Unix(),
+		"iat":  time.Now().Unix(),
+		"type": "2fa_challenge",
+	}
 
-	return token.SignedString(m.refreshSecret)
+	return m.sign(claims)
+}
+
+func (m *Manager) RefreshTTL() time.Duration {
+	return m.refreshTTL
 }
 
 func (m *Manager) ParseRefreshToken(tokenString string) (string, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		return m.refreshSecret, nil
-	})
-	if err != nil || !token.Valid {
-		return "", errors.New("invalid token")
-	}
+	return m.parse(tokenString, "refresh")
+}
 
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return "", errors.New("invalid claims")
-	}
+func (m *Manager) ParseAccessToken(tokenString string) (string, error) {
+	return m.parse(tokenString, "access")
+}
 
-	if claims["type"] != "refresh" {
-		return "", errors.New("invalid token type")
-	}
+func (m *Manager) ParseChallengeToken(tokenString string) (string, error) {
+	return m.parse(tokenString, "2fa_challenge")
+}
 
-	userID, ok := claims["sub"].(string)
-	if !ok || userID == "" {
-		return "", errors.New("invalid subject")
-	}
+func (m *Manager) JWKS() JWKSet {
+	return m.keys.JWKS()
+}
 
-	return userID, nil
+func (m *Manager) sign(claims jwt.MapClaims) (string, error) {
+	key := m.keys.active
+
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+
+	return token.SignedString(key.private)
 }
 
-func (m *Manager) ParseAccessToken(tokenString string) (string, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		return m.accessSecret, nil
-	})
+func (m *Manager) parse(tokenString, tokenType string) (string, error) {
+	token, err := jwt.Parse(tokenString, m.keys.verificationKey)
 	if err != nil || !token.Valid {
 		return "", errors.New("invalid token")
 	}
@@ -92,7 +112,7 @@ func (m *Manager) ParseAccessToken(tokenString string) (string, error) {
 		return "", errors.New("invalid claims")
 	}
 
-	if claims["type"] != "access" {
+	if claims["type"] != tokenType {
 		return "", errors.New("invalid token type")
 	}
 
@@ -103,3 +123,11 @@ func (m *Manager) ParseAccessToken(tokenString string) (string, error) {
 
 	return userID, nil
 }
+
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
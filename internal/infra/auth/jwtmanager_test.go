@@ -0,0 +1,221 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) {
+	t.Helper()
+
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeRSAKey(t *testing.T, dir, name string) *rsa.PrivateKey {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writePEM(t, dir, name, "PRIVATE KEY", der)
+
+	return key
+}
+
+func writeEd25519Key(t *testing.T, dir, name string) ed25519.PrivateKey {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writePEM(t, dir, name, "PRIVATE KEY", der)
+
+	return key
+}
+
+func TestJWTManagerSignsWithActiveKey(t *testing.T) {
+	dir := t.TempDir()
+	writeRSAKey(t, dir, "2025-01.pem")
+	writeEd25519Key(t, dir, "2026-01.pem")
+
+	ring, err := LoadKeyRing(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manager := NewJWTManager(ring, time.Minute, time.Hour)
+
+	token, err := manager.GenerateAccessToken("user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, _, err := new(jwt.Parser).ParseUnverified(token, jwt.MapClaims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if parsed.Header["kid"] != "2026-01" {
+		t.Errorf("expected kid 2026-01, got %v", parsed.Header["kid"])
+	}
+	if parsed.Header["alg"] != "EdDSA" {
+		t.Errorf("expected alg EdDSA, got %v", parsed.Header["alg"])
+	}
+
+	userID, err := manager.ParseAccessToken(token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if userID != "user-1" {
+		t.Errorf("expected user-1, got %s", userID)
+	}
+
+	if _, err := manager.ParseRefreshToken(token); err == nil {
+		t.Error("expected access token to be rejected as refresh token")
+	}
+}
+
+func TestJWTManagerChallengeTokenIsNotAccessToken(t *testing.T) {
+	key, err := GenerateEd25519Key("k1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ring, err := NewKeyRing("", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager := NewJWTManager(ring, time.Minute, time.Hour)
+
+	challenge, err := manager.GenerateChallengeToken("user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := manager.ParseAccessToken(challenge); err == nil {
+		t.Error("expected challenge token to be rejected as access token")
+	}
+
+	userID, err := manager.ParseChallengeToken(challenge)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if userID != "user-1" {
+		t.Errorf("expected user-1, got %s", userID)
+	}
+}
+
+func TestJWTManagerAcceptsTokensFromRetiredKey(t *testing.T) {
+	dir := t.TempDir()
+	old := writeRSAKey(t, dir, "old.pem")
+
+	oldRing, err := LoadKeyRing(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := NewJWTManager(oldRing, time.Minute, time.Hour).GenerateRefreshToken("user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// ротация: закрытый ключ заменяется открытым, активным становится новый
+	if err := os.Remove(filepath.Join(dir, "old.pem")); err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&old.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writePEM(t, dir, "old.pem", "PUBLIC KEY", der)
+	writeEd25519Key(t, dir, "new.pem")
+
+	newRing, err := LoadKeyRing(dir, "new")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userID, err := NewJWTManager(newRing, time.Minute, time.Hour).ParseRefreshToken(token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if userID != "user-1" {
+		t.Errorf("expected user-1, got %s", userID)
+	}
+
+	if len(newRing.JWKS().Keys) != 2 {
+		t.Errorf("expected 2 keys in JWKS, got %d", len(newRing.JWKS().Keys))
+	}
+}
+
+func TestJWTManagerRejectsUnexpectedAlgorithm(t *testing.T) {
+	dir := t.TempDir()
+	key := writeRSAKey(t, dir, "rsa.pem")
+
+	ring, err := LoadKeyRing(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager := NewJWTManager(ring, time.Minute, time.Hour)
+
+	claims := jwt.MapClaims{
+		"sub":  "user-1",
+		"exp":  time.Now().Add(time.Minute).Unix(),
+		"type": "access",
+	}
+
+	// подпись HS256 открытым ключом — классическая атака подмены алгоритма
+	hmacToken := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	hmacToken.Header["kid"] = "rsa"
+	signed, err := hmacToken.SignedString(x509.MarshalPKCS1PublicKey(&key.PublicKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := manager.ParseAccessToken(signed); err == nil {
+		t.Error("expected HS256 token to be rejected")
+	}
+
+	// RS384 тем же ключом тоже не принимается
+	rs384 := jwt.NewWithClaims(jwt.SigningMethodRS384, claims)
+	rs384.Header["kid"] = "rsa"
+	signed, err = rs384.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := manager.ParseAccessToken(signed); err == nil {
+		t.Error("expected RS384 token to be rejected")
+	}
+}
+
+func TestLoadKeyRingWithoutPrivateKey(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := LoadKeyRing(dir, ""); err == nil {
+		t.Error("expected error for empty key directory")
+	}
+}
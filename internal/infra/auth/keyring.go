@@ -0,0 +1,225 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+type Key struct {
+	ID      string
+	Method  jwt.SigningMethod
+	private crypto.PrivateKey
+	public  crypto.PublicKey
+	canSign bool
+}
+
+// KeyRing содержит активный ключ подписи и все ключи, которыми ещё
+// можно проверять ранее выданные токены.
+type KeyRing struct {
+	active *Key
+	keys   map[string]*Key
+}
+
+// LoadKeyRing читает все *.pem из каталога. Имя файла без расширения
+// становится kid. Файлы с закрытым ключом (RSA или Ed25519) пригодны для
+// подписи, файлы с открытым ключом — только для проверки (ключи, выведенные
+// из ротации). Если activeKID пуст, активным становится последний по имени
+// закрытый ключ, поэтому удобно называть файлы по дате выпуска.
+func LoadKeyRing(dir, activeKID string) (*KeyRing, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	keys := make([]*Key, 0, len(paths))
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		kid := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+		key, err := parsePEMKey(kid, data)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", path, err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	return NewKeyRing(activeKID, keys...)
+}
+
+func NewKeyRing(activeKID string, keys ...*Key) (*KeyRing, error) {
+	ring := &KeyRing{keys: make(map[string]*Key, len(keys))}
+
+	for _, key := range keys {
+		if _, ok := ring.keys[key.ID]; ok {
+			return nil, fmt.Errorf("duplicate key id %q", key.ID)
+		}
+		ring.keys[key.ID] = key
+
+		if !key.canSign {
+			continue
+		}
+		if activeKID == "" || key.ID == activeKID {
+			ring.active = key
+		}
+	}
+
+	if ring.active == nil {
+		if activeKID != "" {
+			return nil, fmt.Errorf("active signing key %q not found", activeKID)
+		}
+		return nil, errors.New("no private signing key found")
+	}
+
+	return ring, nil
+}
+
+// GenerateEd25519Key создаёт новый ключ в памяти. Подходит для локальной
+// разработки: после перезапуска все выданные токены станут недействительными.
+func GenerateEd25519Key(kid string) (*Key, error) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Key{
+		ID:      kid,
+		Method:  SigningMethodEdDSA,
+		private: private,
+		public:  public,
+		canSign: true,
+	}, nil
+}
+
+// verificationKey возвращает открытый ключ по kid и проверяет,
+// что токен подписан именно тем алгоритмом, который закреплён за ключом.
+func (k *KeyRing) verificationKey(token *jwt.Token) (interface{}, error) {
+	kid, ok := token.Header["kid"].(string)
+	if !ok || kid == "" {
+		return nil, errors.New("missing kid")
+	}
+
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, errors.New("unknown kid")
+	}
+
+	if token.Method == nil || token.Method.Alg() != key.Method.Alg() {
+		return nil, errors.New("unexpected signing method")
+	}
+
+	return key.public, nil
+}
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+func (k *KeyRing) JWKS() JWKSet {
+	ids := make([]string, 0, len(k.keys))
+	for id := range k.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	set := JWKSet{Keys: make([]JWK, 0, len(ids))}
+
+	for _, id := range ids {
+		key := k.keys[id]
+
+		jwk := JWK{
+			Kid: key.ID,
+			Use: "sig",
+			Alg: key.Method.Alg(),
+		}
+
+		switch public := key.public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		default:
+			continue
+		}
+
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	return set
+}
+
+func parsePEMKey(kid string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block")
+	}
+
+	var (
+		parsed interface{}
+		err    error
+	)
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch key := parsed.(type) {
+	case *rsa.PrivateKey:
+		return &Key{ID: kid, Method: jwt.SigningMethodRS256, private: key, public: &key.PublicKey, canSign: true}, nil
+	case *rsa.PublicKey:
+		return &Key{ID: kid, Method: jwt.SigningMethodRS256, public: key}, nil
+	case ed25519.PrivateKey:
+		return &Key{ID: kid, Method: SigningMethodEdDSA, private: key, public: key.Public(), canSign: true}, nil
+	case ed25519.PublicKey:
+		return &Key{ID: kid, Method: SigningMethodEdDSA, public: key}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type %T", parsed)
+}
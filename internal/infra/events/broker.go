@@ -0,0 +1,62 @@
+package events
+
+import (
+	"sync"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+// размер буфера канала одного подписчика
+const subscriberBuffer = 64
+
+type Broker struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[chan domain.BoardEvent]struct{}
+}
+
+func NewBroker() *Broker {
+	return &Broker{
+		subscribers: make(map[string]map[chan domain.BoardEvent]struct{}),
+	}
+}
+
+func (b *Broker) Publish(event domain.BoardEvent) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch := range b.subscribers[event.BoardID] {
+		select {
+		case ch <- event:
+		default:
+			// медленный подписчик не должен блокировать сервисы,
+			// событие для него теряется
+		}
+	}
+}
+
+func (b *Broker) Subscribe(boardID string) (<-chan domain.BoardEvent, func()) {
+	ch := make(chan domain.BoardEvent, subscriberBuffer)
+
+	b.mu.Lock()
+	if b.subscribers[boardID] == nil {
+		b.subscribers[boardID] = make(map[chan domain.BoardEvent]struct{})
+	}
+	b.subscribers[boardID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+
+			delete(b.subscribers[boardID], ch)
+			if len(b.subscribers[boardID]) == 0 {
+				delete(b.subscribers, boardID)
+			}
+			close(ch)
+		})
+	}
+
+	return ch, unsubscribe
+}
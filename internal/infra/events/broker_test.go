@@ -0,0 +1,49 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+func TestBrokerDeliversOnlyToBoardSubscribers(t *testing.T) {
+	broker := NewBroker()
+
+	first, unsubscribeFirst := broker.Subscribe("board-1")
+	defer unsubscribeFirst()
+
+	second, unsubscribeSecond := broker.Subscribe("board-2")
+	defer unsubscribeSecond()
+
+	broker.Publish(domain.BoardEvent{Type: domain.EventTaskCreated, BoardID: "board-1"})
+
+	select {
+	case event := <-first:
+		if event.Type != domain.EventTaskCreated {
+			t.Errorf("expected %s, got %s", domain.EventTaskCreated, event.Type)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected event for board-1 subscriber")
+	}
+
+	select {
+	case event := <-second:
+		t.Fatalf("unexpected event for board-2 subscriber: %v", event)
+	default:
+	}
+}
+
+func TestBrokerUnsubscribeClosesChannel(t *testing.T) {
+	broker := NewBroker()
+
+	ch, unsubscribe := broker.Subscribe("board-1")
+	unsubscribe()
+	unsubscribe()
+
+	if _, ok := <-ch; ok {
+		t.Fatal("expected closed channel")
+	}
+
+	broker.Publish(domain.BoardEvent{Type: domain.EventTaskCreated, BoardID: "board-1"})
+}
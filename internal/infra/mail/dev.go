@@ -0,0 +1,54 @@
+package mail
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/service"
+)
+
+// LogMailer печатает письма в лог вместо отправки. Только для разработки:
+// в лог попадают одноразовые токены.
+type LogMailer struct{}
+
+func NewLogMailer() *LogMailer {
+	return &LogMailer{}
+}
+
+func (m *LogMailer) Send(email service.Email) error {
+	log.Printf("mail to %s: %s\n%s", email.To, email.Subject, email.Body)
+	return nil
+}
+
+// FileMailer складывает письма в каталог в формате .eml,
+// их можно открыть любым почтовым клиентом.
+type FileMailer struct {
+	dir  string
+	from string
+	seq  atomic.Uint64
+}
+
+func NewFileMailer(dir, from string) (*FileMailer, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	return &FileMailer{dir: dir, from: from}, nil
+}
+
+func (m *FileMailer) Send(email service.Email) error {
+	now := time.Now()
+
+	msg, err := buildMessage(m.from, email, now)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%04d.eml", now.Format("20060102T150405.000000000"), m.seq.Add(1))
+
+	return os.WriteFile(filepath.Join(m.dir, name), msg, 0o600)
+}
@@ -0,0 +1,64 @@
+package mail
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/service"
+)
+
+func TestBuildMessageRejectsHeaderInjection(t *testing.T) {
+	_, err := buildMessage("noreply@example.com", service.Email{
+		To:      "user@example.com\r\nBcc: attacker@example.com",
+		Subject: "Hello",
+		Body:    "text",
+	}, time.Now())
+
+	if err == nil {
+		t.Error("expected error for recipient with line break")
+	}
+}
+
+func TestFileMailerWritesMessage(t *testing.T) {
+	dir := t.TempDir()
+
+	mailer, err := NewFileMailer(dir, "noreply@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = mailer.Send(service.Email{
+		To:      "user@example.com",
+		Subject: "Сброс пароля",
+		Body:    "line one\nline two",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 file, got %d", len(entries))
+	}
+
+	data, err := os.ReadFile(dir + "/" + entries[0].Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := string(data)
+
+	if !strings.Contains(msg, "To: user@example.com\r\n") {
+		t.Errorf("missing To header:\n%s", msg)
+	}
+	if !strings.Contains(msg, "Subject: =?utf-8?q?") {
+		t.Errorf("expected encoded subject:\n%s", msg)
+	}
+	if !strings.HasSuffix(msg, "\r\n\r\nline one\r\nline two") {
+		t.Errorf("unexpected body:\n%s", msg)
+	}
+}
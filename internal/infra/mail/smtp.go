@@ -0,0 +1,66 @@
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/service"
+)
+
+type SMTPMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTPMailer отправляет письма через SMTP-сервер. Если username пуст,
+// авторизация не используется (например, локальный relay).
+func NewSMTPMailer(host, port, username, password, from string) *SMTPMailer {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &SMTPMailer{
+		addr: net.JoinHostPort(host, port),
+		auth: auth,
+		from: from,
+	}
+}
+
+func (m *SMTPMailer) Send(email service.Email) error {
+	msg, err := buildMessage(m.from, email, time.Now())
+	if err != nil {
+		return err
+	}
+
+	return smtp.SendMail(m.addr, m.auth, m.from, []string{email.To}, msg)
+}
+
+func buildMessage(from string, email service.Email, date time.Time) ([]byte, error) {
+	// перевод строки в заголовке позволил бы дописать в письмо свои заголовки
+	for _, value := range []string{from, email.To, email.Subject} {
+		if strings.ContainsAny(value, "\r\n") {
+			return nil, errors.New("mail header contains line break")
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", email.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", email.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(strings.ReplaceAll(email.Body, "\n", "\r\n"))
+
+	return buf.Bytes(), nil
+}
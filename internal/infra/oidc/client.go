@@ -0,0 +1,403 @@
+package oidc
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/ovk741/TasksStream/internal/infra/auth"
+	"github.com/ovk741/TasksStream/internal/service"
+)
+
+// Client реализует вход по authorization code + PKCE (S256).
+// Конфигурация провайдера берётся из /.well-known/openid-configuration
+// при первом обращении, так что сервис стартует и при недоступном провайдере.
+type Client struct {
+	issuer       string
+	clientID     string
+	clientSecret string
+	redirectURL  string
+	httpClient   *http.Client
+
+	mu        sync.Mutex
+	discovery *discovery
+	keys      map[string]interface{}
+}
+
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+func NewClient(issuer, clientID, clientSecret, redirectURL string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &Client{
+		issuer:       strings.TrimSuffix(issuer, "/"),
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		redirectURL:  redirectURL,
+		httpClient:   httpClient,
+	}
+}
+
+func (c *Client) Issuer() string {
+	return c.issuer
+}
+
+func (c *Client) AuthCodeURL(state, nonce, codeChallenge string) (string, error) {
+	d, err := c.getDiscovery()
+	if err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(d.AuthorizationEndpoint)
+	if err != nil {
+		return "", err
+	}
+
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", c.clientID)
+	q.Set("redirect_uri", c.redirectURL)
+	q.Set("scope", "openid email profile")
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	q.Set("code_challenge", codeChallenge)
+	q.Set("code_challenge_method", "S256")
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+func (c *Client) Exchange(code, codeVerifier, nonce string) (service.OIDCIdentity, error) {
+	d, err := c.getDiscovery()
+	if err != nil {
+		return service.OIDCIdentity{}, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", c.redirectURL)
+	form.Set("client_id", c.clientID)
+	form.Set("code_verifier", codeVerifier)
+
+	req, err := http.NewRequest(http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return service.OIDCIdentity{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if c.clientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(c.clientID), url.QueryEscape(c.clientSecret))
+	}
+
+	var response struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := c.doJSON(req, &response); err != nil {
+		return service.OIDCIdentity{}, fmt.Errorf("token exchange: %w", err)
+	}
+	if response.IDToken == "" {
+		return service.OIDCIdentity{}, errors.New("token response has no id_token")
+	}
+
+	return c.verifyIDToken(response.IDToken, d.Issuer, nonce)
+}
+
+func (c *Client) verifyIDToken(rawToken, issuer, nonce string) (service.OIDCIdentity, error) {
+	token, err := jwt.Parse(rawToken, c.verificationKey)
+	if err != nil || !token.Valid {
+		return service.OIDCIdentity{}, fmt.Errorf("invalid id_token: %v", err)
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return service.OIDCIdentity{}, errors.New("invalid id_token claims")
+	}
+
+	if iss, _ := claims["iss"].(string); iss != issuer {
+		return service.OIDCIdentity{}, errors.New("unexpected id_token issuer")
+	}
+	if !audienceContains(claims["aud"], c.clientID) {
+		return service.OIDCIdentity{}, errors.New("id_token is issued for another client")
+	}
+	if _, ok := claims["exp"]; !ok {
+		return service.OIDCIdentity{}, errors.New("id_token has no exp")
+	}
+	if got, _ := claims["nonce"].(string); got == "" || got != nonce {
+		return service.OIDCIdentity{}, errors.New("id_token nonce mismatch")
+	}
+
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return service.OIDCIdentity{}, errors.New("id_token has no sub")
+	}
+
+	email, _ := claims["email"].(string)
+
+	return service.OIDCIdentity{
+		Subject:       subject,
+		Email:         email,
+		EmailVerified: emailVerified(claims["email_verified"]),
+	}, nil
+}
+
+// verificationKey находит ключ провайдера по kid. Неизвестный kid означает
+// ротацию ключей у провайдера, поэтому JWKS перечитывается один раз.
+func (c *Client) verificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	key, err := c.lookupKey(kid, false)
+	if err != nil {
+		key, err = c.lookupKey(kid, true)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !methodMatchesKey(token.Method, key) {
+		return nil, errors.New("unexpected signing method")
+	}
+
+	return key, nil
+}
+
+func (c *Client) lookupKey(kid string, refresh bool) (interface{}, error) {
+	c.mu.Lock()
+	keys := c.keys
+	c.mu.Unlock()
+
+	if keys == nil || refresh {
+		d, err := c.getDiscovery()
+		if err != nil {
+			return nil, err
+		}
+
+		keys, err = c.fetchKeys(d.JWKSURI)
+		if err != nil {
+			return nil, err
+		}
+
+		c.mu.Lock()
+		c.keys = keys
+		c.mu.Unlock()
+	}
+
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key, nil
+		}
+	}
+
+	key, ok := keys[kid]
+	if !ok {
+		return nil, errors.New("unknown kid")
+	}
+
+	return key, nil
+}
+
+func (c *Client) getDiscovery() (*discovery, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.discovery != nil {
+		return c.discovery, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, c.issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var d discovery
+	if err := c.doJSON(req, &d); err != nil {
+		return nil, fmt.Errorf("oidc discovery: %w", err)
+	}
+
+	// OpenID Connect Discovery 1.0, раздел 4.3
+	if strings.TrimSuffix(d.Issuer, "/") != c.issuer {
+		return nil, fmt.Errorf("oidc discovery: issuer %q does not match %q", d.Issuer, c.issuer)
+	}
+	if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "" {
+		return nil, errors.New("oidc discovery: incomplete provider metadata")
+	}
+
+	c.discovery = &d
+	return c.discovery, nil
+}
+
+func (c *Client) fetchKeys(jwksURI string) (map[string]interface{}, error) {
+	req, err := http.NewRequest(http.MethodGet, jwksURI, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := c.doJSON(req, &set); err != nil {
+		return nil, fmt.Errorf("oidc jwks: %w", err)
+	}
+
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.publicKey()
+		if err != nil {
+			// ключи неподдерживаемых типов пропускаем
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	return keys, nil
+}
+
+func (c *Client) doJSON(req *http.Request, dst interface{}) error {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return json.Unmarshal(body, dst)
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key size")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// алгоритм токена должен соответствовать типу ключа; HS* не принимается никогда
+func methodMatchesKey(method jwt.SigningMethod, key interface{}) bool {
+	if method == nil {
+		return false
+	}
+
+	switch key.(type) {
+	case *rsa.PublicKey:
+		_, ok := method.(*jwt.SigningMethodRSA)
+		return ok
+	case *ecdsa.PublicKey:
+		return method.Alg() == jwt.SigningMethodES256.Alg()
+	case ed25519.PublicKey:
+		return method.Alg() == auth.SigningMethodEdDSA.Alg()
+	}
+
+	return false
+}
+
+func audienceContains(aud interface{}, clientID string) bool {
+	switch v := aud.(type) {
+	case string:
+		return v == clientID
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok && s == clientID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// некоторые провайдеры отдают email_verified строкой
+func emailVerified(v interface{}) bool {
+	switch value := v.(type) {
+	case bool:
+		return value
+	case string:
+		return value == "true"
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package oidc
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+
+	"github.com/ovk741/TasksStream/internal/infra/oidc/oidctest"
+)
+
+const (
+	testClientID    = "tasksstream"
+	testRedirectURL = "http://localhost:8080/auth/oidc/callback"
+)
+
+func challengeFor(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func authorize(t *testing.T, provider *oidctest.Provider, client *Client, state, nonce, verifier string) string {
+	t.Helper()
+
+	authURL, err := client.AuthCodeURL(state, nonce, challengeFor(verifier))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	redirect, err := provider.Authorize(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if redirect.Query().Get("state") != state {
+		t.Fatalf("expected state %q, got %q", state, redirect.Query().Get("state"))
+	}
+
+	return redirect.Query().Get("code")
+}
+
+func TestClientCompletesAuthorizationCodeFlow(t *testing.T) {
+	provider := oidctest.NewProvider(t, testClientID)
+	provider.SetUser(oidctest.Identity{Subject: "sub-1", Email: "user@example.com", EmailVerified: true})
+
+	client := NewClient(provider.Issuer(), testClientID, "secret", testRedirectURL, nil)
+
+	code := authorize(t, provider, client, "state-1", "nonce-1", "verifier-1")
+
+	identity, err := client.Exchange(code, "verifier-1", "nonce-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if identity.Subject != "sub-1" || identity.Email != "user@example.com" || !identity.EmailVerified {
+		t.Errorf("unexpected identity: %+v", identity)
+	}
+
+	// code одноразовый
+	if _, err := client.Exchange(code, "verifier-1", "nonce-1"); err == nil {
+		t.Error("expected reused code to be rejected")
+	}
+}
+
+func TestClientRejectsWrongCodeVerifier(t *testing.T) {
+	provider := oidctest.NewProvider(t, testClientID)
+	provider.SetUser(oidctest.Identity{Subject: "sub-1", Email: "user@example.com", EmailVerified: true})
+
+	client := NewClient(provider.Issuer(), testClientID, "", testRedirectURL, nil)
+
+	code := authorize(t, provider, client, "state-1", "nonce-1", "verifier-1")
+
+	if _, err := client.Exchange(code, "another-verifier", "nonce-1"); err == nil {
+		t.Error("expected exchange with wrong PKCE verifier to fail")
+	}
+}
+
+func TestClientRejectsNonceMismatch(t *testing.T) {
+	provider := oidctest.NewProvider(t, testClientID)
+	provider.SetUser(oidctest.Identity{Subject: "sub-1", Email: "user@example.com", EmailVerified: true})
+
+	client := NewClient(provider.Issuer(), testClientID, "", testRedirectURL, nil)
+
+	code := authorize(t, provider, client, "state-1", "nonce-1", "verifier-1")
+
+	if _, err := client.Exchange(code, "verifier-1", "nonce-2"); err == nil {
+		t.Error("expected id_token with foreign nonce to be rejected")
+	}
+}
+
+func TestClientRejectsTokenForAnotherClient(t *testing.T) {
+	provider := oidctest.NewProvider(t, "another-client")
+	provider.SetUser(oidctest.Identity{Subject: "sub-1"})
+
+	// code получен другим клиентом того же провайдера, и id_token выписан для него
+	other := NewClient(provider.Issuer(), "another-client", "", testRedirectURL, nil)
+	code := authorize(t, provider, other, "state-1", "nonce-1", "verifier-1")
+
+	client := NewClient(provider.Issuer(), testClientID, "", testRedirectURL, nil)
+
+	if _, err := client.Exchange(code, "verifier-1", "nonce-1"); err == nil {
+		t.Error("expected id_token with foreign audience to be rejected")
+	}
+}
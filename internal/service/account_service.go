@@ -0,0 +1,281 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/storage"
+)
+
+const (
+	passwordResetTokenPrefix     = "tsr_"
+	emailVerificationTokenPrefix = "tsv_"
+
+	passwordResetTTL     = time.Hour
+	emailVerificationTTL = 48 * time.Hour
+)
+
+type AccountService interface {
+	// RequestPasswordReset и ResendVerification не сообщают,
+	// существует ли адрес, чтобы по ним нельзя было перебирать пользователей
+	RequestPasswordReset(email string) error
+	ResetPassword(token, newPassword string) error
+
+	SendVerification(user domain.User) error
+	ResendVerification(email string) error
+	VerifyEmail(token string) error
+}
+
+type accountService struct {
+	userRepo         storage.UserRepository
+	userTokenRepo    storage.UserTokenRepository
+	refreshTokenRepo storage.RefreshTokenRepository
+	sessionRepo      storage.SessionRepository
+	hasher           PasswordHasher
+	mailer           Mailer
+	invitations      InvitationClaimer
+	baseURL          string
+	generateID       func() string
+}
+
+// NewAccountService; baseURL — адрес фронтенда, на который ведут ссылки из писем.
+func NewAccountService(
+	userRepo storage.UserRepository,
+	userTokenRepo storage.UserTokenRepository,
+	refreshTokenRepo storage.RefreshTokenRepository,
+	sessionRepo storage.SessionRepository,
+	hasher PasswordHasher,
+	mailer Mailer,
+	invitations InvitationClaimer,
+	baseURL string,
+	generateID func() string,
+) AccountService {
+	return &accountService{
+		userRepo:         userRepo,
+		userTokenRepo:    userTokenRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		sessionRepo:      sessionRepo,
+		hasher:           hasher,
+		mailer:           mailer,
+		invitations:      invitations,
+		baseURL:          baseURL,
+		generateID:       generateID,
+	}
+}
+
+func (s *accountService) RequestPasswordReset(email string) error {
+	if email == "" {
+		return domain.ErrInvalidInput
+	}
+
+	user, err := s.userRepo.GetByEmail(email)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	token, err := s.issueToken(user.ID, domain.TokenPurposePasswordReset, passwordResetTokenPrefix, passwordResetTTL)
+	if err != nil {
+		return err
+	}
+
+	// ошибку отправки клиенту не отдаём: для несуществующего адреса письмо
+	// не отправляется вовсе, и по ответу стало бы видно, что адрес есть
+	err = s.mailer.Send(Email{
+		To:      user.Email,
+		Subject: "Сброс пароля TasksStream",
+		Body: fmt.Sprintf(
+			"Чтобы задать новый пароль, перейдите по ссылке:\n\n%s\n\n"+
+				"Ссылка действует %d мин. Если вы не запрашивали сброс, просто проигнорируйте письмо.\n",
+			s.link("/reset-password", token),
+			int(passwordResetTTL.Minutes()),
+		),
+	})
+	if err != nil {
+		log.Printf("send password reset email to user %s: %v", user.ID, err)
+	}
+
+	return nil
+}
+
+func (s *accountService) ResetPassword(token, newPassword string) error {
+	if token == "" || newPassword == "" {
+		return domain.ErrInvalidInput
+	}
+
+	stored, err := s.consumeToken(domain.TokenPurposePasswordReset, token)
+	if err != nil {
+		return err
+	}
+
+	hash, err := s.hasher.Hash(newPassword)
+	if err != nil {
+		return err
+	}
+
+	if err := s.userRepo.UpdatePassword(stored.UserID, hash); err != nil {
+		return err
+	}
+
+	// письмо дошло до владельца адреса — заодно считаем адрес подтверждённым
+	if err := s.userRepo.MarkEmailVerified(stored.UserID); err != nil {
+		return err
+	}
+
+	// после смены пароля все открытые сессии завершаются
+	now := time.Now()
+
+	if err := s.sessionRepo.RevokeAllForUser(stored.UserID, now); err != nil {
+		return err
+	}
+
+	if err := s.refreshTokenRepo.RevokeAllForUser(stored.UserID, now); err != nil {
+		return err
+	}
+
+	s.claimInvitations(stored.UserID)
+
+	return nil
+}
+
+func (s *accountService) SendVerification(user domain.User) error {
+	if user.EmailVerified {
+		return nil
+	}
+
+	token, err := s.issueToken(user.ID, domain.TokenPurposeEmailVerification, emailVerificationTokenPrefix, emailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(Email{
+		To:      user.Email,
+		Subject: "Подтвердите адрес в TasksStream",
+		Body: fmt.Sprintf(
+			"Чтобы подтвердить адрес, перейдите по ссылке:\n\n%s\n\nСсылка действует %d ч.\n",
+			s.link("/verify-email", token),
+			int(emailVerificationTTL.Hours()),
+		),
+	})
+}
+
+func (s *accountService) ResendVerification(email string) error {
+	if email == "" {
+		return domain.ErrInvalidInput
+	}
+
+	user, err := s.userRepo.GetByEmail(email)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	// по той же причине, что и в RequestPasswordReset
+	if err := s.SendVerification(user); err != nil {
+		log.Printf("send verification email to user %s: %v", user.ID, err)
+	}
+
+	return nil
+}
+
+func (s *accountService) VerifyEmail(token string) error {
+	if token == "" {
+		return domain.ErrInvalidInput
+	}
+
+	stored, err := s.consumeToken(domain.TokenPurposeEmailVerification, token)
+	if err != nil {
+		return err
+	}
+
+	if err := s.userRepo.MarkEmailVerified(stored.UserID); err != nil {
+		return err
+	}
+
+	s.claimInvitations(stored.UserID)
+
+	return nil
+}
+
+// claimInvitations превращает приглашения, отправленные на только что
+// подтверждённый адрес, в участие в досках. Подтверждение уже состоялось,
+// поэтому ошибка здесь только логируется: приглашения останутся в GET /invitations.
+func (s *accountService) claimInvitations(userID string) {
+	user, err := s.userRepo.GetByID(userID)
+	if err == nil {
+		err = s.invitations.ClaimPending(user)
+	}
+	if err != nil {
+		log.Printf("claim invitations for user %s: %v", userID, err)
+	}
+}
+
+// issueToken создаёт новый токен и гасит выданные ранее с тем же назначением,
+// так что действительна только ссылка из последнего письма
+func (s *accountService) issueToken(
+	userID string,
+	purpose domain.UserTokenPurpose,
+	prefix string,
+	ttl time.Duration,
+) (string, error) {
+	secret, err := generateSecret(prefix)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+
+	if err := s.userTokenRepo.InvalidateForUser(userID, purpose, now); err != nil {
+		return "", err
+	}
+
+	if err := s.userTokenRepo.Create(domain.UserToken{
+		ID:        s.generateID(),
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hashToken(secret),
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}); err != nil {
+		return "", err
+	}
+
+	return secret, nil
+}
+
+func (s *accountService) consumeToken(purpose domain.UserTokenPurpose, token string) (domain.UserToken, error) {
+	stored, err := s.userTokenRepo.GetByHash(purpose, hashToken(token))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.UserToken{}, domain.ErrInvalidCredentials
+		}
+		return domain.UserToken{}, err
+	}
+
+	now := time.Now()
+
+	if stored.UsedAt != nil || !stored.ExpiresAt.After(now) {
+		return domain.UserToken{}, domain.ErrInvalidCredentials
+	}
+
+	if err := s.userTokenRepo.MarkUsed(stored.ID, now); err != nil {
+		if errors.Is(err, domain.ErrConflict) {
+			return domain.UserToken{}, domain.ErrInvalidCredentials
+		}
+		return domain.UserToken{}, err
+	}
+
+	return stored, nil
+}
+
+func (s *accountService) link(path, token string) string {
+	return s.baseURL + path + "?token=" + url.QueryEscape(token)
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+type discardClaimer struct{}
+
+func (discardClaimer) ClaimPending(domain.User) error { return nil }
+
+func (r *fakeUserRepo) UpdatePassword(userID, passwordHash string) error {
+	user, ok := r.users[userID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	user.PasswordHash = passwordHash
+	r.users[userID] = user
+	return nil
+}
+
+func (r *fakeUserRepo) MarkEmailVerified(userID string) error {
+	user, ok := r.users[userID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	user.EmailVerified = true
+	r.users[userID] = user
+	return nil
+}
+
+type fakeUserTokenRepo struct {
+	tokens map[string]domain.UserToken
+}
+
+func (r *fakeUserTokenRepo) Create(token domain.UserToken) error {
+	r.tokens[token.ID] = token
+	return nil
+}
+
+func (r *fakeUserTokenRepo) GetByHash(purpose domain.UserTokenPurpose, tokenHash string) (domain.UserToken, error) {
+	for _, token := range r.tokens {
+		if token.Purpose == purpose && token.TokenHash == tokenHash {
+			return token, nil
+		}
+	}
+	return domain.UserToken{}, domain.ErrNotFound
+}
+
+func (r *fakeUserTokenRepo) MarkUsed(id string, usedAt time.Time) error {
+	token, ok := r.tokens[id]
+	if !ok || token.UsedAt != nil {
+		return domain.ErrConflict
+	}
+	token.UsedAt = &usedAt
+	r.tokens[id] = token
+	return nil
+}
+
+func (r *fakeUserTokenRepo) InvalidateForUser(userID string, purpose domain.UserTokenPurpose, usedAt time.Time) error {
+	for id, token := range r.tokens {
+		if token.UserID == userID && token.Purpose == purpose && token.UsedAt == nil {
+			token.UsedAt = &usedAt
+			r.tokens[id] = token
+		}
+	}
+	return nil
+}
+
+// recordingMailer запоминает письма; если задан err, отправка не удаётся
+type recordingMailer struct {
+	sent []Email
+	err  error
+}
+
+func (m *recordingMailer) Send(email Email) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.sent = append(m.sent, email)
+	return nil
+}
+
+type testAccount struct {
+	testAuth
+	service    AccountService
+	userTokens *fakeUserTokenRepo
+	mailer     *recordingMailer
+}
+
+// newTestAccountService работает поверх тех же хранилищ, что и authService,
+// чтобы проверять, как сброс пароля влияет на сессии
+func newTestAccountService() testAccount {
+	account := testAccount{
+		testAuth:   newTestAuthService(),
+		userTokens: &fakeUserTokenRepo{tokens: make(map[string]domain.UserToken)},
+		mailer:     &recordingMailer{},
+	}
+
+	n := 0
+	account.service = NewAccountService(
+		account.users,
+		account.userTokens,
+		account.refreshTokens,
+		account.sessions,
+		plainHasher{},
+		account.mailer,
+		discardClaimer{},
+		"http://localhost",
+		func() string {
+			n++
+			return "token-" + strconv.Itoa(n)
+		},
+	)
+
+	return account
+}
+
+var tokenInLink = regexp.MustCompile(`token=(\S+)`)
+
+// requestReset запрашивает сброс пароля user-1 и возвращает токен из письма
+func requestReset(t *testing.T, account testAccount) string {
+	t.Helper()
+
+	if err := account.service.RequestPasswordReset("user@example.com"); err != nil {
+		t.Fatalf("request reset: %v", err)
+	}
+	if len(account.mailer.sent) == 0 {
+		t.Fatal("expected a reset email")
+	}
+
+	match := tokenInLink.FindStringSubmatch(account.mailer.sent[len(account.mailer.sent)-1].Body)
+	if match == nil {
+		t.Fatal("reset email has no token")
+	}
+	token, err := url.QueryUnescape(match[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestResetPasswordTokenIsSingleUse(t *testing.T) {
+	account := newTestAccountService()
+	token := requestReset(t, account)
+
+	if err := account.service.ResetPassword(token, "new password"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if account.users.users["user-1"].PasswordHash != "hashed:new password" {
+		t.Error("expected password to change")
+	}
+
+	if err := account.service.ResetPassword(token, "another password"); !errors.Is(err, domain.ErrInvalidCredentials) {
+		t.Errorf("expected ErrInvalidCredentials for a used token, got %v", err)
+	}
+	if err := account.service.ResetPassword("unknown", "another password"); !errors.Is(err, domain.ErrInvalidCredentials) {
+		t.Errorf("expected ErrInvalidCredentials for an unknown token, got %v", err)
+	}
+	if account.users.users["user-1"].PasswordHash != "hashed:new password" {
+		t.Error("used token must not change the password again")
+	}
+}
+
+func TestResetPasswordTokenExpires(t *testing.T) {
+	account := newTestAccountService()
+	token := requestReset(t, account)
+
+	for id, stored := range account.userTokens.tokens {
+		stored.ExpiresAt = time.Now().Add(-time.Minute)
+		account.userTokens.tokens[id] = stored
+	}
+
+	if err := account.service.ResetPassword(token, "new password"); !errors.Is(err, domain.ErrInvalidCredentials) {
+		t.Errorf("expected ErrInvalidCredentials for an expired token, got %v", err)
+	}
+}
+
+func TestNewResetTokenInvalidatesPrevious(t *testing.T) {
+	account := newTestAccountService()
+	first := requestReset(t, account)
+	second := requestReset(t, account)
+
+	if err := account.service.ResetPassword(first, "new password"); !errors.Is(err, domain.ErrInvalidCredentials) {
+		t.Errorf("expected ErrInvalidCredentials for a superseded token, got %v", err)
+	}
+	if err := account.service.ResetPassword(second, "new password"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestResetPasswordEndsSessions(t *testing.T) {
+	account := newTestAccountService()
+	user := account.users.users["user-1"]
+	user.EmailVerified = false
+	account.users.users["user-1"] = user
+
+	tokens := loginTestUser(t, account.testAuth)
+
+	if err := account.service.ResetPassword(requestReset(t, account), "new password"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(account.sessions.sessions) == 0 {
+		t.Fatal("expected a session from login")
+	}
+	for _, session := range account.sessions.sessions {
+		if session.RevokedAt == nil {
+			t.Errorf("session %s is still active", session.ID)
+		}
+	}
+	if _, err := account.testAuth.service.Refresh(tokens.RefreshToken, ClientInfo{}); !errors.Is(err, domain.ErrInvalidCredentials) {
+		t.Errorf("expected refresh to fail after reset, got %v", err)
+	}
+	if !account.users.users["user-1"].EmailVerified {
+		t.Error("reset must confirm the email address")
+	}
+}
+
+func TestRequestPasswordResetHidesDelivery(t *testing.T) {
+	account := newTestAccountService()
+	account.mailer.err = errors.New("smtp is down")
+
+	if err := account.service.RequestPasswordReset("user@example.com"); err != nil {
+		t.Errorf("expected mailer error to be hidden, got %v", err)
+	}
+	if err := account.service.RequestPasswordReset("nobody@example.com"); err != nil {
+		t.Errorf("expected no error for an unknown address, got %v", err)
+	}
+	if err := account.service.ResendVerification("user@example.com"); err != nil {
+		t.Errorf("expected no error for resend, got %v", err)
+	}
+}
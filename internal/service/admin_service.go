@@ -0,0 +1,239 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/storage"
+)
+
+const (
+	adminDefaultPageSize = 50
+	adminMaxPageSize     = 200
+)
+
+// AdminService — операции администратора инстанса. Все методы первым
+// аргументом принимают ID того, кто их вызывает, и проверяют флаг IsAdmin.
+type AdminService interface {
+	// ListUsers ищет по подстроке email или имени; пустой запрос — все пользователи
+	ListUsers(adminID, query string, limit, offset int) ([]domain.UserAccount, error)
+	// SetUserDisabled отключает пользователя, завершая все его сессии,
+	// или включает обратно
+	SetUserDisabled(adminID, userID string, disabled bool) error
+	ForceLogout(adminID, userID string) error
+
+	ListBoards(adminID string, limit, offset int) ([]domain.BoardOverview, error)
+	// ReassignOwner назначает владельца доски; прежний владелец остаётся редактором
+	ReassignOwner(adminID, boardID, userID string) error
+}
+
+// SessionTerminator завершает все сессии пользователя; его реализует AuthService.
+type SessionTerminator interface {
+	LogoutAll(userID string) error
+}
+
+type adminService struct {
+	userRepo        storage.UserRepository
+	boardRepo       storage.BoardRepository
+	boardMemberRepo storage.BoardMemberRepository
+	sessions        SessionTerminator
+	events          EventPublisher
+	generateID      func() string
+}
+
+func NewAdminService(
+	userRepo storage.UserRepository,
+	boardRepo storage.BoardRepository,
+	boardMemberRepo storage.BoardMemberRepository,
+	sessions SessionTerminator,
+	events EventPublisher,
+	generateID func() string,
+) AdminService {
+	return &adminService{
+		userRepo:        userRepo,
+		boardRepo:       boardRepo,
+		boardMemberRepo: boardMemberRepo,
+		sessions:        sessions,
+		events:          events,
+		generateID:      generateID,
+	}
+}
+
+func (s *adminService) ListUsers(adminID, query string, limit, offset int) ([]domain.UserAccount, error) {
+	if err := s.requireAdmin(adminID); err != nil {
+		return nil, err
+	}
+
+	limit, err := adminPage(limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := s.userRepo.Search(strings.TrimSpace(query), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.UserAccount, 0, len(users))
+	for _, u := range users {
+		result = append(result, u.Account())
+	}
+
+	return result, nil
+}
+
+func (s *adminService) SetUserDisabled(adminID, userID string, disabled bool) error {
+	if userID == "" {
+		return domain.ErrInvalidInput
+	}
+
+	if err := s.requireAdmin(adminID); err != nil {
+		return err
+	}
+
+	// иначе администратор может случайно лишить доступа сам себя
+	if disabled && userID == adminID {
+		return domain.ErrInvalidInput
+	}
+
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return err
+	}
+
+	var disabledAt *time.Time
+	if disabled {
+		now := time.Now()
+		disabledAt = &now
+	}
+
+	if err := s.userRepo.SetDisabled(userID, disabledAt); err != nil {
+		return err
+	}
+
+	log.Printf("admin %s set user %s disabled=%t", adminID, userID, disabled)
+
+	if !disabled {
+		return nil
+	}
+
+	// access-токены и так отклоняются, а refresh-токены отзываем,
+	// чтобы после включения пользователь вошёл заново
+	return s.sessions.LogoutAll(userID)
+}
+
+func (s *adminService) ForceLogout(adminID, userID string) error {
+	if userID == "" {
+		return domain.ErrInvalidInput
+	}
+
+	if err := s.requireAdmin(adminID); err != nil {
+		return err
+	}
+
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return err
+	}
+
+	if err := s.sessions.LogoutAll(userID); err != nil {
+		return err
+	}
+
+	log.Printf("admin %s logged out user %s", adminID, userID)
+
+	return nil
+}
+
+func (s *adminService) ListBoards(adminID string, limit, offset int) ([]domain.BoardOverview, error) {
+	if err := s.requireAdmin(adminID); err != nil {
+		return nil, err
+	}
+
+	limit, err := adminPage(limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.boardRepo.ListOverview(limit, offset)
+}
+
+func (s *adminService) ReassignOwner(adminID, boardID, userID string) error {
+	if boardID == "" || userID == "" {
+		return domain.ErrInvalidInput
+	}
+
+	if err := s.requireAdmin(adminID); err != nil {
+		return err
+	}
+
+	if _, err := s.boardRepo.GetByID(boardID); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	if user.Disabled() {
+		return domain.ErrInvalidInput
+	}
+
+	owner := domain.BoardMember{
+		ID:        s.generateID(),
+		BoardID:   boardID,
+		UserID:    userID,
+		Role:      domain.BoardRoleOwner,
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.boardMemberRepo.ReassignOwner(owner, domain.BoardRoleEditor); err != nil {
+		return err
+	}
+
+	log.Printf("admin %s reassigned board %s to user %s", adminID, boardID, userID)
+
+	s.events.Publish(newBoardEvent(domain.EventMemberRoleChanged, boardID, adminID, map[string]string{
+		"user_id": userID,
+		"role":    string(domain.BoardRoleOwner),
+	}))
+
+	return nil
+}
+
+func (s *adminService) requireAdmin(userID string) error {
+	if userID == "" {
+		return domain.ErrInvalidCredentials
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.ErrForbidden
+		}
+		return err
+	}
+
+	if !user.IsAdmin || user.Disabled() {
+		return domain.ErrForbidden
+	}
+
+	return nil
+}
+
+// adminPage подставляет размер страницы по умолчанию и ограничивает сверху
+func adminPage(limit, offset int) (int, error) {
+	if limit < 0 || offset < 0 {
+		return 0, domain.ErrInvalidInput
+	}
+
+	if limit == 0 {
+		return adminDefaultPageSize, nil
+	}
+	if limit > adminMaxPageSize {
+		return adminMaxPageSize, nil
+	}
+
+	return limit, nil
+}
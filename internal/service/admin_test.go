@@ -0,0 +1,137 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+func (r *fakeUserRepo) SetDisabled(userID string, disabledAt *time.Time) error {
+	user, ok := r.users[userID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	user.DisabledAt = disabledAt
+	r.users[userID] = user
+	return nil
+}
+
+func (r *fakeMemberRepo) ReassignOwner(owner domain.BoardMember, previousOwnerRole domain.BoardRole) error {
+	for key, role := range r.roles {
+		if role == domain.BoardRoleOwner && key != owner.BoardID+"|"+owner.UserID {
+			r.roles[key] = previousOwnerRole
+		}
+	}
+	r.roles[owner.BoardID+"|"+owner.UserID] = domain.BoardRoleOwner
+	return nil
+}
+
+type recordingSessions struct {
+	loggedOut []string
+}
+
+func (s *recordingSessions) LogoutAll(userID string) error {
+	s.loggedOut = append(s.loggedOut, userID)
+	return nil
+}
+
+func newTestAdminService() (AdminService, *fakeUserRepo, *fakeMemberRepo, *recordingSessions) {
+	users := &fakeUserRepo{users: map[string]domain.User{
+		"admin": {ID: "admin", Email: "admin@example.com", IsAdmin: true},
+		"alice": {ID: "alice", Email: "alice@example.com"},
+		"bob":   {ID: "bob", Email: "bob@example.com"},
+	}}
+	members := &fakeMemberRepo{roles: map[string]domain.BoardRole{"board-1|alice": domain.BoardRoleOwner}}
+	sessions := &recordingSessions{}
+
+	service := NewAdminService(users, fakeBoardRepo{}, members, sessions, discardEvents{}, func() string { return "id" })
+
+	return service, users, members, sessions
+}
+
+func TestAdminServiceRequiresAdmin(t *testing.T) {
+	service, _, _, _ := newTestAdminService()
+
+	if _, err := service.ListBoards("alice", 0, 0); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("expected ErrForbidden, got %v", err)
+	}
+	if err := service.ForceLogout("alice", "bob"); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("expected ErrForbidden, got %v", err)
+	}
+}
+
+func TestAdminServiceDisableUser(t *testing.T) {
+	service, users, _, sessions := newTestAdminService()
+
+	if err := service.SetUserDisabled("admin", "bob", true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !users.users["bob"].Disabled() {
+		t.Error("expected bob to be disabled")
+	}
+	if len(sessions.loggedOut) != 1 || sessions.loggedOut[0] != "bob" {
+		t.Errorf("expected bob's sessions to be revoked, got %v", sessions.loggedOut)
+	}
+
+	if err := service.SetUserDisabled("admin", "bob", false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if users.users["bob"].Disabled() {
+		t.Error("expected bob to be enabled")
+	}
+}
+
+func TestAdminServiceCannotDisableSelf(t *testing.T) {
+	service, _, _, _ := newTestAdminService()
+
+	if err := service.SetUserDisabled("admin", "admin", true); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput, got %v", err)
+	}
+}
+
+func TestAdminServiceReassignOwner(t *testing.T) {
+	service, users, members, _ := newTestAdminService()
+
+	if err := service.ReassignOwner("admin", "board-1", "bob"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if role := members.roles["board-1|bob"]; role != domain.BoardRoleOwner {
+		t.Errorf("expected bob to own the board, got %s", role)
+	}
+	if role := members.roles["board-1|alice"]; role != domain.BoardRoleEditor {
+		t.Errorf("expected previous owner to become editor, got %s", role)
+	}
+
+	now := time.Now()
+	users.users["alice"] = domain.User{ID: "alice", DisabledAt: &now}
+
+	if err := service.ReassignOwner("admin", "board-1", "alice"); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput for disabled user, got %v", err)
+	}
+}
+
+func TestAuthenticatorRejectsDisabledUser(t *testing.T) {
+	now := time.Now()
+	users := &fakeUserRepo{users: map[string]domain.User{
+		"bob": {ID: "bob", DisabledAt: &now},
+	}}
+
+	authenticator := NewAuthenticator(acceptingJWT{userID: "bob"}, nil, users)
+
+	if _, err := authenticator.Authenticate("access-token"); !errors.Is(err, domain.ErrUserDisabled) {
+		t.Errorf("expected ErrUserDisabled, got %v", err)
+	}
+}
+
+type acceptingJWT struct {
+	JWTManager
+	userID string
+}
+
+func (j acceptingJWT) ParseAccessToken(string) (string, error) {
+	return j.userID, nil
+}
@@ -2,6 +2,9 @@ package service
 
 import (
 	"errors"
+	"log"
+	"net/mail"
+	"strings"
 	"time"
 
 	"github.com/ovk741/TasksStream/internal/domain"
@@ -10,8 +13,16 @@ import (
 
 type AuthService interface {
 	Register(email, password string) error
-	Login(email, password string) (Tokens, error)
-	Refresh(refreshToken string) (Tokens, error)
+	// Login при включённой 2FA возвращает не токены, а challenge-токен,
+	// который вместе с кодом передаётся в LoginTwoFactor
+	Login(email, password string, client ClientInfo) (LoginResult, error)
+	LoginTwoFactor(challengeToken, code string, client ClientInfo) (Tokens, error)
+	Refresh(refreshToken string, client ClientInfo) (Tokens, error)
+	Logout(refreshToken string) error
+	LogoutAll(userID string) error
+
+	ListSessions(userID string) ([]domain.Session, error)
+	RevokeSession(userID, sessionID string) error
 }
 
 type Tokens struct {
@@ -19,11 +30,35 @@ type Tokens struct {
 	RefreshToken string
 }
 
+type LoginResult struct {
+	Tokens         Tokens
+	ChallengeToken string
+}
+
+// ClientInfo описывает устройство, с которого выполняется вход или обновление токенов.
+type ClientInfo struct {
+	UserAgent string
+	IP        string
+}
+
+// VerificationSender отправляет письмо для подтверждения адреса.
+type VerificationSender interface {
+	SendVerification(user domain.User) error
+}
+
 type authService struct {
-	userRepo   storage.UserRepository
-	hasher     PasswordHasher
-	jwt        JWTManager
-	generateID func() string
+	userRepo         storage.UserRepository
+	refreshTokenRepo storage.RefreshTokenRepository
+	sessionRepo      storage.SessionRepository
+	recoveryCodeRepo storage.RecoveryCodeRepository
+	verifier         VerificationSender
+	limiter          LoginLimiter
+	hasher           PasswordHasher
+	jwt              JWTManager
+	sessions         *sessionStarter
+	// без подтверждённого адреса вход запрещён
+	requireVerifiedEmail bool
+	generateID           func() string
 }
 
 type JWTManager interface {
@@ -31,19 +66,35 @@ type JWTManager interface {
 	GenerateRefreshToken(userID string) (string, error)
 	ParseRefreshToken(token string) (string, error)
 	ParseAccessToken(token string) (string, error)
+	GenerateChallengeToken(userID string) (string, error)
+	ParseChallengeToken(token string) (string, error)
+	RefreshTTL() time.Duration
 }
 
 func NewAuthService(
 	userRepo storage.UserRepository,
+	refreshTokenRepo storage.RefreshTokenRepository,
+	sessionRepo storage.SessionRepository,
+	recoveryCodeRepo storage.RecoveryCodeRepository,
+	verifier VerificationSender,
+	limiter LoginLimiter,
 	hasher PasswordHasher,
 	jwt JWTManager,
+	requireVerifiedEmail bool,
 	generateID func() string,
 ) AuthService {
 	return &authService{
-		userRepo:   userRepo,
-		hasher:     hasher,
-		jwt:        jwt,
-		generateID: generateID,
+		userRepo:             userRepo,
+		refreshTokenRepo:     refreshTokenRepo,
+		sessionRepo:          sessionRepo,
+		recoveryCodeRepo:     recoveryCodeRepo,
+		verifier:             verifier,
+		limiter:              limiter,
+		hasher:               hasher,
+		jwt:                  jwt,
+		sessions:             newSessionStarter(refreshTokenRepo, sessionRepo, jwt, generateID),
+		requireVerifiedEmail: requireVerifiedEmail,
+		generateID:           generateID,
 	}
 }
 
@@ -52,6 +103,10 @@ func (s *authService) Register(email, password string) error {
 		return domain.ErrInvalidInput
 	}
 
+	if !validEmail(email) {
+		return domain.ErrInvalidInput
+	}
+
 	_, err := s.userRepo.GetByEmail(email)
 	if err == nil {
 		return domain.ErrUserAlreadyExists
@@ -72,44 +127,115 @@ func (s *authService) Register(email, password string) error {
 		CreatedAt:    time.Now(),
 	}
 
-	return s.userRepo.Create(user)
+	if err := s.userRepo.Create(user); err != nil {
+		return err
+	}
+
+	// ошибка отправки не отменяет регистрацию:
+	// письмо можно запросить повторно через /auth/verify-email/resend
+	if err := s.verifier.SendVerification(user); err != nil {
+		log.Printf("send verification email to user %s: %v", user.ID, err)
+	}
+
+	return nil
 }
 
-func (s *authService) Login(email, password string) (Tokens, error) {
+func (s *authService) Login(email, password string, client ClientInfo) (LoginResult, error) {
 	if email == "" || password == "" {
-		return Tokens{}, domain.ErrInvalidInput
+		return LoginResult{}, domain.ErrInvalidInput
+	}
+
+	// пока вход заблокирован, пароль даже не проверяем
+	if err := s.limiter.Check(email, client.IP); err != nil {
+		return LoginResult{}, err
 	}
 
 	user, err := s.userRepo.GetByEmail(email)
 	if err != nil {
+		// несуществующие адреса тоже считаются, иначе по блокировке
+		// можно было бы определить, зарегистрирован ли адрес
 		if errors.Is(err, domain.ErrNotFound) {
-			return Tokens{}, domain.ErrInvalidCredentials
+			return LoginResult{}, loginFailed(s.limiter, email, client)
 		}
-		return Tokens{}, err
+		return LoginResult{}, err
 	}
 
 	// bcrypt check
 	if err := s.hasher.Compare(user.PasswordHash, password); err != nil {
+		return LoginResult{}, loginFailed(s.limiter, email, client)
+	}
+
+	if s.requireVerifiedEmail && !user.EmailVerified {
+		return LoginResult{}, domain.ErrEmailNotVerified
+	}
+
+	if user.Disabled() {
+		return LoginResult{}, domain.ErrUserDisabled
+	}
+
+	result, err := s.sessions.StartSession(user, client)
+	if err != nil || result.ChallengeToken != "" {
+		return result, err
+	}
+
+	if err := s.limiter.Success(email, client.IP); err != nil {
+		log.Printf("reset login attempts for %s: %v", user.ID, err)
+	}
+
+	return result, nil
+}
+
+func (s *authService) LoginTwoFactor(challengeToken, code string, client ClientInfo) (Tokens, error) {
+	if challengeToken == "" || code == "" {
+		return Tokens{}, domain.ErrInvalidInput
+	}
+
+	userID, err := s.jwt.ParseChallengeToken(challengeToken)
+	if err != nil {
 		return Tokens{}, domain.ErrInvalidCredentials
 	}
 
-	accessToken, err := s.jwt.GenerateAccessToken(user.ID)
+	user, err := s.userRepo.GetByID(userID)
 	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return Tokens{}, domain.ErrInvalidCredentials
+		}
 		return Tokens{}, err
 	}
 
-	refreshToken, err := s.jwt.GenerateRefreshToken(user.ID)
+	// 2FA могли отключить, пока challenge-токен был жив
+	if !user.TOTPEnabled {
+		return Tokens{}, domain.ErrInvalidCredentials
+	}
+	if user.Disabled() {
+		return Tokens{}, domain.ErrUserDisabled
+	}
+
+	// подбор кода считается вместе с подбором пароля того же аккаунта
+	if err := s.limiter.Check(user.Email, client.IP); err != nil {
+		return Tokens{}, err
+	}
+
+	if err := verifySecondFactor(user, code, s.userRepo, s.recoveryCodeRepo, s.hasher); err != nil {
+		if errors.Is(err, domain.ErrInvalidCredentials) {
+			return Tokens{}, loginFailed(s.limiter, user.Email, client)
+		}
+		return Tokens{}, err
+	}
+
+	tokens, err := s.sessions.start(user.ID, client)
 	if err != nil {
 		return Tokens{}, err
 	}
 
-	return Tokens{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-	}, nil
+	if err := s.limiter.Success(user.Email, client.IP); err != nil {
+		log.Printf("reset login attempts for %s: %v", user.ID, err)
+	}
+
+	return tokens, nil
 }
 
-func (s *authService) Refresh(refreshToken string) (Tokens, error) {
+func (s *authService) Refresh(refreshToken string, client ClientInfo) (Tokens, error) {
 	if refreshToken == "" {
 		return Tokens{}, domain.ErrInvalidInput
 	}
@@ -119,7 +245,7 @@ func (s *authService) Refresh(refreshToken string) (Tokens, error) {
 		return Tokens{}, domain.ErrInvalidCredentials
 	}
 
-	_, err = s.userRepo.GetByID(userID)
+	stored, err := s.refreshTokenRepo.GetByHash(hashToken(refreshToken))
 	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) {
 			return Tokens{}, domain.ErrInvalidCredentials
@@ -127,19 +253,161 @@ func (s *authService) Refresh(refreshToken string) (Tokens, error) {
 		return Tokens{}, err
 	}
 
-	accessToken, err := s.jwt.GenerateAccessToken(userID)
+	if stored.UserID != userID || stored.RevokedAt != nil {
+		return Tokens{}, domain.ErrInvalidCredentials
+	}
+
+	session, err := s.sessionRepo.GetByID(stored.FamilyID)
 	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return Tokens{}, domain.ErrInvalidCredentials
+		}
 		return Tokens{}, err
 	}
+	if session.RevokedAt != nil {
+		return Tokens{}, domain.ErrInvalidCredentials
+	}
 
-	newRefreshToken, err := s.jwt.GenerateRefreshToken(userID)
+	// повторное предъявление уже использованного токена означает,
+	// что он утёк: отзываем всё семейство
+	if stored.UsedAt != nil {
+		return Tokens{}, s.revokeFamily(stored.FamilyID)
+	}
+
+	if err := s.refreshTokenRepo.MarkUsed(stored.ID, time.Now()); err != nil {
+		if errors.Is(err, domain.ErrConflict) {
+			return Tokens{}, s.revokeFamily(stored.FamilyID)
+		}
+		return Tokens{}, err
+	}
+
+	user, err := s.userRepo.GetByID(userID)
 	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return Tokens{}, domain.ErrInvalidCredentials
+		}
 		return Tokens{}, err
 	}
+	if user.Disabled() {
+		return Tokens{}, domain.ErrUserDisabled
+	}
+
+	tokens, err := s.sessions.issueTokens(userID, session.ID)
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	now := time.Now()
+	session.UserAgent = client.UserAgent
+	session.IP = client.IP
+	session.LastUsedAt = now
+	session.ExpiresAt = now.Add(s.jwt.RefreshTTL())
+
+	if err := s.sessionRepo.Touch(session); err != nil {
+		return Tokens{}, err
+	}
+
+	return tokens, nil
+}
+
+func (s *authService) Logout(refreshToken string) error {
+	if refreshToken == "" {
+		return domain.ErrInvalidInput
+	}
+
+	if _, err := s.jwt.ParseRefreshToken(refreshToken); err != nil {
+		return domain.ErrInvalidCredentials
+	}
+
+	stored, err := s.refreshTokenRepo.GetByHash(hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.ErrInvalidCredentials
+		}
+		return err
+	}
+
+	return s.endSession(stored.FamilyID)
+}
+
+func (s *authService) LogoutAll(userID string) error {
+	if userID == "" {
+		return domain.ErrInvalidInput
+	}
+
+	now := time.Now()
+
+	if err := s.sessionRepo.RevokeAllForUser(userID, now); err != nil {
+		return err
+	}
+
+	return s.refreshTokenRepo.RevokeAllForUser(userID, now)
+}
 
-	return Tokens{
-		AccessToken:  accessToken,
-		RefreshToken: newRefreshToken,
-	}, nil
+func (s *authService) ListSessions(userID string) ([]domain.Session, error) {
+	if userID == "" {
+		return nil, domain.ErrInvalidInput
+	}
+
+	return s.sessionRepo.ListActiveByUser(userID, time.Now())
+}
+
+func (s *authService) RevokeSession(userID, sessionID string) error {
+	if userID == "" || sessionID == "" {
+		return domain.ErrInvalidInput
+	}
+
+	session, err := s.sessionRepo.GetByID(sessionID)
+	if err != nil {
+		return err
+	}
+
+	// чужие сессии не раскрываем
+	if session.UserID != userID {
+		return domain.ErrNotFound
+	}
+
+	return s.endSession(session.ID)
+}
+
+func (s *authService) endSession(sessionID string) error {
+	now := time.Now()
+
+	if err := s.sessionRepo.Revoke(sessionID, now); err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return err
+	}
+
+	return s.refreshTokenRepo.RevokeFamily(sessionID, now)
+}
+
+// loginFailed учитывает неудачную попытку; если она привела к блокировке,
+// клиент сразу получает 429, иначе — обычный 401
+func loginFailed(limiter LoginLimiter, email string, client ClientInfo) error {
+	if err := limiter.Failure(email, client.IP); err != nil {
+		return err
+	}
+
+	return domain.ErrInvalidCredentials
+}
+
+func (s *authService) revokeFamily(familyID string) error {
+	if err := s.endSession(familyID); err != nil {
+		return err
+	}
+	return domain.ErrInvalidCredentials
+}
+
+// validEmail принимает только голый адрес, без отображаемого имени
+// и без пробелов по краям: "Bob <bob@example.com>" не пройдёт
+func validEmail(email string) bool {
+	if strings.TrimSpace(email) != email {
+		return false
+	}
+
+	addr, err := mail.ParseAddress(email)
+	if err != nil {
+		return false
+	}
 
+	return addr.Address == email && addr.Name == ""
 }
@@ -0,0 +1,504 @@
+package service
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/storage"
+	"github.com/ovk741/TasksStream/internal/storage/memory"
+)
+
+type fakeUserRepo struct {
+	storage.UserRepository
+	users map[string]domain.User
+	// шаг последнего принятого TOTP-кода; заводится при первом коде
+	totpCounters map[string]int64
+}
+
+func (r *fakeUserRepo) GetByID(id string) (domain.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return domain.User{}, domain.ErrNotFound
+	}
+	return user, nil
+}
+
+func (r *fakeUserRepo) GetByEmail(email string) (domain.User, error) {
+	for _, user := range r.users {
+		if strings.EqualFold(user.Email, email) {
+			return user, nil
+		}
+	}
+	return domain.User{}, domain.ErrNotFound
+}
+
+type fakeRefreshTokenRepo struct {
+	tokens map[string]domain.RefreshToken
+}
+
+func (r *fakeRefreshTokenRepo) Create(token domain.RefreshToken) error {
+	r.tokens[token.ID] = token
+	return nil
+}
+
+func (r *fakeRefreshTokenRepo) GetByHash(tokenHash string) (domain.RefreshToken, error) {
+	for _, token := range r.tokens {
+		if token.TokenHash == tokenHash {
+			return token, nil
+		}
+	}
+	return domain.RefreshToken{}, domain.ErrNotFound
+}
+
+func (r *fakeRefreshTokenRepo) MarkUsed(id string, usedAt time.Time) error {
+	token, ok := r.tokens[id]
+	if !ok || token.UsedAt != nil || token.RevokedAt != nil {
+		return domain.ErrConflict
+	}
+	token.UsedAt = &usedAt
+	r.tokens[id] = token
+	return nil
+}
+
+func (r *fakeRefreshTokenRepo) RevokeFamily(familyID string, revokedAt time.Time) error {
+	return r.revoke(revokedAt, func(token domain.RefreshToken) bool { return token.FamilyID == familyID })
+}
+
+func (r *fakeRefreshTokenRepo) RevokeAllForUser(userID string, revokedAt time.Time) error {
+	return r.revoke(revokedAt, func(token domain.RefreshToken) bool { return token.UserID == userID })
+}
+
+func (r *fakeRefreshTokenRepo) revoke(revokedAt time.Time, match func(domain.RefreshToken) bool) error {
+	for id, token := range r.tokens {
+		if match(token) && token.RevokedAt == nil {
+			token.RevokedAt = &revokedAt
+			r.tokens[id] = token
+		}
+	}
+	return nil
+}
+
+type fakeSessionRepo struct {
+	sessions map[string]domain.Session
+}
+
+func (r *fakeSessionRepo) Create(session domain.Session) error {
+	r.sessions[session.ID] = session
+	return nil
+}
+
+func (r *fakeSessionRepo) GetByID(id string) (domain.Session, error) {
+	session, ok := r.sessions[id]
+	if !ok {
+		return domain.Session{}, domain.ErrNotFound
+	}
+	return session, nil
+}
+
+func (r *fakeSessionRepo) ListActiveByUser(userID string, now time.Time) ([]domain.Session, error) {
+	var result []domain.Session
+	for _, session := range r.sessions {
+		if session.UserID == userID && session.RevokedAt == nil && session.ExpiresAt.After(now) {
+			result = append(result, session)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].LastUsedAt.After(result[j].LastUsedAt) })
+	return result, nil
+}
+
+func (r *fakeSessionRepo) Touch(session domain.Session) error {
+	if _, ok := r.sessions[session.ID]; !ok {
+		return domain.ErrNotFound
+	}
+	r.sessions[session.ID] = session
+	return nil
+}
+
+func (r *fakeSessionRepo) Revoke(id string, revokedAt time.Time) error {
+	session, ok := r.sessions[id]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	if session.RevokedAt == nil {
+		session.RevokedAt = &revokedAt
+		r.sessions[id] = session
+	}
+	return nil
+}
+
+func (r *fakeSessionRepo) RevokeAllForUser(userID string, revokedAt time.Time) error {
+	for id, session := range r.sessions {
+		if session.UserID == userID && session.RevokedAt == nil {
+			session.RevokedAt = &revokedAt
+			r.sessions[id] = session
+		}
+	}
+	return nil
+}
+
+// fakeJWT выдаёт токены вида "вид|пользователь|номер"
+type fakeJWT struct {
+	issued int
+}
+
+func (j *fakeJWT) GenerateAccessToken(userID string) (string, error) {
+	return j.generate("access", userID), nil
+}
+
+func (j *fakeJWT) GenerateRefreshToken(userID string) (string, error) {
+	return j.generate("refresh", userID), nil
+}
+
+func (j *fakeJWT) GenerateChallengeToken(userID string) (string, error) {
+	return j.generate("challenge", userID), nil
+}
+
+func (j *fakeJWT) ParseAccessToken(token string) (string, error) {
+	return j.parse("access", token)
+}
+
+func (j *fakeJWT) ParseRefreshToken(token string) (string, error) {
+	return j.parse("refresh", token)
+}
+
+func (j *fakeJWT) ParseChallengeToken(token string) (string, error) {
+	return j.parse("challenge", token)
+}
+
+func (j *fakeJWT) RefreshTTL() time.Duration {
+	return time.Hour
+}
+
+func (j *fakeJWT) generate(kind, userID string) string {
+	j.issued++
+	return kind + "|" + userID + "|" + strconv.Itoa(j.issued)
+}
+
+func (j *fakeJWT) parse(kind, token string) (string, error) {
+	parts := strings.Split(token, "|")
+	if len(parts) != 3 || parts[0] != kind {
+		return "", errors.New("invalid token")
+	}
+	return parts[1], nil
+}
+
+type plainHasher struct{}
+
+func (plainHasher) Hash(password string) (string, error) {
+	return "hashed:" + password, nil
+}
+
+func (plainHasher) Compare(hash, password string) error {
+	if hash != "hashed:"+password {
+		return errors.New("password mismatch")
+	}
+	return nil
+}
+
+// testPassword — пароль пользователей, созданных через newTestUser
+const testPassword = "correct horse"
+
+func newTestUser(id, email string) domain.User {
+	return domain.User{ID: id, Email: email, PasswordHash: "hashed:" + testPassword, EmailVerified: true}
+}
+
+type testAuth struct {
+	service       *authService
+	users         *fakeUserRepo
+	refreshTokens *fakeRefreshTokenRepo
+	sessions      *fakeSessionRepo
+	recoveryCodes *fakeRecoveryCodeRepo
+}
+
+// newTestAuthService собирает authService с пользователем user-1 и
+// настоящим LoginLimiter поверх хранилища в памяти
+func newTestAuthService() testAuth {
+	n := 0
+	generateID := func() string {
+		n++
+		return "id-" + strconv.Itoa(n)
+	}
+
+	auth := testAuth{
+		users:         &fakeUserRepo{users: map[string]domain.User{"user-1": newTestUser("user-1", "user@example.com")}},
+		refreshTokens: &fakeRefreshTokenRepo{tokens: make(map[string]domain.RefreshToken)},
+		sessions:      &fakeSessionRepo{sessions: make(map[string]domain.Session)},
+		recoveryCodes: &fakeRecoveryCodeRepo{codes: make(map[string]domain.RecoveryCode)},
+	}
+
+	auth.service = NewAuthService(
+		auth.users,
+		auth.refreshTokens,
+		auth.sessions,
+		auth.recoveryCodes,
+		nil,
+		NewLoginLimiter(memory.NewLoginAttemptRepository(), generateID),
+		plainHasher{},
+		&fakeJWT{},
+		false,
+		generateID,
+	).(*authService)
+
+	return auth
+}
+
+func loginTestUser(t *testing.T, auth testAuth) Tokens {
+	t.Helper()
+
+	result, err := auth.service.Login("user@example.com", testPassword, ClientInfo{IP: "10.0.0.1"})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	return result.Tokens
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	cases := []struct {
+		name string
+		// prepare выполняет действия до проверяемого Refresh и возвращает
+		// предъявляемый токен
+		prepare        func(t *testing.T, auth testAuth, issued Tokens) string
+		wantErr        error
+		sessionRevoked bool
+	}{
+		{
+			name: "fresh token",
+			prepare: func(t *testing.T, auth testAuth, issued Tokens) string {
+				return issued.RefreshToken
+			},
+		},
+		{
+			name: "rotated token is rejected",
+			prepare: func(t *testing.T, auth testAuth, issued Tokens) string {
+				if _, err := auth.service.Refresh(issued.RefreshToken, ClientInfo{}); err != nil {
+					t.Fatalf("rotate: %v", err)
+				}
+				return issued.RefreshToken
+			},
+			wantErr:        domain.ErrInvalidCredentials,
+			sessionRevoked: true,
+		},
+		{
+			name: "replay revokes the whole family",
+			prepare: func(t *testing.T, auth testAuth, issued Tokens) string {
+				rotated, err := auth.service.Refresh(issued.RefreshToken, ClientInfo{})
+				if err != nil {
+					t.Fatalf("rotate: %v", err)
+				}
+				if _, err := auth.service.Refresh(issued.RefreshToken, ClientInfo{}); !errors.Is(err, domain.ErrInvalidCredentials) {
+					t.Fatalf("replay: expected ErrInvalidCredentials, got %v", err)
+				}
+				return rotated.RefreshToken
+			},
+			wantErr:        domain.ErrInvalidCredentials,
+			sessionRevoked: true,
+		},
+		{
+			name: "logout",
+			prepare: func(t *testing.T, auth testAuth, issued Tokens) string {
+				if err := auth.service.Logout(issued.RefreshToken); err != nil {
+					t.Fatalf("logout: %v", err)
+				}
+				return issued.RefreshToken
+			},
+			wantErr:        domain.ErrInvalidCredentials,
+			sessionRevoked: true,
+		},
+		{
+			name: "logout with rotated token",
+			prepare: func(t *testing.T, auth testAuth, issued Tokens) string {
+				rotated, err := auth.service.Refresh(issued.RefreshToken, ClientInfo{})
+				if err != nil {
+					t.Fatalf("rotate: %v", err)
+				}
+				if err := auth.service.Logout(rotated.RefreshToken); err != nil {
+					t.Fatalf("logout: %v", err)
+				}
+				return rotated.RefreshToken
+			},
+			wantErr:        domain.ErrInvalidCredentials,
+			sessionRevoked: true,
+		},
+		{
+			name: "unknown token",
+			prepare: func(t *testing.T, auth testAuth, issued Tokens) string {
+				return "refresh|user-1|999"
+			},
+			wantErr: domain.ErrInvalidCredentials,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			auth := newTestAuthService()
+			issued := loginTestUser(t, auth)
+
+			token := tc.prepare(t, auth, issued)
+
+			tokens, err := auth.service.Refresh(token, ClientInfo{UserAgent: "curl", IP: "10.0.0.2"})
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected %v, got %v", tc.wantErr, err)
+			}
+			if err == nil && (tokens.RefreshToken == "" || tokens.RefreshToken == token) {
+				t.Errorf("expected a new refresh token, got %q", tokens.RefreshToken)
+			}
+
+			if len(auth.sessions.sessions) != 1 {
+				t.Fatalf("expected one session, got %d", len(auth.sessions.sessions))
+			}
+			for _, session := range auth.sessions.sessions {
+				if revoked := session.RevokedAt != nil; revoked != tc.sessionRevoked {
+					t.Errorf("expected session revoked=%v, got %v", tc.sessionRevoked, revoked)
+				}
+			}
+
+			if tc.sessionRevoked {
+				for _, stored := range auth.refreshTokens.tokens {
+					if stored.RevokedAt == nil {
+						t.Errorf("token %s of the revoked family is still active", stored.ID)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestListSessions(t *testing.T) {
+	auth := newTestAuthService()
+	auth.users.users["user-2"] = newTestUser("user-2", "other@example.com")
+
+	first := loginTestUser(t, auth)
+	loginTestUser(t, auth)
+	if _, err := auth.service.Login("other@example.com", testPassword, ClientInfo{}); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+
+	sessions, err := auth.service.ListSessions("user-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("expected 2 sessions of user-1, got %d", len(sessions))
+	}
+	for _, session := range sessions {
+		if session.UserID != "user-1" || session.IP != "10.0.0.1" {
+			t.Errorf("unexpected session: %+v", session)
+		}
+	}
+
+	if err := auth.service.Logout(first.RefreshToken); err != nil {
+		t.Fatalf("logout: %v", err)
+	}
+
+	sessions, err = auth.service.ListSessions("user-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sessions) != 1 {
+		t.Errorf("expected ended session to be hidden, got %d sessions", len(sessions))
+	}
+
+	if _, err := auth.service.ListSessions(""); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput, got %v", err)
+	}
+}
+
+func TestRevokeSession(t *testing.T) {
+	auth := newTestAuthService()
+	auth.users.users["user-2"] = newTestUser("user-2", "other@example.com")
+
+	tokens := loginTestUser(t, auth)
+	sessions, err := auth.service.ListSessions("user-1")
+	if err != nil || len(sessions) != 1 {
+		t.Fatalf("expected one session, got %v, %v", sessions, err)
+	}
+	sessionID := sessions[0].ID
+
+	cases := []struct {
+		name      string
+		userID    string
+		sessionID string
+		wantErr   error
+		active    bool
+	}{
+		{"foreign session", "user-2", sessionID, domain.ErrNotFound, true},
+		{"unknown session", "user-1", "missing", domain.ErrNotFound, true},
+		{"empty id", "user-1", "", domain.ErrInvalidInput, true},
+		{"own session", "user-1", sessionID, nil, false},
+		{"already ended", "user-1", sessionID, nil, false},
+	}
+
+	for _, tc := range cases {
+		if err := auth.service.RevokeSession(tc.userID, tc.sessionID); !errors.Is(err, tc.wantErr) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.wantErr, err)
+		}
+		if active := auth.sessions.sessions[sessionID].RevokedAt == nil; active != tc.active {
+			t.Errorf("%s: expected session active=%v, got %v", tc.name, tc.active, active)
+		}
+	}
+
+	if _, err := auth.service.Refresh(tokens.RefreshToken, ClientInfo{}); !errors.Is(err, domain.ErrInvalidCredentials) {
+		t.Errorf("expected refresh of a revoked session to fail, got %v", err)
+	}
+	if sessions, _ := auth.service.ListSessions("user-1"); len(sessions) != 0 {
+		t.Errorf("expected no active sessions, got %d", len(sessions))
+	}
+}
+
+func TestLoginIsRateLimited(t *testing.T) {
+	auth := newTestAuthService()
+	client := ClientInfo{IP: "10.0.0.1"}
+
+	for i := 0; i < loginAccountFreeAttempts-1; i++ {
+		if _, err := auth.service.Login("user@example.com", "wrong", client); !errors.Is(err, domain.ErrInvalidCredentials) {
+			t.Fatalf("attempt %d: expected ErrInvalidCredentials, got %v", i+1, err)
+		}
+	}
+
+	var rateLimit *domain.RateLimitError
+	if _, err := auth.service.Login("user@example.com", "wrong", client); !errors.As(err, &rateLimit) || !errors.Is(err, domain.ErrTooManyRequests) {
+		t.Fatalf("expected rate limit on the last free attempt, got %v", err)
+	}
+	if rateLimit.RetryAfter <= 0 {
+		t.Errorf("expected positive Retry-After, got %s", rateLimit.RetryAfter)
+	}
+
+	// во время блокировки не пускает даже верный пароль
+	if _, err := auth.service.Login("user@example.com", testPassword, client); !errors.Is(err, domain.ErrTooManyRequests) {
+		t.Errorf("expected ErrTooManyRequests with correct password, got %v", err)
+	}
+
+	// несуществующий адрес блокируется так же
+	for i := 0; i < loginAccountFreeAttempts; i++ {
+		_, err := auth.service.Login("nobody@example.com", "wrong", ClientInfo{})
+		if i == loginAccountFreeAttempts-1 && !errors.Is(err, domain.ErrTooManyRequests) {
+			t.Errorf("expected ErrTooManyRequests for unknown address, got %v", err)
+		}
+	}
+}
+
+func TestValidEmail(t *testing.T) {
+	cases := []struct {
+		email string
+		want  bool
+	}{
+		{"user@example.com", true},
+		{"first.last+tag@sub.example.org", true},
+		{"not-an-email", false},
+		{"user@", false},
+		{" user@example.com", false},
+		{"Bob <bob@example.com>", false},
+		{"a@b.c\r\nBcc: x@y.z", false},
+	}
+
+	for _, tc := range cases {
+		if got := validEmail(tc.email); got != tc.want {
+			t.Errorf("validEmail(%q) = %v, want %v", tc.email, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/storage"
+)
+
+// last_used_at обновляется не чаще раза в минуту, чтобы скрипты,
+// делающие много запросов, не писали в базу на каждый из них
+const personalTokenTouchInterval = time.Minute
+
+type Authenticator interface {
+	Authenticate(token string) (domain.Principal, error)
+}
+
+type authenticator struct {
+	jwt       JWTManager
+	tokenRepo storage.PersonalTokenRepository
+	userRepo  storage.UserRepository
+}
+
+// NewAuthenticator принимает как access JWT, так и персональные токены.
+// Токены отключённого пользователя отклоняются с domain.ErrUserDisabled.
+func NewAuthenticator(
+	jwt JWTManager,
+	tokenRepo storage.PersonalTokenRepository,
+	userRepo storage.UserRepository,
+) Authenticator {
+	return &authenticator{
+		jwt:       jwt,
+		tokenRepo: tokenRepo,
+		userRepo:  userRepo,
+	}
+}
+
+func (a *authenticator) Authenticate(token string) (domain.Principal, error) {
+	principal, err := a.resolve(token)
+	if err != nil {
+		return domain.Principal{}, err
+	}
+
+	user, err := a.userRepo.GetByID(principal.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.Principal{}, domain.ErrInvalidCredentials
+		}
+		return domain.Principal{}, err
+	}
+	if user.Disabled() {
+		return domain.Principal{}, domain.ErrUserDisabled
+	}
+
+	return principal, nil
+}
+
+func (a *authenticator) resolve(token string) (domain.Principal, error) {
+	if token == "" {
+		return domain.Principal{}, domain.ErrInvalidCredentials
+	}
+
+	if strings.HasPrefix(token, personalTokenPrefix) {
+		return a.authenticatePersonalToken(token)
+	}
+
+	userID, err := a.jwt.ParseAccessToken(token)
+	if err != nil {
+		return domain.Principal{}, domain.ErrInvalidCredentials
+	}
+
+	return domain.Principal{UserID: userID}, nil
+}
+
+func (a *authenticator) authenticatePersonalToken(token string) (domain.Principal, error) {
+	stored, err := a.tokenRepo.GetByHash(hashToken(token))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.Principal{}, domain.ErrInvalidCredentials
+		}
+		return domain.Principal{}, err
+	}
+
+	now := time.Now()
+
+	if stored.RevokedAt != nil {
+		return domain.Principal{}, domain.ErrInvalidCredentials
+	}
+	if stored.ExpiresAt != nil && !stored.ExpiresAt.After(now) {
+		return domain.Principal{}, domain.ErrInvalidCredentials
+	}
+
+	if stored.LastUsedAt == nil || now.Sub(*stored.LastUsedAt) >= personalTokenTouchInterval {
+		if err := a.tokenRepo.TouchLastUsed(stored.ID, now); err != nil {
+			log.Printf("touch personal token %s: %v", stored.ID, err)
+		}
+	}
+
+	// пустой, но не nil список: токен без прав ничего не может
+	scopes := stored.Scopes
+	if scopes == nil {
+		scopes = []domain.TokenScope{}
+	}
+
+	return domain.Principal{
+		UserID: stored.UserID,
+		Scopes: scopes,
+	}, nil
+}
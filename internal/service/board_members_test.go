@@ -0,0 +1,211 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+// fakeMemberRepo хранит только прямое участие
+func (r *fakeMemberRepo) GetDirectRole(boardID, userID string) (domain.BoardRole, error) {
+	return r.GetRole(boardID, userID)
+}
+
+func (r *fakeMemberRepo) UpdateRole(boardID, userID string, role domain.BoardRole) error {
+	current, ok := r.roles[boardID+"|"+userID]
+	if !ok || current == domain.BoardRoleOwner {
+		return domain.ErrNotFound
+	}
+	r.roles[boardID+"|"+userID] = role
+	return nil
+}
+
+func (r *fakeMemberRepo) Remove(boardID, userID string) error {
+	current, ok := r.roles[boardID+"|"+userID]
+	if !ok || current == domain.BoardRoleOwner {
+		return domain.ErrNotFound
+	}
+	delete(r.roles, boardID+"|"+userID)
+	return nil
+}
+
+func (r *fakeMemberRepo) TransferOwnership(boardID, fromUserID, toUserID string, previousOwnerRole domain.BoardRole) error {
+	if r.roles[boardID+"|"+fromUserID] != domain.BoardRoleOwner {
+		return domain.ErrConflict
+	}
+	if _, ok := r.roles[boardID+"|"+toUserID]; !ok {
+		return domain.ErrNotFound
+	}
+	r.roles[boardID+"|"+fromUserID] = previousOwnerRole
+	r.roles[boardID+"|"+toUserID] = domain.BoardRoleOwner
+	return nil
+}
+
+func newTestMembersService() (BoardService, *fakeMemberRepo) {
+	members := &fakeMemberRepo{roles: map[string]domain.BoardRole{
+		"board-1|owner":  domain.BoardRoleOwner,
+		"board-1|editor": domain.BoardRoleEditor,
+		"board-1|viewer": domain.BoardRoleViewer,
+	}}
+
+	service := NewBoardService(fakeBoardRepo{}, nil, nil, members, &fakeAssigneeRepo{}, NewBoardPolicy(members, newFakeRoleRepo(), newFakeWorkspaceMemberRepo()), nil, discardEvents{}, func() string { return "id" })
+
+	return service, members
+}
+
+func TestTransferOwnership(t *testing.T) {
+	service, members := newTestMembersService()
+
+	if err := service.TransferOwnership("owner", "board-1", "viewer"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if role := members.roles["board-1|viewer"]; role != domain.BoardRoleOwner {
+		t.Errorf("expected new owner, got %s", role)
+	}
+	if role := members.roles["board-1|owner"]; role != domain.BoardRoleEditor {
+		t.Errorf("expected previous owner to become editor, got %s", role)
+	}
+
+	// права переданы — прежний владелец больше не может их передать
+	err := service.TransferOwnership("owner", "board-1", "editor")
+	if !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden, got %v", err)
+	}
+}
+
+func TestTransferOwnershipToNonMember(t *testing.T) {
+	service, members := newTestMembersService()
+
+	err := service.TransferOwnership("owner", "board-1", "stranger")
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	if role := members.roles["board-1|owner"]; role != domain.BoardRoleOwner {
+		t.Errorf("expected owner to keep the role, got %s", role)
+	}
+}
+
+func TestChangeMemberRole(t *testing.T) {
+	service, members := newTestMembersService()
+
+	if err := service.ChangeMemberRole("owner", "board-1", "viewer", domain.BoardRoleEditor); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if role := members.roles["board-1|viewer"]; role != domain.BoardRoleEditor {
+		t.Errorf("expected editor, got %s", role)
+	}
+
+	tests := []struct {
+		name      string
+		requester string
+		target    string
+		role      domain.BoardRole
+		want      error
+	}{
+		{"editor cannot change roles", "editor", "viewer", domain.BoardRoleViewer, domain.ErrForbidden},
+		{"owner cannot demote self", "owner", "owner", domain.BoardRoleEditor, domain.ErrForbidden},
+		{"owner role is not assignable", "owner", "editor", domain.BoardRoleOwner, domain.ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.ChangeMemberRole(tt.requester, "board-1", tt.target, tt.role)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestLeave(t *testing.T) {
+	service, members := newTestMembersService()
+
+	if err := service.Leave("owner", "board-1"); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden for owner, got %v", err)
+	}
+
+	if err := service.Leave("editor", "board-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := members.roles["board-1|editor"]; ok {
+		t.Error("expected editor to leave the board")
+	}
+}
+
+// teamAccessRepo добавляет к прямому участию роли, выданные через команду
+type teamAccessRepo struct {
+	*fakeMemberRepo
+	team map[string]domain.BoardRole
+}
+
+func (r *teamAccessRepo) GetRole(boardID, userID string) (domain.BoardRole, error) {
+	direct, err := r.fakeMemberRepo.GetRole(boardID, userID)
+	team, ok := r.team[boardID+"|"+userID]
+	if !ok {
+		return direct, err
+	}
+	if err != nil || team.Rank() > direct.Rank() {
+		return team, nil
+	}
+	return direct, nil
+}
+
+func (r *teamAccessRepo) IsMember(boardID, userID string) (bool, error) {
+	_, err := r.GetRole(boardID, userID)
+	return err == nil, nil
+}
+
+func TestMembershipChangesRequireDirectMembership(t *testing.T) {
+	members := &teamAccessRepo{
+		fakeMemberRepo: &fakeMemberRepo{roles: map[string]domain.BoardRole{
+			"board-1|owner":  domain.BoardRoleOwner,
+			"board-1|editor": domain.BoardRoleEditor,
+		}},
+		team: map[string]domain.BoardRole{
+			"board-1|editor": domain.BoardRoleViewer,
+			"board-1|vera":   domain.BoardRoleEditor,
+		},
+	}
+	service := NewBoardService(
+		fakeBoardRepo{},
+		nil,
+		nil,
+		members,
+		&fakeAssigneeRepo{},
+		NewBoardPolicy(members, newFakeRoleRepo(), newFakeWorkspaceMemberRepo()),
+		nil,
+		discardEvents{},
+		func() string { return "id" },
+	)
+
+	// доступ vera только через команду: его меняют и снимают в самой команде
+	if err := service.ChangeMemberRole("owner", "board-1", "vera", domain.BoardRoleViewer); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("expected ErrForbidden for role change, got %v", err)
+	}
+	if err := service.RemoveUser("owner", "board-1", "vera"); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("expected ErrForbidden for removal, got %v", err)
+	}
+	if err := service.Leave("vera", "board-1"); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("expected ErrForbidden for leaving, got %v", err)
+	}
+	if err := service.ChangeMemberRole("owner", "board-1", "stranger", domain.BoardRoleViewer); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for non-member, got %v", err)
+	}
+
+	// прямое участие меняется, даже если через команду роль другая
+	if err := service.ChangeMemberRole("owner", "board-1", "editor", domain.BoardRoleViewer); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if role := members.roles["board-1|editor"]; role != domain.BoardRoleViewer {
+		t.Errorf("expected direct role to change, got %s", role)
+	}
+	if err := service.Leave("editor", "board-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := members.roles["board-1|editor"]; ok {
+		t.Error("expected direct membership to be removed")
+	}
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/storage"
+)
+
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{1,31}$`)
+
+// BoardRoleService управляет собственными ролями доски. Встроенные роли
+// возвращаются в списке, но не меняются.
+type BoardRoleService interface {
+	List(requesterID, boardID string) ([]domain.BoardRoleDefinition, error)
+	Create(ownerID, boardID string, name domain.BoardRole, permissions []domain.Action) (domain.BoardRoleDefinition, error)
+	Update(ownerID, boardID string, name domain.BoardRole, permissions []domain.Action) (domain.BoardRoleDefinition, error)
+	Delete(ownerID, boardID string, name domain.BoardRole) error
+}
+
+type boardRoleService struct {
+	boardRoleRepo storage.BoardRoleRepository
+	policy        BoardPolicy
+	generateID    func() string
+}
+
+func NewBoardRoleService(
+	boardRoleRepo storage.BoardRoleRepository,
+	policy BoardPolicy,
+	generateID func() string,
+) BoardRoleService {
+	return &boardRoleService{
+		boardRoleRepo: boardRoleRepo,
+		policy:        policy,
+		generateID:    generateID,
+	}
+}
+
+func (s *boardRoleService) List(requesterID, boardID string) ([]domain.BoardRoleDefinition, error) {
+	if boardID == "" {
+		return nil, domain.ErrInvalidInput
+	}
+
+	if _, err := s.policy.Authorize(boardID, requesterID, domain.ActionBoardRead); err != nil {
+		return nil, err
+	}
+
+	custom, err := s.boardRoleRepo.ListByBoard(boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(builtInRoles(), custom...), nil
+}
+
+func (s *boardRoleService) Create(
+	ownerID, boardID string,
+	name domain.BoardRole,
+	permissions []domain.Action,
+) (domain.BoardRoleDefinition, error) {
+	if boardID == "" || name.IsBuiltIn() || !roleNamePattern.MatchString(string(name)) {
+		return domain.BoardRoleDefinition{}, domain.ErrInvalidInput
+	}
+
+	permissions, err := normalizeRolePermissions(permissions)
+	if err != nil {
+		return domain.BoardRoleDefinition{}, err
+	}
+
+	if _, err := s.policy.Authorize(boardID, ownerID, domain.ActionMemberManage); err != nil {
+		return domain.BoardRoleDefinition{}, err
+	}
+
+	role := domain.BoardRoleDefinition{
+		ID:          s.generateID(),
+		BoardID:     boardID,
+		Name:        name,
+		Permissions: permissions,
+		CreatedAt:   time.Now(),
+	}
+
+	if err := s.boardRoleRepo.Create(role); err != nil {
+		return domain.BoardRoleDefinition{}, err
+	}
+
+	return role, nil
+}
+
+func (s *boardRoleService) Update(
+	ownerID, boardID string,
+	name domain.BoardRole,
+	permissions []domain.Action,
+) (domain.BoardRoleDefinition, error) {
+	if boardID == "" || name == "" {
+		return domain.BoardRoleDefinition{}, domain.ErrInvalidInput
+	}
+	if name.IsBuiltIn() {
+		return domain.BoardRoleDefinition{}, domain.ErrForbidden
+	}
+
+	permissions, err := normalizeRolePermissions(permissions)
+	if err != nil {
+		return domain.BoardRoleDefinition{}, err
+	}
+
+	if _, err := s.policy.Authorize(boardID, ownerID, domain.ActionMemberManage); err != nil {
+		return domain.BoardRoleDefinition{}, err
+	}
+
+	if err := s.boardRoleRepo.UpdatePermissions(boardID, name, permissions); err != nil {
+		return domain.BoardRoleDefinition{}, err
+	}
+
+	return s.boardRoleRepo.GetByName(boardID, name)
+}
+
+func (s *boardRoleService) Delete(ownerID, boardID string, name domain.BoardRole) error {
+	if boardID == "" || name == "" {
+		return domain.ErrInvalidInput
+	}
+	if name.IsBuiltIn() {
+		return domain.ErrForbidden
+	}
+
+	if _, err := s.policy.Authorize(boardID, ownerID, domain.ActionMemberManage); err != nil {
+		return err
+	}
+
+	return s.boardRoleRepo.Delete(boardID, name)
+}
+
+// normalizeRolePermissions оставляет только допустимые для собственной роли
+// действия без повторов; чтение доски есть у любой роли и не хранится
+func normalizeRolePermissions(permissions []domain.Action) ([]domain.Action, error) {
+	allowed := make(map[domain.Action]bool, len(domain.CustomRoleActions))
+	for _, action := range domain.CustomRoleActions {
+		allowed[action] = true
+	}
+
+	seen := make(map[domain.Action]bool, len(permissions))
+	result := make([]domain.Action, 0, len(permissions))
+
+	for _, action := range permissions {
+		if action == domain.ActionBoardRead || seen[action] {
+			continue
+		}
+		if !allowed[action] {
+			return nil, domain.ErrInvalidInput
+		}
+		seen[action] = true
+		result = append(result, action)
+	}
+
+	return result, nil
+}
@@ -2,6 +2,8 @@ package service
 
 import (
 	"errors"
+	"fmt"
+	"log"
 	"time"
 
 	"github.com/ovk741/TasksStream/internal/domain"
@@ -17,13 +19,29 @@ type BoardService interface {
 
 	GetMembers(requesterID, boardID string) ([]domain.BoardMember, error)
 	RemoveUser(requesterID, boardID, userID string) error
+	ChangeMemberRole(ownerID, boardID, userID string, role domain.BoardRole) error
+	// TransferOwnership делает участника владельцем, прежний владелец становится редактором
+	TransferOwnership(ownerID, boardID, newOwnerID string) error
+	Leave(userID, boardID string) error
+
+	// CreateInviteLink возвращает ссылку и её токен; токен показывается только один раз
+	CreateInviteLink(ownerID, boardID string, role domain.BoardRole, maxUses *int, expiresAt *time.Time) (domain.BoardInviteLink, string, error)
+	GetInviteLinks(ownerID, boardID string) ([]domain.BoardInviteLink, error)
+	RevokeInviteLink(ownerID, linkID string) error
+	JoinByInviteLink(userID, token string) (domain.Board, error)
 }
 
+const inviteLinkTokenPrefix = "tsl_"
+
 type boardService struct {
 	boardRepo       storage.BoardRepository
 	columnRepo      storage.ColumnRepository
 	taskRepo        storage.TaskRepository
 	boardMemberRepo storage.BoardMemberRepository
+	assigneeRepo    storage.TaskAssigneeRepository
+	policy          BoardPolicy
+	inviteLinkRepo  storage.BoardInviteLinkRepository
+	events          EventPublisher
 	generateID      func() string
 }
 
@@ -32,6 +50,10 @@ func NewBoardService(
 	columnRepo storage.ColumnRepository,
 	taskRepo storage.TaskRepository,
 	boardMemberRepo storage.BoardMemberRepository,
+	assigneeRepo storage.TaskAssigneeRepository,
+	policy BoardPolicy,
+	inviteLinkRepo storage.BoardInviteLinkRepository,
+	events EventPublisher,
 	generateID func() string,
 ) BoardService {
 	return &boardService{
@@ -39,6 +61,10 @@ func NewBoardService(
 		columnRepo:      columnRepo,
 		taskRepo:        taskRepo,
 		boardMemberRepo: boardMemberRepo,
+		assigneeRepo:    assigneeRepo,
+		policy:          policy,
+		inviteLinkRepo:  inviteLinkRepo,
+		events:          events,
 		generateID:      generateID,
 	}
 
@@ -79,24 +105,7 @@ func (s *boardService) GetAll(userID string) ([]domain.Board, error) {
 		return nil, domain.ErrInvalidInput
 	}
 
-	boards, err := s.boardRepo.GetAll()
-	if err != nil {
-		return nil, err
-	}
-
-	result := make([]domain.Board, 0, len(boards))
-
-	for _, b := range boards {
-		isMember, err := s.boardMemberRepo.IsMember(b.ID, userID)
-		if err != nil {
-			return nil, err
-		}
-		if isMember {
-			result = append(result, b)
-		}
-	}
-
-	return result, nil
+	return s.boardRepo.ListByUser(userID)
 }
 
 func (s *boardService) Update(userID, boardID, name string) (domain.Board, error) {
@@ -104,15 +113,10 @@ func (s *boardService) Update(userID, boardID, name string) (domain.Board, error
 		return domain.Board{}, domain.ErrInvalidInput
 	}
 
-	role, err := s.requireMember(boardID, userID)
-	if err != nil {
+	if _, err := s.policy.Authorize(boardID, userID, domain.ActionBoardUpdate); err != nil {
 		return domain.Board{}, err
 	}
 
-	if role == domain.BoardRoleViewer {
-		return domain.Board{}, domain.ErrForbidden
-	}
-
 	board, err := s.boardRepo.GetByID(boardID)
 	if err != nil {
 		return domain.Board{}, err
@@ -124,6 +128,9 @@ func (s *boardService) Update(userID, boardID, name string) (domain.Board, error
 	if err != nil {
 		return domain.Board{}, err
 	}
+
+	s.events.Publish(newBoardEvent(domain.EventBoardUpdated, boardID, userID, updated))
+
 	return updated, nil
 
 }
@@ -138,28 +145,19 @@ func (s *boardService) Delete(userID, boardID string) error {
 		return err
 	}
 
-	role, err := s.requireMember(boardID, userID)
-	if err != nil {
+	if _, err := s.policy.Authorize(boardID, userID, domain.ActionBoardDelete); err != nil {
 		return err
 	}
-	if role != domain.BoardRoleOwner && role != domain.BoardRoleEditor {
-		return domain.ErrForbidden
-	}
-
-	return s.boardRepo.Delete(boardID)
-}
 
-func (s *boardService) requireMember(boardID, userID string) (domain.BoardRole, error) {
-
-	role, err := s.boardMemberRepo.GetRole(boardID, userID)
-	if err != nil {
-		if errors.Is(err, domain.ErrNotFound) {
-			return "", domain.ErrForbidden
-		}
-		return "", err
+	if err := s.boardRepo.Delete(boardID); err != nil {
+		return err
 	}
 
-	return role, nil
+	s.events.Publish(newBoardEvent(domain.EventBoardDeleted, boardID, userID, map[string]string{
+		"id": boardID,
+	}))
+
+	return nil
 }
 
 func (s *boardService) InviteUser(ownerID string, boardID string, userID string, role domain.BoardRole) error {
@@ -167,23 +165,19 @@ func (s *boardService) InviteUser(ownerID string, boardID string, userID string,
 		return domain.ErrInvalidInput
 	}
 
-	if role != domain.BoardRoleEditor && role != domain.BoardRoleViewer {
-		return domain.ErrInvalidInput
-	}
-
 	if _, err := s.boardRepo.GetByID(boardID); err != nil {
 		return domain.ErrNotFound
 	}
 
-	inviterRole, err := s.boardMemberRepo.GetRole(boardID, ownerID)
-	if err != nil {
+	if _, err := s.policy.Authorize(boardID, ownerID, domain.ActionMemberManage); err != nil {
 		return err
 	}
-	if inviterRole != domain.BoardRoleOwner {
-		return domain.ErrForbidden
+
+	if err := s.policy.AssignableRole(boardID, role); err != nil {
+		return err
 	}
 
-	_, err = s.boardMemberRepo.GetRole(boardID, userID)
+	_, err := s.boardMemberRepo.GetRole(boardID, userID)
 	if err == nil {
 		return domain.ErrUserAlreadyExists
 	}
@@ -192,12 +186,23 @@ func (s *boardService) InviteUser(ownerID string, boardID string, userID string,
 	}
 
 	member := domain.BoardMember{
-		BoardID: boardID,
-		UserID:  userID,
-		Role:    role,
+		ID:        s.generateID(),
+		BoardID:   boardID,
+		UserID:    userID,
+		Role:      role,
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.boardMemberRepo.Add(member); err != nil {
+		return err
 	}
 
-	return s.boardMemberRepo.Add(member)
+	s.events.Publish(newBoardEvent(domain.EventMemberAdded, boardID, ownerID, map[string]string{
+		"user_id": userID,
+		"role":    string(role),
+	}))
+
+	return nil
 }
 
 func (s *boardService) GetMembers(
@@ -208,8 +213,7 @@ func (s *boardService) GetMembers(
 		return nil, domain.ErrInvalidInput
 	}
 
-	_, err := s.requireMember(boardID, requesterID)
-	if err != nil {
+	if _, err := s.policy.Authorize(boardID, requesterID, domain.ActionBoardRead); err != nil {
 		return nil, err
 	}
 
@@ -224,20 +228,293 @@ func (s *boardService) RemoveUser(
 		return domain.ErrInvalidInput
 	}
 
-	requesterRole, err := s.requireMember(boardID, requesterID)
-	if err != nil {
+	if _, err := s.policy.Authorize(boardID, requesterID, domain.ActionMemberManage); err != nil {
 		return err
 	}
 
-	// только owner может удалять
-	if requesterRole != domain.BoardRoleOwner {
+	// владелец не может удалить себя: сначала нужно передать права
+	if requesterID == userID {
 		return domain.ErrForbidden
 	}
 
-	// нельзя удалить самого себя (без передачи ownership)
-	if requesterID == userID {
+	if _, err := s.directRole(boardID, userID); err != nil {
+		return err
+	}
+
+	if err := s.boardMemberRepo.Remove(boardID, userID); err != nil {
+		return err
+	}
+
+	unassignFormerMember(s.boardMemberRepo, s.assigneeRepo, boardID, userID)
+
+	s.events.Publish(newBoardEvent(domain.EventMemberRemoved, boardID, requesterID, map[string]string{
+		"user_id": userID,
+	}))
+
+	return nil
+}
+
+func (s *boardService) ChangeMemberRole(ownerID, boardID, userID string, role domain.BoardRole) error {
+	if boardID == "" || userID == "" {
+		return domain.ErrInvalidInput
+	}
+
+	if _, err := s.policy.Authorize(boardID, ownerID, domain.ActionMemberManage); err != nil {
+		return err
+	}
+
+	// владельцем становятся только через передачу прав
+	if err := s.policy.AssignableRole(boardID, role); err != nil {
+		return err
+	}
+
+	if ownerID == userID {
 		return domain.ErrForbidden
 	}
 
-	return s.boardMemberRepo.Remove(boardID, userID)
+	current, err := s.directRole(boardID, userID)
+	if err != nil {
+		return err
+	}
+	if current == role {
+		return nil
+	}
+
+	if err := s.boardMemberRepo.UpdateRole(boardID, userID, role); err != nil {
+		return err
+	}
+
+	s.events.Publish(newBoardEvent(domain.EventMemberRoleChanged, boardID, ownerID, map[string]string{
+		"user_id": userID,
+		"role":    string(role),
+	}))
+
+	return nil
+}
+
+func (s *boardService) TransferOwnership(ownerID, boardID, newOwnerID string) error {
+	if boardID == "" || newOwnerID == "" {
+		return domain.ErrInvalidInput
+	}
+
+	if _, err := s.policy.Authorize(boardID, ownerID, domain.ActionBoardTransfer); err != nil {
+		return err
+	}
+
+	if ownerID == newOwnerID {
+		return domain.ErrInvalidInput
+	}
+
+	if err := s.boardMemberRepo.TransferOwnership(boardID, ownerID, newOwnerID, domain.BoardRoleEditor); err != nil {
+		return err
+	}
+
+	s.events.Publish(newBoardEvent(domain.EventMemberRoleChanged, boardID, ownerID, map[string]string{
+		"user_id": newOwnerID,
+		"role":    string(domain.BoardRoleOwner),
+	}))
+	s.events.Publish(newBoardEvent(domain.EventMemberRoleChanged, boardID, ownerID, map[string]string{
+		"user_id": ownerID,
+		"role":    string(domain.BoardRoleEditor),
+	}))
+
+	return nil
+}
+
+func (s *boardService) Leave(userID, boardID string) error {
+	if boardID == "" {
+		return domain.ErrInvalidInput
+	}
+
+	role, err := s.directRole(boardID, userID)
+	if err != nil {
+		return err
+	}
+
+	// доска не может остаться без владельца
+	if role == domain.BoardRoleOwner {
+		return domain.ErrForbidden
+	}
+
+	if err := s.boardMemberRepo.Remove(boardID, userID); err != nil {
+		return err
+	}
+
+	unassignFormerMember(s.boardMemberRepo, s.assigneeRepo, boardID, userID)
+
+	s.events.Publish(newBoardEvent(domain.EventMemberRemoved, boardID, userID, map[string]string{
+		"user_id": userID,
+	}))
+
+	return nil
+}
+
+// directRole возвращает роль прямого участия: менять роль и исключать можно
+// только его, а доступ через команду снимается в самой команде
+func (s *boardService) directRole(boardID, userID string) (domain.BoardRole, error) {
+	role, err := s.boardMemberRepo.GetDirectRole(boardID, userID)
+	if !errors.Is(err, domain.ErrNotFound) {
+		return role, err
+	}
+
+	isMember, err := s.boardMemberRepo.IsMember(boardID, userID)
+	if err != nil {
+		return "", err
+	}
+	if isMember {
+		return "", fmt.Errorf("%w: access granted via team", domain.ErrForbidden)
+	}
+
+	return "", domain.ErrNotFound
+}
+
+// unassignFormerMember снимает пользователя с задач доски, если у него не осталось
+// доступа к ней ни напрямую, ни через команду. Доступ к этому моменту уже отозван,
+// поэтому ошибка только логируется.
+func unassignFormerMember(
+	boardMemberRepo storage.BoardMemberRepository,
+	assigneeRepo storage.TaskAssigneeRepository,
+	boardID, userID string,
+) {
+	isMember, err := boardMemberRepo.IsMember(boardID, userID)
+	if err != nil {
+		log.Printf("check access of %s to board %s: %v", userID, boardID, err)
+		return
+	}
+	if isMember {
+		return
+	}
+
+	if err := assigneeRepo.RemoveFromBoard(boardID, userID); err != nil {
+		log.Printf("unassign %s from tasks of board %s: %v", userID, boardID, err)
+	}
+}
+
+func (s *boardService) CreateInviteLink(
+	ownerID, boardID string,
+	role domain.BoardRole,
+	maxUses *int,
+	expiresAt *time.Time,
+) (domain.BoardInviteLink, string, error) {
+	if ownerID == "" || boardID == "" {
+		return domain.BoardInviteLink{}, "", domain.ErrInvalidInput
+	}
+
+	now := time.Now()
+
+	if maxUses != nil && *maxUses < 1 {
+		return domain.BoardInviteLink{}, "", domain.ErrInvalidInput
+	}
+	if expiresAt != nil && !expiresAt.After(now) {
+		return domain.BoardInviteLink{}, "", domain.ErrInvalidInput
+	}
+
+	if _, err := s.boardRepo.GetByID(boardID); err != nil {
+		return domain.BoardInviteLink{}, "", err
+	}
+
+	if _, err := s.policy.Authorize(boardID, ownerID, domain.ActionMemberManage); err != nil {
+		return domain.BoardInviteLink{}, "", err
+	}
+
+	if err := s.policy.AssignableRole(boardID, role); err != nil {
+		return domain.BoardInviteLink{}, "", err
+	}
+
+	token, err := generateSecret(inviteLinkTokenPrefix)
+	if err != nil {
+		return domain.BoardInviteLink{}, "", err
+	}
+
+	link := domain.BoardInviteLink{
+		ID:        s.generateID(),
+		BoardID:   boardID,
+		Role:      role,
+		CreatedBy: ownerID,
+		TokenHash: hashToken(token),
+		MaxUses:   maxUses,
+		CreatedAt: now,
+		ExpiresAt: expiresAt,
+	}
+
+	if err := s.inviteLinkRepo.Create(link); err != nil {
+		return domain.BoardInviteLink{}, "", err
+	}
+
+	return link, token, nil
+}
+
+func (s *boardService) GetInviteLinks(ownerID, boardID string) ([]domain.BoardInviteLink, error) {
+	if boardID == "" {
+		return nil, domain.ErrInvalidInput
+	}
+
+	if _, err := s.policy.Authorize(boardID, ownerID, domain.ActionMemberManage); err != nil {
+		return nil, err
+	}
+
+	return s.inviteLinkRepo.ListActiveByBoard(boardID, time.Now())
+}
+
+func (s *boardService) RevokeInviteLink(ownerID, linkID string) error {
+	if linkID == "" {
+		return domain.ErrInvalidInput
+	}
+
+	link, err := s.inviteLinkRepo.GetByID(linkID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.policy.Authorize(link.BoardID, ownerID, domain.ActionMemberManage); err != nil {
+		return err
+	}
+
+	return s.inviteLinkRepo.Revoke(link.BoardID, link.ID, time.Now())
+}
+
+func (s *boardService) JoinByInviteLink(userID, token string) (domain.Board, error) {
+	if userID == "" || token == "" {
+		return domain.Board{}, domain.ErrInvalidInput
+	}
+
+	link, err := s.inviteLinkRepo.GetByTokenHash(hashToken(token))
+	if err != nil {
+		return domain.Board{}, err
+	}
+
+	now := time.Now()
+
+	// отозванная и истёкшая ссылки выглядят так же, как несуществующая;
+	// исчерпанная даёт ErrConflict из Redeem
+	if link.RevokedAt != nil || (link.ExpiresAt != nil && !link.ExpiresAt.After(now)) {
+		return domain.Board{}, domain.ErrNotFound
+	}
+
+	board, err := s.boardRepo.GetByID(link.BoardID)
+	if err != nil {
+		return domain.Board{}, err
+	}
+
+	err = s.inviteLinkRepo.Redeem(link.ID, domain.BoardMember{
+		ID:        s.generateID(),
+		BoardID:   link.BoardID,
+		UserID:    userID,
+		Role:      link.Role,
+		CreatedAt: now,
+	}, now)
+	// повторный переход по ссылке участником доски ничего не меняет
+	if errors.Is(err, domain.ErrUserAlreadyExists) {
+		return board, nil
+	}
+	if err != nil {
+		return domain.Board{}, err
+	}
+
+	s.events.Publish(newBoardEvent(domain.EventMemberAdded, link.BoardID, userID, map[string]string{
+		"user_id": userID,
+		"role":    string(link.Role),
+	}))
+
+	return board, nil
 }
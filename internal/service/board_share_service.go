@@ -0,0 +1,155 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/storage"
+)
+
+const boardShareTokenPrefix = "tss_"
+
+// BoardShareService публикует доску по ссылке только для чтения.
+// Токен ссылки не является учётными данными: ни один изменяющий
+// endpoint его не принимает, он открывает только GetPublicBoard.
+type BoardShareService interface {
+	Get(ownerID, boardID string) (domain.BoardShare, error)
+	// Enable публикует доску или выпускает новый токен взамен прежнего;
+	// токен возвращается только здесь
+	Enable(ownerID, boardID string) (domain.BoardShare, string, error)
+	Disable(ownerID, boardID string) error
+
+	GetPublicBoard(token string) (domain.PublicBoard, error)
+}
+
+type boardShareService struct {
+	shareRepo  storage.BoardShareRepository
+	boardRepo  storage.BoardRepository
+	columnRepo storage.ColumnRepository
+	taskRepo   storage.TaskRepository
+	policy     BoardPolicy
+}
+
+func NewBoardShareService(
+	shareRepo storage.BoardShareRepository,
+	boardRepo storage.BoardRepository,
+	columnRepo storage.ColumnRepository,
+	taskRepo storage.TaskRepository,
+	policy BoardPolicy,
+) BoardShareService {
+	return &boardShareService{
+		shareRepo:  shareRepo,
+		boardRepo:  boardRepo,
+		columnRepo: columnRepo,
+		taskRepo:   taskRepo,
+		policy:     policy,
+	}
+}
+
+func (s *boardShareService) Get(ownerID, boardID string) (domain.BoardShare, error) {
+	if boardID == "" {
+		return domain.BoardShare{}, domain.ErrInvalidInput
+	}
+
+	if _, err := s.policy.Authorize(boardID, ownerID, domain.ActionBoardShare); err != nil {
+		return domain.BoardShare{}, err
+	}
+
+	return s.shareRepo.GetByBoard(boardID)
+}
+
+func (s *boardShareService) Enable(ownerID, boardID string) (domain.BoardShare, string, error) {
+	if boardID == "" {
+		return domain.BoardShare{}, "", domain.ErrInvalidInput
+	}
+
+	if _, err := s.policy.Authorize(boardID, ownerID, domain.ActionBoardShare); err != nil {
+		return domain.BoardShare{}, "", err
+	}
+
+	token, err := generateSecret(boardShareTokenPrefix)
+	if err != nil {
+		return domain.BoardShare{}, "", err
+	}
+
+	share := domain.BoardShare{
+		BoardID:   boardID,
+		TokenHash: hashToken(token),
+		CreatedBy: ownerID,
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.shareRepo.Save(share); err != nil {
+		return domain.BoardShare{}, "", err
+	}
+
+	return share, token, nil
+}
+
+func (s *boardShareService) Disable(ownerID, boardID string) error {
+	if boardID == "" {
+		return domain.ErrInvalidInput
+	}
+
+	if _, err := s.policy.Authorize(boardID, ownerID, domain.ActionBoardShare); err != nil {
+		return err
+	}
+
+	return s.shareRepo.Delete(boardID)
+}
+
+func (s *boardShareService) GetPublicBoard(token string) (domain.PublicBoard, error) {
+	// чужие токены даже не ищем в базе
+	if !strings.HasPrefix(token, boardShareTokenPrefix) {
+		return domain.PublicBoard{}, domain.ErrNotFound
+	}
+
+	share, err := s.shareRepo.GetByTokenHash(hashToken(token))
+	if err != nil {
+		return domain.PublicBoard{}, err
+	}
+
+	board, err := s.boardRepo.GetByID(share.BoardID)
+	if err != nil {
+		return domain.PublicBoard{}, err
+	}
+
+	columns, err := s.columnRepo.GetByBoardID(board.ID)
+	if err != nil {
+		return domain.PublicBoard{}, err
+	}
+
+	public := domain.PublicBoard{
+		ID:      board.ID,
+		Name:    board.Name,
+		Columns: make([]domain.PublicColumn, 0, len(columns)),
+	}
+
+	for _, column := range columns {
+		tasks, err := s.taskRepo.GetByColumnID(column.ID)
+		if err != nil {
+			return domain.PublicBoard{}, err
+		}
+
+		publicColumn := domain.PublicColumn{
+			ID:       column.ID,
+			Title:    column.Title,
+			Position: column.Position,
+			Tasks:    make([]domain.PublicTask, 0, len(tasks)),
+		}
+
+		for _, task := range tasks {
+			publicColumn.Tasks = append(publicColumn.Tasks, domain.PublicTask{
+				ID:          task.ID,
+				Title:       task.Title,
+				Description: task.Description,
+				Position:    task.Position,
+			})
+		}
+
+		public.Columns = append(public.Columns, publicColumn)
+	}
+
+	return public, nil
+}
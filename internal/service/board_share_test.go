@@ -0,0 +1,162 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/storage"
+)
+
+type fakeShareRepo struct {
+	storage.BoardShareRepository
+	shares map[string]domain.BoardShare
+}
+
+func (r *fakeShareRepo) Save(share domain.BoardShare) error {
+	r.shares[share.BoardID] = share
+	return nil
+}
+
+func (r *fakeShareRepo) GetByBoard(boardID string) (domain.BoardShare, error) {
+	share, ok := r.shares[boardID]
+	if !ok {
+		return domain.BoardShare{}, domain.ErrNotFound
+	}
+	return share, nil
+}
+
+func (r *fakeShareRepo) GetByTokenHash(tokenHash string) (domain.BoardShare, error) {
+	for _, share := range r.shares {
+		if share.TokenHash == tokenHash {
+			return share, nil
+		}
+	}
+	return domain.BoardShare{}, domain.ErrNotFound
+}
+
+func (r *fakeShareRepo) Delete(boardID string) error {
+	if _, ok := r.shares[boardID]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(r.shares, boardID)
+	return nil
+}
+
+type fakeColumnRepo struct {
+	storage.ColumnRepository
+	columns []domain.Column
+}
+
+func (r *fakeColumnRepo) GetByBoardID(boardID string) ([]domain.Column, error) {
+	return r.columns, nil
+}
+
+type fakeTaskRepo struct {
+	storage.TaskRepository
+	tasks map[string][]domain.Task
+}
+
+func (r *fakeTaskRepo) GetByColumnID(columnID string) ([]domain.Task, error) {
+	return r.tasks[columnID], nil
+}
+
+type rejectingJWT struct {
+	JWTManager
+}
+
+func (rejectingJWT) ParseAccessToken(token string) (string, error) {
+	return "", errors.New("invalid token")
+}
+
+func newTestBoardShareService() BoardShareService {
+	members := &fakeMemberRepo{roles: map[string]domain.BoardRole{
+		"board-1|owner":  domain.BoardRoleOwner,
+		"board-1|editor": domain.BoardRoleEditor,
+	}}
+
+	return NewBoardShareService(
+		&fakeShareRepo{shares: make(map[string]domain.BoardShare)},
+		fakeBoardRepo{},
+		&fakeColumnRepo{columns: []domain.Column{{ID: "col-1", BoardID: "board-1", Title: "Q3"}}},
+		&fakeTaskRepo{tasks: map[string][]domain.Task{
+			"col-1": {{ID: "task-1", ColumnID: "col-1", Title: "Dark mode"}},
+		}},
+		NewBoardPolicy(members, newFakeRoleRepo(), newFakeWorkspaceMemberRepo()),
+	)
+}
+
+func TestBoardSharePublicView(t *testing.T) {
+	service := newTestBoardShareService()
+
+	if _, _, err := service.Enable("editor", "board-1"); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden for editor, got %v", err)
+	}
+
+	share, token, err := service.Enable("owner", "board-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(token, boardShareTokenPrefix) {
+		t.Errorf("expected token with prefix %s, got %s", boardShareTokenPrefix, token)
+	}
+	if share.TokenHash == token {
+		t.Error("token must be stored hashed")
+	}
+
+	board, err := service.GetPublicBoard(token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if board.Name != "Roadmap" || len(board.Columns) != 1 || len(board.Columns[0].Tasks) != 1 {
+		t.Fatalf("unexpected public board: %+v", board)
+	}
+	if board.Columns[0].Tasks[0].Title != "Dark mode" {
+		t.Errorf("expected task Dark mode, got %s", board.Columns[0].Tasks[0].Title)
+	}
+}
+
+func TestBoardShareRotateAndDisable(t *testing.T) {
+	service := newTestBoardShareService()
+
+	_, old, err := service.Enable("owner", "board-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, rotated, err := service.Enable("owner", "board-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := service.GetPublicBoard(old); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected old token to stop working, got %v", err)
+	}
+	if _, err := service.GetPublicBoard(rotated); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := service.Disable("owner", "board-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := service.GetPublicBoard(rotated); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected disabled share to be not found, got %v", err)
+	}
+}
+
+func TestShareTokenIsNotCredential(t *testing.T) {
+	service := newTestBoardShareService()
+
+	_, token, err := service.Enable("owner", "board-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// изменяющие endpoint'ы проходят через Authenticator, и токен ссылки
+	// там не принимается
+	authenticator := NewAuthenticator(rejectingJWT{}, nil, nil)
+	if _, err := authenticator.Authenticate(token); !errors.Is(err, domain.ErrInvalidCredentials) {
+		t.Errorf("expected ErrInvalidCredentials, got %v", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/infra/events"
 	"github.com/ovk741/TasksStream/internal/storage/postgres"
 )
 
@@ -23,12 +24,13 @@ func TestCreateBoard(t *testing.T) {
 	columnRepo := postgres.NewColumnRepository(pool)
 	taskRepo := postgres.NewTaskRepository(pool)
 	boardMemberRepo := postgres.NewBoardMemberRepository(pool)
+	inviteLinkRepo := postgres.NewBoardInviteLinkRepository(pool)
 
 	generateID := func() string {
 		return "board-1"
 	}
 
-	service := NewBoardService(boardRepo, columnRepo, taskRepo, boardMemberRepo, generateID)
+	service := NewBoardService(boardRepo, columnRepo, taskRepo, boardMemberRepo, postgres.NewTaskAssigneeRepository(pool), NewBoardPolicy(boardMemberRepo, postgres.NewBoardRoleRepository(pool), postgres.NewWorkspaceMemberRepository(pool)), inviteLinkRepo, events.NewBroker(), generateID)
 
 	board, err := service.Create("1", "My board")
 
@@ -58,8 +60,9 @@ func TestBoardServiceCreateInvalidInput(t *testing.T) {
 	columnRepo := postgres.NewColumnRepository(pool)
 	taskRepo := postgres.NewTaskRepository(pool)
 	boardMemberRepo := postgres.NewBoardMemberRepository(pool)
+	inviteLinkRepo := postgres.NewBoardInviteLinkRepository(pool)
 
-	service := NewBoardService(boardRepo, columnRepo, taskRepo, boardMemberRepo, func() string {
+	service := NewBoardService(boardRepo, columnRepo, taskRepo, boardMemberRepo, postgres.NewTaskAssigneeRepository(pool), NewBoardPolicy(boardMemberRepo, postgres.NewBoardRoleRepository(pool), postgres.NewWorkspaceMemberRepository(pool)), inviteLinkRepo, events.NewBroker(), func() string {
 		return "board-1"
 	})
 
@@ -87,8 +90,9 @@ func TestBoardServiceGetAllEmpty(t *testing.T) {
 	columnRepo := postgres.NewColumnRepository(pool)
 	taskRepo := postgres.NewTaskRepository(pool)
 	boardMemberRepo := postgres.NewBoardMemberRepository(pool)
+	inviteLinkRepo := postgres.NewBoardInviteLinkRepository(pool)
 
-	service := NewBoardService(boardRepo, columnRepo, taskRepo, boardMemberRepo, func() string {
+	service := NewBoardService(boardRepo, columnRepo, taskRepo, boardMemberRepo, postgres.NewTaskAssigneeRepository(pool), NewBoardPolicy(boardMemberRepo, postgres.NewBoardRoleRepository(pool), postgres.NewWorkspaceMemberRepository(pool)), inviteLinkRepo, events.NewBroker(), func() string {
 		return "id"
 	})
 
@@ -116,8 +120,9 @@ func TestBoardServiceGetAllWithData(t *testing.T) {
 	columnRepo := postgres.NewColumnRepository(pool)
 	taskRepo := postgres.NewTaskRepository(pool)
 	boardMemberRepo := postgres.NewBoardMemberRepository(pool)
+	inviteLinkRepo := postgres.NewBoardInviteLinkRepository(pool)
 
-	service := NewBoardService(boardRepo, columnRepo, taskRepo, boardMemberRepo, func() string {
+	service := NewBoardService(boardRepo, columnRepo, taskRepo, boardMemberRepo, postgres.NewTaskAssigneeRepository(pool), NewBoardPolicy(boardMemberRepo, postgres.NewBoardRoleRepository(pool), postgres.NewWorkspaceMemberRepository(pool)), inviteLinkRepo, events.NewBroker(), func() string {
 		return "id"
 	})
 
@@ -0,0 +1,359 @@
+package service
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/storage"
+)
+
+// ChecklistService управляет чек-листами задач. Изменения чек-листа
+// рассылаются событием checklist.updated с чек-листом целиком.
+type ChecklistService interface {
+	List(userID, taskID string) ([]domain.Checklist, error)
+	Create(userID, taskID, title string) (domain.Checklist, error)
+	Rename(userID, checklistID, title string) (domain.Checklist, error)
+	Delete(userID, checklistID string) error
+
+	AddItem(userID, checklistID, title string) (domain.ChecklistItem, error)
+	UpdateItem(userID, itemID, title string, done bool) (domain.ChecklistItem, error)
+	// MoveItem переносит пункт на position в чек-листе той же задачи
+	MoveItem(userID, itemID, checklistID string, position int) (domain.ChecklistItem, error)
+	DeleteItem(userID, itemID string) error
+	// ConvertItem превращает пункт в задачу в конце колонки той же доски;
+	// пункт при этом удаляется
+	ConvertItem(userID, itemID, columnID string) (domain.Task, error)
+}
+
+type checklistService struct {
+	checklistRepo storage.ChecklistRepository
+	taskRepo      storage.TaskRepository
+	columnRepo    storage.ColumnRepository
+	policy        BoardPolicy
+	events        EventPublisher
+	generateID    func() string
+}
+
+func NewChecklistService(
+	checklistRepo storage.ChecklistRepository,
+	taskRepo storage.TaskRepository,
+	columnRepo storage.ColumnRepository,
+	policy BoardPolicy,
+	events EventPublisher,
+	generateID func() string,
+) ChecklistService {
+	return &checklistService{
+		checklistRepo: checklistRepo,
+		taskRepo:      taskRepo,
+		columnRepo:    columnRepo,
+		policy:        policy,
+		events:        events,
+		generateID:    generateID,
+	}
+}
+
+func (s *checklistService) List(userID, taskID string) ([]domain.Checklist, error) {
+	if taskID == "" {
+		return nil, domain.ErrInvalidInput
+	}
+
+	if _, err := s.authorizeTask(taskID, userID, domain.ActionBoardRead); err != nil {
+		return nil, err
+	}
+
+	return s.checklistRepo.ListByTask(taskID)
+}
+
+func (s *checklistService) Create(userID, taskID, title string) (domain.Checklist, error) {
+	title = strings.TrimSpace(title)
+	if taskID == "" || title == "" {
+		return domain.Checklist{}, domain.ErrInvalidInput
+	}
+
+	boardID, err := s.authorizeTask(taskID, userID, domain.ActionTaskWrite)
+	if err != nil {
+		return domain.Checklist{}, err
+	}
+
+	checklists, err := s.checklistRepo.ListByTask(taskID)
+	if err != nil {
+		return domain.Checklist{}, err
+	}
+
+	checklist := domain.Checklist{
+		ID:        s.generateID(),
+		TaskID:    taskID,
+		Title:     title,
+		Position:  len(checklists),
+		CreatedAt: time.Now(),
+		Items:     []domain.ChecklistItem{},
+	}
+
+	if err := s.checklistRepo.Create(checklist); err != nil {
+		return domain.Checklist{}, err
+	}
+
+	s.events.Publish(newBoardEvent(domain.EventChecklistCreated, boardID, userID, checklist))
+
+	return checklist, nil
+}
+
+func (s *checklistService) Rename(userID, checklistID, title string) (domain.Checklist, error) {
+	title = strings.TrimSpace(title)
+	if checklistID == "" || title == "" {
+		return domain.Checklist{}, domain.ErrInvalidInput
+	}
+
+	checklist, boardID, err := s.authorizeChecklist(checklistID, userID)
+	if err != nil {
+		return domain.Checklist{}, err
+	}
+
+	if err := s.checklistRepo.Rename(checklistID, title); err != nil {
+		return domain.Checklist{}, err
+	}
+	checklist.Title = title
+
+	s.events.Publish(newBoardEvent(domain.EventChecklistUpdated, boardID, userID, checklist))
+
+	return checklist, nil
+}
+
+func (s *checklistService) Delete(userID, checklistID string) error {
+	if checklistID == "" {
+		return domain.ErrInvalidInput
+	}
+
+	checklist, boardID, err := s.authorizeChecklist(checklistID, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checklistRepo.Delete(checklistID); err != nil {
+		return err
+	}
+
+	s.events.Publish(newBoardEvent(domain.EventChecklistDeleted, boardID, userID, map[string]string{
+		"id":      checklistID,
+		"task_id": checklist.TaskID,
+	}))
+
+	return nil
+}
+
+func (s *checklistService) AddItem(userID, checklistID, title string) (domain.ChecklistItem, error) {
+	title = strings.TrimSpace(title)
+	if checklistID == "" || title == "" {
+		return domain.ChecklistItem{}, domain.ErrInvalidInput
+	}
+
+	checklist, boardID, err := s.authorizeChecklist(checklistID, userID)
+	if err != nil {
+		return domain.ChecklistItem{}, err
+	}
+
+	item := domain.ChecklistItem{
+		ID:          s.generateID(),
+		ChecklistID: checklistID,
+		Title:       title,
+		Position:    len(checklist.Items),
+		CreatedAt:   time.Now(),
+	}
+
+	if err := s.checklistRepo.AddItem(item); err != nil {
+		return domain.ChecklistItem{}, err
+	}
+
+	s.publishUpdated(checklistID, boardID, userID)
+
+	return item, nil
+}
+
+func (s *checklistService) UpdateItem(userID, itemID, title string, done bool) (domain.ChecklistItem, error) {
+	title = strings.TrimSpace(title)
+	if itemID == "" || title == "" {
+		return domain.ChecklistItem{}, domain.ErrInvalidInput
+	}
+
+	item, err := s.checklistRepo.GetItem(itemID)
+	if err != nil {
+		return domain.ChecklistItem{}, err
+	}
+
+	_, boardID, err := s.authorizeChecklist(item.ChecklistID, userID)
+	if err != nil {
+		return domain.ChecklistItem{}, err
+	}
+
+	item.Title = title
+	item.Done = done
+
+	if err := s.checklistRepo.UpdateItem(item); err != nil {
+		return domain.ChecklistItem{}, err
+	}
+
+	s.publishUpdated(item.ChecklistID, boardID, userID)
+
+	return item, nil
+}
+
+func (s *checklistService) MoveItem(userID, itemID, checklistID string, position int) (domain.ChecklistItem, error) {
+	if itemID == "" || checklistID == "" || position < 0 {
+		return domain.ChecklistItem{}, domain.ErrInvalidInput
+	}
+
+	item, err := s.checklistRepo.GetItem(itemID)
+	if err != nil {
+		return domain.ChecklistItem{}, err
+	}
+
+	source, boardID, err := s.authorizeChecklist(item.ChecklistID, userID)
+	if err != nil {
+		return domain.ChecklistItem{}, err
+	}
+
+	dest := source
+	if checklistID != source.ID {
+		dest, err = s.checklistRepo.GetByID(checklistID)
+		if err != nil {
+			return domain.ChecklistItem{}, err
+		}
+	}
+
+	// пункт переносится только между чек-листами одной задачи
+	if dest.TaskID != source.TaskID {
+		return domain.ChecklistItem{}, domain.ErrForbidden
+	}
+
+	moved, err := s.checklistRepo.MoveItem(itemID, checklistID, position)
+	if err != nil {
+		return domain.ChecklistItem{}, err
+	}
+
+	s.publishUpdated(source.ID, boardID, userID)
+	if dest.ID != source.ID {
+		s.publishUpdated(dest.ID, boardID, userID)
+	}
+
+	return moved, nil
+}
+
+func (s *checklistService) DeleteItem(userID, itemID string) error {
+	if itemID == "" {
+		return domain.ErrInvalidInput
+	}
+
+	item, err := s.checklistRepo.GetItem(itemID)
+	if err != nil {
+		return err
+	}
+
+	_, boardID, err := s.authorizeChecklist(item.ChecklistID, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checklistRepo.DeleteItem(itemID); err != nil {
+		return err
+	}
+
+	s.publishUpdated(item.ChecklistID, boardID, userID)
+
+	return nil
+}
+
+func (s *checklistService) ConvertItem(userID, itemID, columnID string) (domain.Task, error) {
+	if itemID == "" || columnID == "" {
+		return domain.Task{}, domain.ErrInvalidInput
+	}
+
+	item, err := s.checklistRepo.GetItem(itemID)
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	_, boardID, err := s.authorizeChecklist(item.ChecklistID, userID)
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	column, err := s.columnRepo.GetByID(columnID)
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	// задача из пункта создаётся на той же доске
+	if column.BoardID != boardID {
+		return domain.Task{}, domain.ErrForbidden
+	}
+
+	tasks, err := s.taskRepo.GetByColumnID(columnID)
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	created, err := s.checklistRepo.ConvertItem(itemID, domain.Task{
+		ID:        s.generateID(),
+		Title:     item.Title,
+		ColumnID:  columnID,
+		Position:  len(tasks),
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	s.events.Publish(newBoardEvent(domain.EventTaskCreated, boardID, userID, created))
+	s.publishUpdated(item.ChecklistID, boardID, userID)
+
+	return created, nil
+}
+
+// authorizeTask проверяет право на доске задачи и возвращает ID доски
+func (s *checklistService) authorizeTask(taskID, userID string, action domain.Action) (string, error) {
+	task, err := s.taskRepo.GetByID(taskID)
+	if err != nil {
+		return "", err
+	}
+
+	column, err := s.columnRepo.GetByID(task.ColumnID)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := s.policy.Authorize(column.BoardID, userID, action); err != nil {
+		return "", err
+	}
+
+	return column.BoardID, nil
+}
+
+// authorizeChecklist проверяет право на изменение задачи чек-листа
+// и возвращает чек-лист с пунктами и ID доски
+func (s *checklistService) authorizeChecklist(checklistID, userID string) (domain.Checklist, string, error) {
+	checklist, err := s.checklistRepo.GetByID(checklistID)
+	if err != nil {
+		return domain.Checklist{}, "", err
+	}
+
+	boardID, err := s.authorizeTask(checklist.TaskID, userID, domain.ActionTaskWrite)
+	if err != nil {
+		return domain.Checklist{}, "", err
+	}
+
+	return checklist, boardID, nil
+}
+
+// publishUpdated рассылает чек-лист в текущем состоянии; если прочитать его
+// не удалось, событие пропускается — изменение уже сохранено
+func (s *checklistService) publishUpdated(checklistID, boardID, userID string) {
+	checklist, err := s.checklistRepo.GetByID(checklistID)
+	if err != nil {
+		log.Printf("read checklist %s for event: %v", checklistID, err)
+		return
+	}
+
+	s.events.Publish(newBoardEvent(domain.EventChecklistUpdated, boardID, userID, checklist))
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/storage"
+)
+
+type fakeChecklistRepo struct {
+	storage.ChecklistRepository
+	checklists map[string]domain.Checklist
+	items      map[string]domain.ChecklistItem
+	converted  []domain.Task
+}
+
+func (r *fakeChecklistRepo) GetByID(id string) (domain.Checklist, error) {
+	checklist, ok := r.checklists[id]
+	if !ok {
+		return domain.Checklist{}, domain.ErrNotFound
+	}
+
+	checklist.Items = []domain.ChecklistItem{}
+	for _, item := range r.items {
+		if item.ChecklistID == id {
+			checklist.Items = append(checklist.Items, item)
+		}
+	}
+	sort.Slice(checklist.Items, func(i, j int) bool {
+		return checklist.Items[i].Position < checklist.Items[j].Position
+	})
+
+	return checklist, nil
+}
+
+func (r *fakeChecklistRepo) AddItem(item domain.ChecklistItem) error {
+	r.items[item.ID] = item
+	return nil
+}
+
+func (r *fakeChecklistRepo) GetItem(id string) (domain.ChecklistItem, error) {
+	item, ok := r.items[id]
+	if !ok {
+		return domain.ChecklistItem{}, domain.ErrNotFound
+	}
+	return item, nil
+}
+
+func (r *fakeChecklistRepo) MoveItem(itemID, checklistID string, position int) (domain.ChecklistItem, error) {
+	for id, item := range r.items {
+		if item.ChecklistID == checklistID && item.Position >= position {
+			item.Position++
+			r.items[id] = item
+		}
+	}
+
+	item := r.items[itemID]
+	item.ChecklistID = checklistID
+	item.Position = position
+	r.items[itemID] = item
+
+	return item, nil
+}
+
+func (r *fakeChecklistRepo) ConvertItem(itemID string, task domain.Task) (domain.Task, error) {
+	delete(r.items, itemID)
+	r.converted = append(r.converted, task)
+	return task, nil
+}
+
+func newTestChecklistService() (ChecklistService, *fakeChecklistRepo) {
+	members := &fakeMemberRepo{roles: map[string]domain.BoardRole{
+		"board-1|editor": domain.BoardRoleEditor,
+		"board-1|viewer": domain.BoardRoleViewer,
+		"board-2|editor": domain.BoardRoleEditor,
+	}}
+
+	checklists := &fakeChecklistRepo{
+		checklists: map[string]domain.Checklist{
+			"todo":  {ID: "todo", TaskID: "task-1", Title: "Todo"},
+			"qa":    {ID: "qa", TaskID: "task-1", Title: "QA", Position: 1},
+			"other": {ID: "other", TaskID: "task-2", Title: "Other"},
+		},
+		items: map[string]domain.ChecklistItem{
+			"a": {ID: "a", ChecklistID: "todo", Title: "Design", Position: 0},
+			"b": {ID: "b", ChecklistID: "todo", Title: "Build", Position: 1},
+			"c": {ID: "c", ChecklistID: "todo", Title: "Ship", Position: 2},
+		},
+	}
+
+	service := NewChecklistService(
+		checklists,
+		&fakeTaskRepo{tasks: map[string][]domain.Task{
+			"col-1": {{ID: "task-1", ColumnID: "col-1", Title: "Release"}},
+			"col-2": {{ID: "task-2", ColumnID: "col-2", Title: "Elsewhere"}},
+		}},
+		&fakeColumnRepo{columns: []domain.Column{
+			{ID: "col-1", BoardID: "board-1"},
+			{ID: "col-2", BoardID: "board-2"},
+		}},
+		NewBoardPolicy(members, newFakeRoleRepo(), newFakeWorkspaceMemberRepo()),
+		discardEvents{},
+		func() string { return "new" },
+	)
+
+	return service, checklists
+}
+
+func TestAddChecklistItem(t *testing.T) {
+	service, _ := newTestChecklistService()
+
+	item, err := service.AddItem("editor", "todo", " Announce ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if item.Title != "Announce" || item.Position != 3 {
+		t.Errorf("expected item at the end of the checklist, got %+v", item)
+	}
+
+	if _, err := service.AddItem("viewer", "todo", "Announce"); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("expected ErrForbidden for viewer, got %v", err)
+	}
+}
+
+func TestMoveChecklistItem(t *testing.T) {
+	service, checklists := newTestChecklistService()
+
+	if _, err := service.MoveItem("editor", "c", "todo", 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	checklist, _ := checklists.GetByID("todo")
+	order := ""
+	for _, item := range checklist.Items {
+		order += item.ID
+	}
+	if order != "cab" {
+		t.Errorf("expected order cab, got %s", order)
+	}
+
+	if _, err := service.MoveItem("editor", "a", "qa", 0); err != nil {
+		t.Fatalf("unexpected error moving to another checklist of the task: %v", err)
+	}
+	if _, err := service.MoveItem("editor", "b", "other", 0); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("expected ErrForbidden for checklist of another task, got %v", err)
+	}
+}
+
+func TestConvertChecklistItem(t *testing.T) {
+	service, checklists := newTestChecklistService()
+
+	task, err := service.ConvertItem("editor", "b", "col-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if task.Title != "Build" || task.ColumnID != "col-1" || task.Position != 1 {
+		t.Errorf("unexpected task: %+v", task)
+	}
+	if _, ok := checklists.items["b"]; ok {
+		t.Error("expected converted item to be removed")
+	}
+
+	if _, err := service.ConvertItem("editor", "a", "col-2"); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("expected ErrForbidden for column of another board, got %v", err)
+	}
+}
@@ -1,7 +1,6 @@
 package service
 
 import (
-	"errors"
 	"time"
 
 	"github.com/ovk741/TasksStream/internal/domain"
@@ -17,26 +16,29 @@ type ColumnService interface {
 }
 
 type columnService struct {
-	columnRepo      storage.ColumnRepository
-	boardRepo       storage.BoardRepository
-	boardMemberRepo storage.BoardMemberRepository
-	taskRepo        storage.TaskRepository
-	generateID      func() string
+	columnRepo storage.ColumnRepository
+	boardRepo  storage.BoardRepository
+	policy     BoardPolicy
+	taskRepo   storage.TaskRepository
+	events     EventPublisher
+	generateID func() string
 }
 
 func NewColumnService(
 	columnRepo storage.ColumnRepository,
 	boardRepo storage.BoardRepository,
-	boardMemberRepo storage.BoardMemberRepository,
+	policy BoardPolicy,
 	taskRepo storage.TaskRepository,
+	events EventPublisher,
 	generateID func() string,
 ) ColumnService {
 	return &columnService{
-		columnRepo:      columnRepo,
-		boardRepo:       boardRepo,
-		boardMemberRepo: boardMemberRepo,
-		taskRepo:        taskRepo,
-		generateID:      generateID,
+		columnRepo: columnRepo,
+		boardRepo:  boardRepo,
+		policy:     policy,
+		taskRepo:   taskRepo,
+		events:     events,
+		generateID: generateID,
 	}
 }
 
@@ -45,7 +47,7 @@ func (s *columnService) Create(userID, title string, boardID string) (domain.Col
 		return domain.Column{}, domain.ErrInvalidInput
 	}
 
-	if err := s.requireBoardAccess(boardID, userID); err != nil {
+	if _, err := s.policy.Authorize(boardID, userID, domain.ActionColumnWrite); err != nil {
 		return domain.Column{}, err
 	}
 
@@ -70,6 +72,8 @@ func (s *columnService) Create(userID, title string, boardID string) (domain.Col
 		return domain.Column{}, err
 	}
 
+	s.events.Publish(newBoardEvent(domain.EventColumnCreated, boardID, userID, column))
+
 	return column, nil
 }
 func (s *columnService) GetByBoardID(userID, boardID string) ([]domain.Column, error) {
@@ -79,7 +83,7 @@ func (s *columnService) GetByBoardID(userID, boardID string) ([]domain.Column, e
 		return nil, domain.ErrNotFound
 	}
 
-	if err := s.requireBoardAccess(boardID, userID); err != nil {
+	if _, err := s.policy.Authorize(boardID, userID, domain.ActionBoardRead); err != nil {
 		return nil, err
 	}
 
@@ -99,7 +103,7 @@ func (s *columnService) Update(userID, columnID string, title string) (domain.Co
 		return domain.Column{}, err
 	}
 
-	if err := s.requireBoardAccess(column.BoardID, userID); err != nil {
+	if _, err := s.policy.Authorize(column.BoardID, userID, domain.ActionColumnWrite); err != nil {
 		return domain.Column{}, err
 	}
 
@@ -109,6 +113,8 @@ func (s *columnService) Update(userID, columnID string, title string) (domain.Co
 		return domain.Column{}, err
 	}
 
+	s.events.Publish(newBoardEvent(domain.EventColumnUpdated, column.BoardID, userID, column))
+
 	return column, nil
 }
 
@@ -123,11 +129,19 @@ func (s *columnService) Delete(userID, columnID string) error {
 		return err
 	}
 
-	if err := s.requireBoardAccess(column.BoardID, userID); err != nil {
+	if _, err := s.policy.Authorize(column.BoardID, userID, domain.ActionColumnDelete); err != nil {
 		return err
 	}
 
-	return s.columnRepo.Delete(columnID)
+	if err := s.columnRepo.Delete(columnID); err != nil {
+		return err
+	}
+
+	s.events.Publish(newBoardEvent(domain.EventColumnDeleted, column.BoardID, userID, map[string]string{
+		"id": columnID,
+	}))
+
+	return nil
 
 }
 
@@ -141,24 +155,16 @@ func (s *columnService) Move(userID, columnID string, position int) (domain.Colu
 		return domain.Column{}, err
 	}
 
-	if err := s.requireBoardAccess(column.BoardID, userID); err != nil {
+	if _, err := s.policy.Authorize(column.BoardID, userID, domain.ActionColumnWrite); err != nil {
 		return domain.Column{}, err
 	}
 
-	return s.columnRepo.Move(columnID, position)
-}
-
-func (s *columnService) requireBoardAccess(
-	boardID, userID string,
-) error {
-
-	_, err := s.boardMemberRepo.GetRole(boardID, userID)
+	moved, err := s.columnRepo.Move(columnID, position)
 	if err != nil {
-		if errors.Is(err, domain.ErrNotFound) {
-			return domain.ErrForbidden
-		}
-		return err
+		return domain.Column{}, err
 	}
 
-	return nil
+	s.events.Publish(newBoardEvent(domain.EventColumnMoved, moved.BoardID, userID, moved))
+
+	return moved, nil
 }
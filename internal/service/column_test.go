@@ -7,6 +7,7 @@ import (
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/infra/events"
 	"github.com/ovk741/TasksStream/internal/storage/postgres"
 )
 
@@ -30,7 +31,7 @@ func TestCreateColumn(t *testing.T) {
 	}
 	boardRepo.Create(board)
 
-	service := NewColumnService(columnRepo, boardRepo, boardMemberRepo, taskRepo, func() string {
+	service := NewColumnService(columnRepo, boardRepo, NewBoardPolicy(boardMemberRepo, postgres.NewBoardRoleRepository(pool), postgres.NewWorkspaceMemberRepository(pool)), taskRepo, events.NewBroker(), func() string {
 		return "column-1"
 	})
 
@@ -67,7 +68,7 @@ func TestColumnServiceCreateInvalidInput(t *testing.T) {
 		ID: "board-1",
 	})
 
-	service := NewColumnService(columnRepo, boardRepo, boardMemberRepo, taskRepo, func() string {
+	service := NewColumnService(columnRepo, boardRepo, NewBoardPolicy(boardMemberRepo, postgres.NewBoardRoleRepository(pool), postgres.NewWorkspaceMemberRepository(pool)), taskRepo, events.NewBroker(), func() string {
 		return "column-1"
 	})
 
@@ -96,7 +97,7 @@ func TestColumnServiceCreateBoardNotFound(t *testing.T) {
 	taskRepo := postgres.NewTaskRepository(pool)
 	boardMemberRepo := postgres.NewBoardMemberRepository(pool)
 
-	service := NewColumnService(columnRepo, boardRepo, boardMemberRepo, taskRepo, func() string {
+	service := NewColumnService(columnRepo, boardRepo, NewBoardPolicy(boardMemberRepo, postgres.NewBoardRoleRepository(pool), postgres.NewWorkspaceMemberRepository(pool)), taskRepo, events.NewBroker(), func() string {
 		return "column-1"
 	})
 
@@ -125,7 +126,7 @@ func TestColumnServiceGetByBoardIDEmpty(t *testing.T) {
 		ID: "board-1",
 	})
 
-	service := NewColumnService(columnRepo, boardRepo, boardMemberRepo, taskRepo, func() string {
+	service := NewColumnService(columnRepo, boardRepo, NewBoardPolicy(boardMemberRepo, postgres.NewBoardRoleRepository(pool), postgres.NewWorkspaceMemberRepository(pool)), taskRepo, events.NewBroker(), func() string {
 		return "id"
 	})
 
@@ -158,7 +159,7 @@ func TestColumnServiceGetByBoardIDWithData(t *testing.T) {
 		ID: "board-1",
 	})
 
-	service := NewColumnService(columnRepo, boardRepo, boardMemberRepo, taskRepo, func() string {
+	service := NewColumnService(columnRepo, boardRepo, NewBoardPolicy(boardMemberRepo, postgres.NewBoardRoleRepository(pool), postgres.NewWorkspaceMemberRepository(pool)), taskRepo, events.NewBroker(), func() string {
 		return "column-id"
 	})
 
@@ -0,0 +1,316 @@
+package service
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/storage"
+)
+
+const (
+	maxFieldNameLength  = 50
+	maxFieldOptions     = 50
+	maxFieldTextLength  = 1000
+	customFieldDateForm = "2006-01-02"
+)
+
+// CustomFieldService управляет схемой полей задач доски. Значения полей
+// задаются вместе с задачей и проверяются в TaskService.
+type CustomFieldService interface {
+	List(userID, boardID string) ([]domain.CustomField, error)
+	Create(userID, boardID, name string, fieldType domain.CustomFieldType, options []string) (domain.CustomField, error)
+	// Update меняет имя и варианты выбора; тип поля не меняется
+	Update(userID, fieldID, name string, options []string) (domain.CustomField, error)
+	Delete(userID, fieldID string) error
+}
+
+type customFieldService struct {
+	fieldRepo  storage.CustomFieldRepository
+	policy     BoardPolicy
+	events     EventPublisher
+	generateID func() string
+}
+
+func NewCustomFieldService(
+	fieldRepo storage.CustomFieldRepository,
+	policy BoardPolicy,
+	events EventPublisher,
+	generateID func() string,
+) CustomFieldService {
+	return &customFieldService{
+		fieldRepo:  fieldRepo,
+		policy:     policy,
+		events:     events,
+		generateID: generateID,
+	}
+}
+
+func (s *customFieldService) List(userID, boardID string) ([]domain.CustomField, error) {
+	if boardID == "" {
+		return nil, domain.ErrInvalidInput
+	}
+
+	if _, err := s.policy.Authorize(boardID, userID, domain.ActionBoardRead); err != nil {
+		return nil, err
+	}
+
+	return s.fieldRepo.ListByBoard(boardID)
+}
+
+func (s *customFieldService) Create(
+	userID, boardID, name string,
+	fieldType domain.CustomFieldType,
+	options []string,
+) (domain.CustomField, error) {
+	if boardID == "" {
+		return domain.CustomField{}, domain.ErrInvalidInput
+	}
+
+	field, err := normalizeCustomField(domain.CustomField{Name: name, Type: fieldType, Options: options})
+	if err != nil {
+		return domain.CustomField{}, err
+	}
+
+	if _, err := s.policy.Authorize(boardID, userID, domain.ActionFieldManage); err != nil {
+		return domain.CustomField{}, err
+	}
+
+	field.ID = s.generateID()
+	field.BoardID = boardID
+	field.CreatedAt = time.Now()
+
+	if err := s.fieldRepo.Create(field); err != nil {
+		return domain.CustomField{}, err
+	}
+
+	s.events.Publish(newBoardEvent(domain.EventFieldCreated, boardID, userID, field))
+
+	return field, nil
+}
+
+func (s *customFieldService) Update(userID, fieldID, name string, options []string) (domain.CustomField, error) {
+	if fieldID == "" {
+		return domain.CustomField{}, domain.ErrInvalidInput
+	}
+
+	field, err := s.fieldRepo.GetByID(fieldID)
+	if err != nil {
+		return domain.CustomField{}, err
+	}
+
+	if _, err := s.policy.Authorize(field.BoardID, userID, domain.ActionFieldManage); err != nil {
+		return domain.CustomField{}, err
+	}
+
+	field.Name = name
+	field.Options = options
+
+	field, err = normalizeCustomField(field)
+	if err != nil {
+		return domain.CustomField{}, err
+	}
+
+	if err := s.fieldRepo.Update(field); err != nil {
+		return domain.CustomField{}, err
+	}
+
+	s.events.Publish(newBoardEvent(domain.EventFieldUpdated, field.BoardID, userID, field))
+
+	return field, nil
+}
+
+func (s *customFieldService) Delete(userID, fieldID string) error {
+	if fieldID == "" {
+		return domain.ErrInvalidInput
+	}
+
+	field, err := s.fieldRepo.GetByID(fieldID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.policy.Authorize(field.BoardID, userID, domain.ActionFieldManage); err != nil {
+		return err
+	}
+
+	if err := s.fieldRepo.Delete(fieldID); err != nil {
+		return err
+	}
+
+	s.events.Publish(newBoardEvent(domain.EventFieldDeleted, field.BoardID, userID, map[string]string{
+		"id": fieldID,
+	}))
+
+	return nil
+}
+
+// normalizeCustomField проверяет имя, тип и варианты поля. Варианты
+// обязательны только для полей выбора, пробелы по краям обрезаются,
+// повторы не допускаются.
+func normalizeCustomField(field domain.CustomField) (domain.CustomField, error) {
+	field.Name = strings.TrimSpace(field.Name)
+	if field.Name == "" || len([]rune(field.Name)) > maxFieldNameLength {
+		return domain.CustomField{}, domain.ErrInvalidInput
+	}
+
+	switch field.Type {
+	case domain.CustomFieldText, domain.CustomFieldNumber, domain.CustomFieldDate, domain.CustomFieldUser:
+		if len(field.Options) > 0 {
+			return domain.CustomField{}, domain.ErrInvalidInput
+		}
+		field.Options = nil
+		return field, nil
+	case domain.CustomFieldSelect, domain.CustomFieldMultiSelect:
+	default:
+		return domain.CustomField{}, domain.ErrInvalidInput
+	}
+
+	if len(field.Options) == 0 || len(field.Options) > maxFieldOptions {
+		return domain.CustomField{}, domain.ErrInvalidInput
+	}
+
+	seen := make(map[string]bool, len(field.Options))
+	options := make([]string, 0, len(field.Options))
+
+	for _, option := range field.Options {
+		option = strings.TrimSpace(option)
+		if option == "" || len([]rune(option)) > maxFieldNameLength || seen[option] {
+			return domain.CustomField{}, domain.ErrInvalidInput
+		}
+		seen[option] = true
+		options = append(options, option)
+	}
+	field.Options = options
+
+	return field, nil
+}
+
+// normalizeFieldValues проверяет значения задачи по схеме полей доски.
+// Пустое значение (null, "" или пустой список) означает, что поле не задано.
+// isMember проверяет, что пользователь из поля типа user имеет доступ к доске.
+func normalizeFieldValues(
+	fields []domain.CustomField,
+	values domain.CustomFieldValues,
+	isMember func(userID string) (bool, error),
+) (domain.CustomFieldValues, error) {
+	byID := make(map[string]domain.CustomField, len(fields))
+	for _, field := range fields {
+		byID[field.ID] = field
+	}
+
+	result := make(domain.CustomFieldValues, len(values))
+
+	for fieldID, raw := range values {
+		field, ok := byID[fieldID]
+		if !ok {
+			return nil, domain.ErrInvalidInput
+		}
+		if raw == nil {
+			continue
+		}
+
+		value, err := normalizeFieldValue(field, raw, isMember)
+		if err != nil {
+			return nil, err
+		}
+		if value != nil {
+			result[fieldID] = value
+		}
+	}
+
+	return result, nil
+}
+
+func normalizeFieldValue(
+	field domain.CustomField,
+	raw any,
+	isMember func(userID string) (bool, error),
+) (any, error) {
+	if field.Type == domain.CustomFieldNumber {
+		number, ok := raw.(float64)
+		if !ok {
+			return nil, domain.ErrInvalidInput
+		}
+		return number, nil
+	}
+
+	if field.Type == domain.CustomFieldMultiSelect {
+		selected, ok := stringList(raw)
+		if !ok {
+			return nil, domain.ErrInvalidInput
+		}
+		if len(selected) == 0 {
+			return nil, nil
+		}
+
+		seen := make(map[string]bool, len(selected))
+		result := make([]string, 0, len(selected))
+		for _, option := range selected {
+			if !slices.Contains(field.Options, option) {
+				return nil, domain.ErrInvalidInput
+			}
+			if !seen[option] {
+				seen[option] = true
+				result = append(result, option)
+			}
+		}
+		return result, nil
+	}
+
+	text, ok := raw.(string)
+	if !ok {
+		return nil, domain.ErrInvalidInput
+	}
+	if text == "" {
+		return nil, nil
+	}
+
+	switch field.Type {
+	case domain.CustomFieldText:
+		if len([]rune(text)) > maxFieldTextLength {
+			return nil, domain.ErrInvalidInput
+		}
+	case domain.CustomFieldDate:
+		if _, err := time.Parse(customFieldDateForm, text); err != nil {
+			return nil, domain.ErrInvalidInput
+		}
+	case domain.CustomFieldSelect:
+		if !slices.Contains(field.Options, text) {
+			return nil, domain.ErrInvalidInput
+		}
+	case domain.CustomFieldUser:
+		member, err := isMember(text)
+		if err != nil {
+			return nil, err
+		}
+		if !member {
+			return nil, domain.ErrInvalidInput
+		}
+	default:
+		return nil, domain.ErrInvalidInput
+	}
+
+	return text, nil
+}
+
+// stringList принимает []string и список строк из JSON ([]any)
+func stringList(raw any) ([]string, bool) {
+	switch list := raw.(type) {
+	case []string:
+		return list, true
+	case []any:
+		result := make([]string, 0, len(list))
+		for _, item := range list {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			result = append(result, s)
+		}
+		return result, true
+	default:
+		return nil, false
+	}
+}
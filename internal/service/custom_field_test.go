@@ -0,0 +1,147 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/storage"
+)
+
+type fakeFieldRepo struct {
+	storage.CustomFieldRepository
+	fields []domain.CustomField
+}
+
+func (r *fakeFieldRepo) Create(field domain.CustomField) error {
+	r.fields = append(r.fields, field)
+	return nil
+}
+
+func (r *fakeFieldRepo) ListByBoard(boardID string) ([]domain.CustomField, error) {
+	var result []domain.CustomField
+	for _, field := range r.fields {
+		if field.BoardID == boardID {
+			result = append(result, field)
+		}
+	}
+	return result, nil
+}
+
+func newTestCustomFieldTaskService() TaskService {
+	members := &fakeMemberRepo{roles: map[string]domain.BoardRole{
+		"board-1|editor": domain.BoardRoleEditor,
+		"board-1|viewer": domain.BoardRoleViewer,
+	}}
+
+	fields := &fakeFieldRepo{fields: []domain.CustomField{
+		{ID: "priority", BoardID: "board-1", Name: "Priority", Type: domain.CustomFieldSelect, Options: []string{"low", "high"}},
+		{ID: "tags", BoardID: "board-1", Name: "Tags", Type: domain.CustomFieldMultiSelect, Options: []string{"ui", "api"}},
+		{ID: "points", BoardID: "board-1", Name: "Story points", Type: domain.CustomFieldNumber},
+		{ID: "release", BoardID: "board-1", Name: "Release", Type: domain.CustomFieldDate},
+		{ID: "reviewer", BoardID: "board-1", Name: "Reviewer", Type: domain.CustomFieldUser},
+		{ID: "customer", BoardID: "board-2", Name: "Customer", Type: domain.CustomFieldText},
+	}}
+
+	return NewTaskService(
+		&fakeTaskRepo{tasks: map[string][]domain.Task{
+			"col-1": {{ID: "task-1", ColumnID: "col-1", Title: "Dark mode"}},
+		}},
+		&fakeColumnRepo{columns: []domain.Column{{ID: "col-1", BoardID: "board-1"}}},
+		fields,
+		members,
+		NewBoardPolicy(members, newFakeRoleRepo(), newFakeWorkspaceMemberRepo()),
+		discardEvents{},
+		func() string { return "id" },
+	)
+}
+
+func TestTaskCustomFieldValues(t *testing.T) {
+	service := newTestCustomFieldTaskService()
+
+	task, err := service.Update("editor", "task-1", "Dark mode", "", TaskUpdate{CustomFields: domain.CustomFieldValues{
+		"priority": "high",
+		"tags":     []any{"ui", "api", "ui"},
+		"points":   float64(3),
+		"release":  "2026-05-15",
+		"reviewer": "viewer",
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if task.CustomFields["priority"] != "high" || task.CustomFields["points"] != float64(3) {
+		t.Errorf("unexpected values: %v", task.CustomFields)
+	}
+	if tags, _ := task.CustomFields["tags"].([]string); len(tags) != 2 {
+		t.Errorf("expected duplicate options to be dropped, got %v", task.CustomFields["tags"])
+	}
+
+	// без custom_fields значения остаются, пустая карта (null в запросе) очищает их
+	task, err = service.Update("editor", "task-1", "Dark mode", "", TaskUpdate{})
+	if err != nil || task.CustomFields["priority"] != "high" {
+		t.Errorf("expected values to be kept, got %v, %v", task.CustomFields, err)
+	}
+
+	task, err = service.Update("editor", "task-1", "Dark mode", "", TaskUpdate{CustomFields: domain.CustomFieldValues{}})
+	if err != nil || len(task.CustomFields) != 0 {
+		t.Errorf("expected values to be cleared, got %v, %v", task.CustomFields, err)
+	}
+}
+
+func TestTaskCustomFieldValuesRejected(t *testing.T) {
+	service := newTestCustomFieldTaskService()
+
+	cases := map[string]domain.CustomFieldValues{
+		"unknown field":        {"missing": "x"},
+		"field of other board": {"customer": "ACME"},
+		"option not in list":   {"priority": "urgent"},
+		"number as string":     {"points": "3"},
+		"bad date":             {"release": "15.05.2026"},
+		"multi not a list":     {"tags": "ui"},
+		"user not a member":    {"reviewer": "stranger"},
+	}
+
+	for name, values := range cases {
+		if _, err := service.Update("editor", "task-1", "Dark mode", "", TaskUpdate{CustomFields: values}); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("%s: expected ErrInvalidInput, got %v", name, err)
+		}
+	}
+}
+
+func TestCreateCustomField(t *testing.T) {
+	members := &fakeMemberRepo{roles: map[string]domain.BoardRole{
+		"board-1|owner":  domain.BoardRoleOwner,
+		"board-1|editor": domain.BoardRoleEditor,
+	}}
+	fields := &fakeFieldRepo{}
+
+	service := NewCustomFieldService(fields, NewBoardPolicy(members, newFakeRoleRepo(), newFakeWorkspaceMemberRepo()), discardEvents{}, func() string { return "field-1" })
+
+	field, err := service.Create("owner", "board-1", " Severity ", domain.CustomFieldSelect, []string{" S1", "S2 "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if field.Name != "Severity" || len(field.Options) != 2 || field.Options[0] != "S1" || field.Options[1] != "S2" {
+		t.Errorf("unexpected field: %+v", field)
+	}
+
+	if _, err := service.Create("editor", "board-1", "Points", domain.CustomFieldNumber, nil); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("expected ErrForbidden for editor, got %v", err)
+	}
+
+	invalid := []struct {
+		fieldType domain.CustomFieldType
+		options   []string
+	}{
+		{"checkbox", nil},
+		{domain.CustomFieldSelect, nil},
+		{domain.CustomFieldSelect, []string{"a", "a"}},
+		{domain.CustomFieldText, []string{"a"}},
+	}
+	for _, c := range invalid {
+		if _, err := service.Create("owner", "board-1", "Field", c.fieldType, c.options); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("%s %v: expected ErrInvalidInput, got %v", c.fieldType, c.options, err)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"hash/fnv"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/storage"
+)
+
+type EventPublisher interface {
+	Publish(event domain.BoardEvent)
+}
+
+type EventBus interface {
+	EventPublisher
+	Subscribe(boardID string) (<-chan domain.BoardEvent, func())
+}
+
+type EventService interface {
+	EventPublisher
+	Subscribe(userID, boardID string) (<-chan domain.BoardEvent, func(), error)
+	History(userID, boardID string, afterID int64) ([]domain.BoardEvent, error)
+}
+
+type eventService struct {
+	bus       EventBus
+	eventRepo storage.BoardEventRepository
+	boardRepo storage.BoardRepository
+	policy    BoardPolicy
+
+	// события одной доски публикуются по очереди, чтобы подписчики получали
+	// их в том же порядке, в котором они записаны в журнал; доски делят
+	// мьютексы по хешу id и друг друга почти не ждут
+	locks [publishLockShards]sync.Mutex
+}
+
+const publishLockShards = 64
+
+func NewEventService(
+	bus EventBus,
+	eventRepo storage.BoardEventRepository,
+	boardRepo storage.BoardRepository,
+	policy BoardPolicy,
+) EventService {
+	return &eventService{
+		bus:       bus,
+		eventRepo: eventRepo,
+		boardRepo: boardRepo,
+		policy:    policy,
+	}
+}
+
+func (s *eventService) Publish(event domain.BoardEvent) {
+	lock := s.boardLock(event.BoardID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	stored, err := s.eventRepo.Append(event)
+	if err != nil {
+		// изменение уже сохранено, поэтому онлайн-подписчики всё равно
+		// получают событие, но переподключившиеся клиенты его не увидят
+		log.Printf("append board event: %v", err)
+		s.bus.Publish(event)
+		return
+	}
+
+	s.bus.Publish(stored)
+}
+
+func (s *eventService) boardLock(boardID string) *sync.Mutex {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(boardID))
+	return &s.locks[hash.Sum32()%publishLockShards]
+}
+
+func (s *eventService) Subscribe(userID, boardID string) (<-chan domain.BoardEvent, func(), error) {
+	if err := s.requireMember(boardID, userID); err != nil {
+		return nil, nil, err
+	}
+
+	in, unsubscribe := s.bus.Subscribe(boardID)
+
+	out := make(chan domain.BoardEvent)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(out)
+		defer unsubscribe()
+
+		for {
+			select {
+			case <-done:
+				return
+			case event, ok := <-in:
+				if !ok {
+					return
+				}
+
+				select {
+				case out <- event:
+				case <-done:
+					return
+				}
+
+				if endsSubscription(event, userID) {
+					return
+				}
+
+				if changesAccess(event.Type) {
+					if _, err := s.policy.Authorize(boardID, userID, domain.ActionBoardRead); err != nil {
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	stop := func() {
+		once.Do(func() { close(done) })
+	}
+
+	return out, stop, nil
+}
+
+func (s *eventService) History(userID, boardID string, afterID int64) ([]domain.BoardEvent, error) {
+	if afterID < 0 {
+		return nil, domain.ErrInvalidInput
+	}
+
+	if err := s.requireMember(boardID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.eventRepo.ListAfter(boardID, afterID)
+}
+
+func (s *eventService) requireMember(boardID, userID string) error {
+	if boardID == "" {
+		return domain.ErrInvalidInput
+	}
+
+	if _, err := s.boardRepo.GetByID(boardID); err != nil {
+		return err
+	}
+
+	if _, err := s.policy.Authorize(boardID, userID, domain.ActionBoardRead); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// после удаления доски или исключения пользователя поток закрывается
+func endsSubscription(event domain.BoardEvent, userID string) bool {
+	switch event.Type {
+	case domain.EventBoardDeleted:
+		return true
+	case domain.EventMemberRemoved:
+		payload, ok := event.Payload.(map[string]string)
+		return ok && payload["user_id"] == userID
+	}
+
+	return false
+}
+
+// changesAccess — события, после которых подписчик мог потерять доступ
+// не напрямую: через команду, пространство, смену роли или перенос доски.
+// После них право на чтение проверяется заново.
+func changesAccess(eventType domain.EventType) bool {
+	switch eventType {
+	case domain.EventMemberRemoved,
+		domain.EventMemberRoleChanged,
+		domain.EventTeamRevoked,
+		domain.EventTeamMemberRemoved,
+		domain.EventWorkspaceMemberRemoved,
+		domain.EventBoardUpdated:
+		return true
+	}
+
+	return false
+}
+
+func newBoardEvent(
+	eventType domain.EventType,
+	boardID, actorID string,
+	payload any,
+) domain.BoardEvent {
+	return domain.BoardEvent{
+		Type:      eventType,
+		BoardID:   boardID,
+		ActorID:   actorID,
+		Payload:   payload,
+		CreatedAt: time.Now(),
+	}
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/infra/events"
+	"github.com/ovk741/TasksStream/internal/storage"
+)
+
+type recordingEvents struct {
+	events []domain.BoardEvent
+}
+
+func (r *recordingEvents) Publish(event domain.BoardEvent) {
+	r.events = append(r.events, event)
+}
+
+// switchPolicy разрешает чтение любой доски, пока allowed истинно
+type switchPolicy struct {
+	BoardPolicy
+	allowed atomic.Bool
+}
+
+func (p *switchPolicy) Authorize(boardID, userID string, action domain.Action) (domain.BoardRole, error) {
+	if !p.allowed.Load() {
+		return "", domain.ErrForbidden
+	}
+	return domain.BoardRoleViewer, nil
+}
+
+type discardEventLog struct {
+	storage.BoardEventRepository
+}
+
+func (discardEventLog) Append(event domain.BoardEvent) (domain.BoardEvent, error) {
+	return event, nil
+}
+
+// receive возвращает следующее событие потока; ok ложно, если поток закрыт
+func receive(t *testing.T, stream <-chan domain.BoardEvent) (domain.BoardEvent, bool) {
+	t.Helper()
+
+	select {
+	case event, ok := <-stream:
+		return event, ok
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the stream")
+		return domain.BoardEvent{}, false
+	}
+}
+
+func TestSubscriptionEndsWhenAccessIsLost(t *testing.T) {
+	policy := &switchPolicy{}
+	policy.allowed.Store(true)
+
+	service := NewEventService(events.NewBroker(), discardEventLog{}, fakeBoardRepo{}, policy)
+
+	stream, stop, err := service.Subscribe("vera", "board-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer stop()
+
+	policy.allowed.Store(false)
+
+	// обычные изменения доступ не перепроверяют
+	service.Publish(newBoardEvent(domain.EventTaskCreated, "board-1", "anna", nil))
+	if _, ok := receive(t, stream); !ok {
+		t.Fatal("stream must stay open after a regular event")
+	}
+
+	// доступ через команду пропал, хотя прямого исключения не было
+	service.Publish(newBoardEvent(domain.EventTeamMemberRemoved, "board-1", "anna", map[string]string{
+		"team_id": "team-1",
+		"user_id": "vera",
+	}))
+	if event, ok := receive(t, stream); !ok || event.Type != domain.EventTeamMemberRemoved {
+		t.Fatalf("expected the access change to be delivered, got %v, %v", event, ok)
+	}
+	if _, ok := receive(t, stream); ok {
+		t.Error("expected the stream to end after access was lost")
+	}
+}
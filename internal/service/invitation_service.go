@@ -0,0 +1,348 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/storage"
+)
+
+const (
+	invitationTokenPrefix = "tsi_"
+	invitationTTL         = 7 * 24 * time.Hour
+)
+
+type InvitationService interface {
+	// методы владельца доски
+	Invite(ownerID, boardID, email string, role domain.BoardRole) (domain.BoardInvitation, error)
+	ListForBoard(requesterID, boardID string) ([]domain.BoardInvitation, error)
+	Revoke(ownerID, invitationID string) error
+
+	// методы приглашённого
+	ListMine(userID string) ([]domain.BoardInvitation, error)
+	Accept(userID, invitationID string) (domain.BoardInvitation, error)
+	AcceptByToken(userID, token string) (domain.BoardInvitation, error)
+	Decline(userID, invitationID string) error
+}
+
+// InvitationClaimer превращает ожидающие приглашения адреса в участие
+// в досках, как только владелец адреса его подтвердил.
+type InvitationClaimer interface {
+	ClaimPending(user domain.User) error
+}
+
+type invitationService struct {
+	invitationRepo  storage.BoardInvitationRepository
+	boardRepo       storage.BoardRepository
+	boardMemberRepo storage.BoardMemberRepository
+	policy          BoardPolicy
+	userRepo        storage.UserRepository
+	mailer          Mailer
+	events          EventPublisher
+	baseURL         string
+	generateID      func() string
+}
+
+func NewInvitationService(
+	invitationRepo storage.BoardInvitationRepository,
+	boardRepo storage.BoardRepository,
+	boardMemberRepo storage.BoardMemberRepository,
+	policy BoardPolicy,
+	userRepo storage.UserRepository,
+	mailer Mailer,
+	events EventPublisher,
+	baseURL string,
+	generateID func() string,
+) *invitationService {
+	return &invitationService{
+		invitationRepo:  invitationRepo,
+		boardRepo:       boardRepo,
+		boardMemberRepo: boardMemberRepo,
+		policy:          policy,
+		userRepo:        userRepo,
+		mailer:          mailer,
+		events:          events,
+		baseURL:         baseURL,
+		generateID:      generateID,
+	}
+}
+
+func (s *invitationService) Invite(ownerID, boardID, email string, role domain.BoardRole) (domain.BoardInvitation, error) {
+	email = strings.TrimSpace(email)
+
+	if ownerID == "" || boardID == "" || !validEmail(email) {
+		return domain.BoardInvitation{}, domain.ErrInvalidInput
+	}
+
+	board, err := s.boardRepo.GetByID(boardID)
+	if err != nil {
+		return domain.BoardInvitation{}, err
+	}
+
+	if _, err := s.policy.Authorize(boardID, ownerID, domain.ActionMemberManage); err != nil {
+		return domain.BoardInvitation{}, err
+	}
+
+	if err := s.policy.AssignableRole(boardID, role); err != nil {
+		return domain.BoardInvitation{}, err
+	}
+
+	invitee, err := s.userRepo.GetByEmail(email)
+	if err == nil {
+		if isMember, err := s.boardMemberRepo.IsMember(boardID, invitee.ID); err != nil {
+			return domain.BoardInvitation{}, err
+		} else if isMember {
+			return domain.BoardInvitation{}, domain.ErrUserAlreadyExists
+		}
+	} else if !errors.Is(err, domain.ErrNotFound) {
+		return domain.BoardInvitation{}, err
+	}
+
+	token, err := generateSecret(invitationTokenPrefix)
+	if err != nil {
+		return domain.BoardInvitation{}, err
+	}
+
+	now := time.Now()
+
+	// повторное приглашение заменяет предыдущее: действует ссылка из последнего письма
+	if err := s.invitationRepo.RevokePending(boardID, email, now); err != nil {
+		return domain.BoardInvitation{}, err
+	}
+
+	invitation := domain.BoardInvitation{
+		ID:        s.generateID(),
+		BoardID:   boardID,
+		BoardName: board.Name,
+		Email:     email,
+		Role:      role,
+		InvitedBy: ownerID,
+		TokenHash: hashToken(token),
+		Status:    domain.InvitationPending,
+		CreatedAt: now,
+		ExpiresAt: now.Add(invitationTTL),
+	}
+
+	if err := s.invitationRepo.Create(invitation); err != nil {
+		return domain.BoardInvitation{}, err
+	}
+
+	// приглашение уже сохранено и видно в GET /invitations,
+	// поэтому ошибка почты не отменяет его
+	if err := s.mailer.Send(Email{
+		To:      email,
+		Subject: fmt.Sprintf("Приглашение в доску «%s»", board.Name),
+		Body: fmt.Sprintf(
+			"Вас пригласили в доску «%s» TasksStream с ролью %s.\n\n"+
+				"Принять приглашение:\n%s\n\n"+
+				"Если у вас ещё нет аккаунта, зарегистрируйтесь с этим адресом и подтвердите его — "+
+				"доступ к доске появится автоматически. Приглашение действует %d дней.\n",
+			board.Name,
+			role,
+			s.baseURL+"/invitations/accept?token="+url.QueryEscape(token),
+			int(invitationTTL.Hours()/24),
+		),
+	}); err != nil {
+		log.Printf("send invitation %s: %v", invitation.ID, err)
+	}
+
+	return invitation, nil
+}
+
+func (s *invitationService) ListForBoard(requesterID, boardID string) ([]domain.BoardInvitation, error) {
+	if requesterID == "" || boardID == "" {
+		return nil, domain.ErrInvalidInput
+	}
+
+	if _, err := s.policy.Authorize(boardID, requesterID, domain.ActionMemberManage); err != nil {
+		return nil, err
+	}
+
+	return s.invitationRepo.ListPendingByBoard(boardID, time.Now())
+}
+
+func (s *invitationService) Revoke(ownerID, invitationID string) error {
+	if ownerID == "" || invitationID == "" {
+		return domain.ErrInvalidInput
+	}
+
+	invitation, err := s.invitationRepo.GetByID(invitationID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.policy.Authorize(invitation.BoardID, ownerID, domain.ActionMemberManage); err != nil {
+		return err
+	}
+
+	return s.invitationRepo.SetStatus(invitation.ID, domain.InvitationRevoked, time.Now())
+}
+
+// ListMine и Accept по id доступны только с подтверждённым адресом: иначе
+// кто угодно мог бы зарегистрироваться на чужой email и забрать приглашения
+func (s *invitationService) ListMine(userID string) ([]domain.BoardInvitation, error) {
+	user, err := s.verifiedUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations, err := s.invitationRepo.ListPendingByEmail(user.Email, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range invitations {
+		if board, err := s.boardRepo.GetByID(invitations[i].BoardID); err == nil {
+			invitations[i].BoardName = board.Name
+		}
+	}
+
+	return invitations, nil
+}
+
+func (s *invitationService) Accept(userID, invitationID string) (domain.BoardInvitation, error) {
+	if invitationID == "" {
+		return domain.BoardInvitation{}, domain.ErrInvalidInput
+	}
+
+	user, err := s.verifiedUser(userID)
+	if err != nil {
+		return domain.BoardInvitation{}, err
+	}
+
+	invitation, err := s.addresseeInvitation(user, invitationID)
+	if err != nil {
+		return domain.BoardInvitation{}, err
+	}
+
+	return s.accept(user.ID, invitation)
+}
+
+// AcceptByToken не сверяет адрес: токен из письма сам доказывает доступ
+// к ящику, на который было отправлено приглашение
+func (s *invitationService) AcceptByToken(userID, token string) (domain.BoardInvitation, error) {
+	if userID == "" || token == "" {
+		return domain.BoardInvitation{}, domain.ErrInvalidInput
+	}
+
+	invitation, err := s.invitationRepo.GetByTokenHash(hashToken(token))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.BoardInvitation{}, domain.ErrInvalidCredentials
+		}
+		return domain.BoardInvitation{}, err
+	}
+
+	return s.accept(userID, invitation)
+}
+
+func (s *invitationService) Decline(userID, invitationID string) error {
+	if invitationID == "" {
+		return domain.ErrInvalidInput
+	}
+
+	user, err := s.verifiedUser(userID)
+	if err != nil {
+		return err
+	}
+
+	invitation, err := s.addresseeInvitation(user, invitationID)
+	if err != nil {
+		return err
+	}
+
+	return s.invitationRepo.SetStatus(invitation.ID, domain.InvitationDeclined, time.Now())
+}
+
+func (s *invitationService) ClaimPending(user domain.User) error {
+	if !user.EmailVerified {
+		return nil
+	}
+
+	invitations, err := s.invitationRepo.ListPendingByEmail(user.Email, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, invitation := range invitations {
+		if _, err := s.accept(user.ID, invitation); err != nil && !errors.Is(err, domain.ErrConflict) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *invitationService) accept(userID string, invitation domain.BoardInvitation) (domain.BoardInvitation, error) {
+	now := time.Now()
+
+	if invitation.Status != domain.InvitationPending {
+		return domain.BoardInvitation{}, domain.ErrConflict
+	}
+	if !invitation.ExpiresAt.After(now) {
+		return domain.BoardInvitation{}, domain.ErrNotFound
+	}
+
+	// статус и участник меняются в одной транзакции: из двух параллельных
+	// принятий пройдёт одно, а сбой вставки не оставит принятое приглашение без участия
+	added, err := s.invitationRepo.Accept(invitation.ID, domain.BoardMember{
+		ID:        s.generateID(),
+		BoardID:   invitation.BoardID,
+		UserID:    userID,
+		Role:      invitation.Role,
+		CreatedAt: now,
+	}, now)
+	if err != nil {
+		return domain.BoardInvitation{}, err
+	}
+
+	invitation.Status = domain.InvitationAccepted
+	invitation.RespondedAt = &now
+
+	if !added {
+		return invitation, nil
+	}
+
+	s.events.Publish(newBoardEvent(domain.EventMemberAdded, invitation.BoardID, userID, map[string]string{
+		"user_id": userID,
+		"role":    string(invitation.Role),
+	}))
+
+	return invitation, nil
+}
+
+// чужие приглашения не раскрываем
+func (s *invitationService) addresseeInvitation(user domain.User, invitationID string) (domain.BoardInvitation, error) {
+	invitation, err := s.invitationRepo.GetByID(invitationID)
+	if err != nil {
+		return domain.BoardInvitation{}, err
+	}
+
+	if !strings.EqualFold(invitation.Email, user.Email) {
+		return domain.BoardInvitation{}, domain.ErrNotFound
+	}
+
+	return invitation, nil
+}
+
+func (s *invitationService) verifiedUser(userID string) (domain.User, error) {
+	if userID == "" {
+		return domain.User{}, domain.ErrInvalidInput
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	if !user.EmailVerified {
+		return domain.User{}, domain.ErrEmailNotVerified
+	}
+
+	return user, nil
+}
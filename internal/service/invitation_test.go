@@ -0,0 +1,241 @@
+package service
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/storage"
+)
+
+// fakeInvitationRepo добавляет участника при Accept в тот же fakeMemberRepo,
+// что и сервис, как это делает транзакция в postgres
+type fakeInvitationRepo struct {
+	storage.BoardInvitationRepository
+	items   map[string]domain.BoardInvitation
+	members *fakeMemberRepo
+}
+
+func (r *fakeInvitationRepo) Create(invitation domain.BoardInvitation) error {
+	r.items[invitation.ID] = invitation
+	return nil
+}
+
+func (r *fakeInvitationRepo) GetByID(id string) (domain.BoardInvitation, error) {
+	invitation, ok := r.items[id]
+	if !ok {
+		return domain.BoardInvitation{}, domain.ErrNotFound
+	}
+	return invitation, nil
+}
+
+func (r *fakeInvitationRepo) ListPendingByEmail(email string, now time.Time) ([]domain.BoardInvitation, error) {
+	var result []domain.BoardInvitation
+	for _, invitation := range r.items {
+		if strings.EqualFold(invitation.Email, email) && invitation.Status == domain.InvitationPending {
+			result = append(result, invitation)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeInvitationRepo) SetStatus(id string, status domain.InvitationStatus, at time.Time) error {
+	invitation, ok := r.items[id]
+	if !ok || invitation.Status != domain.InvitationPending {
+		return domain.ErrConflict
+	}
+	invitation.Status = status
+	r.items[id] = invitation
+	return nil
+}
+
+func (r *fakeInvitationRepo) Accept(id string, member domain.BoardMember, at time.Time) (bool, error) {
+	if err := r.SetStatus(id, domain.InvitationAccepted, at); err != nil {
+		return false, err
+	}
+	if isMember, _ := r.members.IsMember(member.BoardID, member.UserID); isMember {
+		return false, nil
+	}
+	return true, r.members.Add(member)
+}
+
+func (r *fakeInvitationRepo) RevokePending(boardID, email string, at time.Time) error {
+	for id, invitation := range r.items {
+		if invitation.BoardID == boardID && strings.EqualFold(invitation.Email, email) && invitation.Status == domain.InvitationPending {
+			invitation.Status = domain.InvitationRevoked
+			r.items[id] = invitation
+		}
+	}
+	return nil
+}
+
+type fakeBoardRepo struct {
+	storage.BoardRepository
+}
+
+func (fakeBoardRepo) GetByID(boardID string) (domain.Board, error) {
+	return domain.Board{ID: boardID, Name: "Roadmap"}, nil
+}
+
+type fakeMemberRepo struct {
+	storage.BoardMemberRepository
+	roles map[string]domain.BoardRole
+}
+
+func (r *fakeMemberRepo) GetRole(boardID, userID string) (domain.BoardRole, error) {
+	role, ok := r.roles[boardID+"|"+userID]
+	if !ok {
+		return "", domain.ErrNotFound
+	}
+	return role, nil
+}
+
+func (r *fakeMemberRepo) IsMember(boardID, userID string) (bool, error) {
+	_, ok := r.roles[boardID+"|"+userID]
+	return ok, nil
+}
+
+func (r *fakeMemberRepo) Add(member domain.BoardMember) error {
+	r.roles[member.BoardID+"|"+member.UserID] = member.Role
+	return nil
+}
+
+type discardMailer struct{}
+
+func (discardMailer) Send(Email) error { return nil }
+
+type discardEvents struct{}
+
+func (discardEvents) Publish(domain.BoardEvent) {}
+
+func newTestInvitationService() (*invitationService, *fakeInvitationRepo, *fakeUserRepo) {
+	members := &fakeMemberRepo{roles: map[string]domain.BoardRole{"board-1|owner": domain.BoardRoleOwner}}
+	users := &fakeUserRepo{users: map[string]domain.User{
+		"owner": {ID: "owner", Email: "owner@example.com", EmailVerified: true},
+	}}
+
+	invitations := &fakeInvitationRepo{items: make(map[string]domain.BoardInvitation), members: members}
+
+	n := 0
+	generateID := func() string {
+		n++
+		return "id-" + strconv.Itoa(n)
+	}
+
+	service := NewInvitationService(
+		invitations,
+		fakeBoardRepo{},
+		members,
+		NewBoardPolicy(members, newFakeRoleRepo(), newFakeWorkspaceMemberRepo()),
+		users,
+		discardMailer{},
+		discardEvents{},
+		"http://localhost",
+		generateID,
+	)
+
+	return service, invitations, users
+}
+
+func TestInvitationClaimedAfterRegistration(t *testing.T) {
+	service, invitations, users := newTestInvitationService()
+	members := invitations.members
+
+	if _, err := service.Invite("owner", "board-1", "New@Example.com", domain.BoardRoleEditor); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// до подтверждения адреса приглашение не срабатывает
+	newcomer := domain.User{ID: "newcomer", Email: "new@example.com"}
+	users.users[newcomer.ID] = newcomer
+
+	if err := service.ClaimPending(newcomer); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ok, _ := members.IsMember("board-1", "newcomer"); ok {
+		t.Fatal("unverified user must not join the board")
+	}
+
+	newcomer.EmailVerified = true
+	if err := service.ClaimPending(newcomer); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	role, err := members.GetRole("board-1", "newcomer")
+	if err != nil {
+		t.Fatalf("expected membership, got %v", err)
+	}
+	if role != domain.BoardRoleEditor {
+		t.Errorf("expected editor role, got %s", role)
+	}
+}
+
+func TestInvitationCannotBeAcceptedByAnotherUser(t *testing.T) {
+	service, _, users := newTestInvitationService()
+	users.users["stranger"] = domain.User{ID: "stranger", Email: "stranger@example.com", EmailVerified: true}
+
+	invitation, err := service.Invite("owner", "board-1", "friend@example.com", domain.BoardRoleViewer)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := service.Accept("stranger", invitation.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestInviteRequiresOwner(t *testing.T) {
+	service, invitations, _ := newTestInvitationService()
+	invitations.members.roles["board-1|editor"] = domain.BoardRoleEditor
+
+	_, err := service.Invite("editor", "board-1", "friend@example.com", domain.BoardRoleViewer)
+	if !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden, got %v", err)
+	}
+}
+
+func TestAcceptInvitationAddsMemberOnce(t *testing.T) {
+	service, invitations, users := newTestInvitationService()
+	users.users["friend"] = domain.User{ID: "friend", Email: "friend@example.com", EmailVerified: true}
+	users.users["member"] = domain.User{ID: "member", Email: "member@example.com", EmailVerified: true}
+	invitations.members.roles["board-1|member"] = domain.BoardRoleViewer
+
+	invitation, err := service.Invite("owner", "board-1", "friend@example.com", domain.BoardRoleEditor)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	accepted, err := service.Accept("friend", invitation.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if accepted.Status != domain.InvitationAccepted || invitations.items[invitation.ID].Status != domain.InvitationAccepted {
+		t.Errorf("expected accepted invitation, got %s", invitations.items[invitation.ID].Status)
+	}
+	if role, _ := invitations.members.GetRole("board-1", "friend"); role != domain.BoardRoleEditor {
+		t.Errorf("expected editor membership, got %q", role)
+	}
+
+	if _, err := service.Accept("friend", invitation.ID); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("expected ErrConflict for repeated accept, got %v", err)
+	}
+
+	// приглашение уже участника принимается, но роль не меняет
+	invitations.items["pending"] = domain.BoardInvitation{
+		ID:        "pending",
+		BoardID:   "board-1",
+		Email:     "member@example.com",
+		Role:      domain.BoardRoleEditor,
+		Status:    domain.InvitationPending,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	if _, err := service.Accept("member", "pending"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if role, _ := invitations.members.GetRole("board-1", "member"); role != domain.BoardRoleViewer {
+		t.Errorf("expected existing viewer role to stay, got %q", role)
+	}
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/storage"
+)
+
+// fakeInviteLinkRepo повторяет контракт Redeem: проверка лимита и
+// добавление участника происходят под одной блокировкой
+type fakeInviteLinkRepo struct {
+	storage.BoardInviteLinkRepository
+
+	mu      sync.Mutex
+	links   map[string]domain.BoardInviteLink
+	members map[string]bool
+}
+
+func (r *fakeInviteLinkRepo) Create(link domain.BoardInviteLink) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.links[link.ID] = link
+	return nil
+}
+
+func (r *fakeInviteLinkRepo) GetByTokenHash(tokenHash string) (domain.BoardInviteLink, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, link := range r.links {
+		if link.TokenHash == tokenHash {
+			return link, nil
+		}
+	}
+	return domain.BoardInviteLink{}, domain.ErrNotFound
+}
+
+func (r *fakeInviteLinkRepo) Redeem(linkID string, member domain.BoardMember, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	link := r.links[linkID]
+	if !link.Active(now) {
+		return domain.ErrConflict
+	}
+	if r.members[member.BoardID+"|"+member.UserID] {
+		return domain.ErrUserAlreadyExists
+	}
+
+	link.Uses++
+	r.links[linkID] = link
+	r.members[member.BoardID+"|"+member.UserID] = true
+
+	return nil
+}
+
+func newTestInviteLinkService() (BoardService, *fakeInviteLinkRepo) {
+	links := &fakeInviteLinkRepo{
+		links:   make(map[string]domain.BoardInviteLink),
+		members: make(map[string]bool),
+	}
+	members := &fakeMemberRepo{roles: map[string]domain.BoardRole{"board-1|owner": domain.BoardRoleOwner}}
+
+	var (
+		mu sync.Mutex
+		n  int
+	)
+	generateID := func() string {
+		mu.Lock()
+		defer mu.Unlock()
+		n++
+		return "id-" + strconv.Itoa(n)
+	}
+
+	service := NewBoardService(fakeBoardRepo{}, nil, nil, members, &fakeAssigneeRepo{}, NewBoardPolicy(members, newFakeRoleRepo(), newFakeWorkspaceMemberRepo()), links, discardEvents{}, generateID)
+
+	return service, links
+}
+
+func TestJoinByInviteLinkRespectsMaxUses(t *testing.T) {
+	service, links := newTestInviteLinkService()
+
+	maxUses := 3
+	link, token, err := service.CreateInviteLink("owner", "board-1", domain.BoardRoleViewer, &maxUses, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		joined int
+	)
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(userID string) {
+			defer wg.Done()
+
+			_, err := service.JoinByInviteLink(userID, token)
+			if err == nil {
+				mu.Lock()
+				joined++
+				mu.Unlock()
+				return
+			}
+			if !errors.Is(err, domain.ErrConflict) {
+				t.Errorf("expected ErrConflict, got %v", err)
+			}
+		}("user-" + strconv.Itoa(i))
+	}
+
+	wg.Wait()
+
+	if joined != maxUses {
+		t.Errorf("expected %d users to join, got %d", maxUses, joined)
+	}
+	if uses := links.links[link.ID].Uses; uses != maxUses {
+		t.Errorf("expected %d uses, got %d", maxUses, uses)
+	}
+}
+
+func TestJoinByInviteLinkTwiceDoesNotConsumeUse(t *testing.T) {
+	service, links := newTestInviteLinkService()
+
+	link, token, err := service.CreateInviteLink("owner", "board-1", domain.BoardRoleEditor, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		board, err := service.JoinByInviteLink("user-1", token)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if board.ID != "board-1" {
+			t.Errorf("expected board-1, got %s", board.ID)
+		}
+	}
+
+	if uses := links.links[link.ID].Uses; uses != 1 {
+		t.Errorf("expected 1 use, got %d", uses)
+	}
+}
+
+func TestCreateInviteLinkRequiresOwner(t *testing.T) {
+	service, _ := newTestInviteLinkService()
+
+	_, _, err := service.CreateInviteLink("stranger", "board-1", domain.BoardRoleViewer, nil, nil)
+	if !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden, got %v", err)
+	}
+}
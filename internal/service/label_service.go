@@ -0,0 +1,229 @@
+package service
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/storage"
+)
+
+var labelColorPattern = regexp.MustCompile(`^#[0-9a-f]{6}$`)
+
+// maxLabelNameLength ограничивает имя метки в символах
+const maxLabelNameLength = 50
+
+// LabelService управляет метками доски и их привязкой к задачам.
+// Задача при перемещении остаётся на той же доске, поэтому метки
+// переходят вместе с ней без изменений.
+type LabelService interface {
+	List(userID, boardID string) ([]domain.Label, error)
+	Create(userID, boardID, name, color string) (domain.Label, error)
+	Update(userID, labelID, name, color string) (domain.Label, error)
+	Delete(userID, labelID string) error
+
+	Attach(userID, taskID, labelID string) error
+	Detach(userID, taskID, labelID string) error
+}
+
+type labelService struct {
+	labelRepo  storage.LabelRepository
+	taskRepo   storage.TaskRepository
+	columnRepo storage.ColumnRepository
+	policy     BoardPolicy
+	events     EventPublisher
+	generateID func() string
+}
+
+func NewLabelService(
+	labelRepo storage.LabelRepository,
+	taskRepo storage.TaskRepository,
+	columnRepo storage.ColumnRepository,
+	policy BoardPolicy,
+	events EventPublisher,
+	generateID func() string,
+) LabelService {
+	return &labelService{
+		labelRepo:  labelRepo,
+		taskRepo:   taskRepo,
+		columnRepo: columnRepo,
+		policy:     policy,
+		events:     events,
+		generateID: generateID,
+	}
+}
+
+func (s *labelService) List(userID, boardID string) ([]domain.Label, error) {
+	if boardID == "" {
+		return nil, domain.ErrInvalidInput
+	}
+
+	if _, err := s.policy.Authorize(boardID, userID, domain.ActionBoardRead); err != nil {
+		return nil, err
+	}
+
+	return s.labelRepo.ListByBoard(boardID)
+}
+
+func (s *labelService) Create(userID, boardID, name, color string) (domain.Label, error) {
+	name, color, err := normalizeLabel(name, color)
+	if err != nil || boardID == "" {
+		return domain.Label{}, domain.ErrInvalidInput
+	}
+
+	if _, err := s.policy.Authorize(boardID, userID, domain.ActionLabelManage); err != nil {
+		return domain.Label{}, err
+	}
+
+	label := domain.Label{
+		ID:        s.generateID(),
+		BoardID:   boardID,
+		Name:      name,
+		Color:     color,
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.labelRepo.Create(label); err != nil {
+		return domain.Label{}, err
+	}
+
+	s.events.Publish(newBoardEvent(domain.EventLabelCreated, boardID, userID, label))
+
+	return label, nil
+}
+
+func (s *labelService) Update(userID, labelID, name, color string) (domain.Label, error) {
+	name, color, err := normalizeLabel(name, color)
+	if err != nil || labelID == "" {
+		return domain.Label{}, domain.ErrInvalidInput
+	}
+
+	label, err := s.labelRepo.GetByID(labelID)
+	if err != nil {
+		return domain.Label{}, err
+	}
+
+	if _, err := s.policy.Authorize(label.BoardID, userID, domain.ActionLabelManage); err != nil {
+		return domain.Label{}, err
+	}
+
+	label.Name = name
+	label.Color = color
+
+	if err := s.labelRepo.Update(label); err != nil {
+		return domain.Label{}, err
+	}
+
+	s.events.Publish(newBoardEvent(domain.EventLabelUpdated, label.BoardID, userID, label))
+
+	return label, nil
+}
+
+func (s *labelService) Delete(userID, labelID string) error {
+	if labelID == "" {
+		return domain.ErrInvalidInput
+	}
+
+	label, err := s.labelRepo.GetByID(labelID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.policy.Authorize(label.BoardID, userID, domain.ActionLabelManage); err != nil {
+		return err
+	}
+
+	if err := s.labelRepo.Delete(labelID); err != nil {
+		return err
+	}
+
+	s.events.Publish(newBoardEvent(domain.EventLabelDeleted, label.BoardID, userID, map[string]string{
+		"id": labelID,
+	}))
+
+	return nil
+}
+
+func (s *labelService) Attach(userID, taskID, labelID string) error {
+	boardID, err := s.authorizeTaskLabel(userID, taskID, labelID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.labelRepo.Attach(taskID, labelID); err != nil {
+		return err
+	}
+
+	s.events.Publish(newBoardEvent(domain.EventTaskLabeled, boardID, userID, map[string]string{
+		"task_id":  taskID,
+		"label_id": labelID,
+	}))
+
+	return nil
+}
+
+func (s *labelService) Detach(userID, taskID, labelID string) error {
+	boardID, err := s.authorizeTaskLabel(userID, taskID, labelID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.labelRepo.Detach(taskID, labelID); err != nil {
+		return err
+	}
+
+	s.events.Publish(newBoardEvent(domain.EventTaskUnlabeled, boardID, userID, map[string]string{
+		"task_id":  taskID,
+		"label_id": labelID,
+	}))
+
+	return nil
+}
+
+// authorizeTaskLabel проверяет право на изменение задачи и то, что метка
+// принадлежит доске задачи; возвращает ID доски
+func (s *labelService) authorizeTaskLabel(userID, taskID, labelID string) (string, error) {
+	if taskID == "" || labelID == "" {
+		return "", domain.ErrInvalidInput
+	}
+
+	task, err := s.taskRepo.GetByID(taskID)
+	if err != nil {
+		return "", err
+	}
+
+	column, err := s.columnRepo.GetByID(task.ColumnID)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := s.policy.Authorize(column.BoardID, userID, domain.ActionTaskWrite); err != nil {
+		return "", err
+	}
+
+	label, err := s.labelRepo.GetByID(labelID)
+	if err != nil {
+		return "", err
+	}
+
+	// метки другой доски к задаче не прикрепляются
+	if label.BoardID != column.BoardID {
+		return "", domain.ErrInvalidInput
+	}
+
+	return column.BoardID, nil
+}
+
+// normalizeLabel обрезает пробелы в имени и приводит цвет #RRGGBB
+// к нижнему регистру
+func normalizeLabel(name, color string) (string, string, error) {
+	name = strings.TrimSpace(name)
+	color = strings.ToLower(strings.TrimSpace(color))
+
+	if name == "" || len([]rune(name)) > maxLabelNameLength || !labelColorPattern.MatchString(color) {
+		return "", "", domain.ErrInvalidInput
+	}
+
+	return name, color, nil
+}
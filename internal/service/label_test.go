@@ -0,0 +1,154 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/storage"
+)
+
+type fakeLabelRepo struct {
+	storage.LabelRepository
+	labels map[string]domain.Label
+	// ключ "задача|метка"
+	attached map[string]bool
+}
+
+func (r *fakeLabelRepo) Create(label domain.Label) error {
+	for _, existing := range r.labels {
+		if existing.BoardID == label.BoardID && existing.Name == label.Name {
+			return domain.ErrConflict
+		}
+	}
+	r.labels[label.ID] = label
+	return nil
+}
+
+func (r *fakeLabelRepo) GetByID(id string) (domain.Label, error) {
+	label, ok := r.labels[id]
+	if !ok {
+		return domain.Label{}, domain.ErrNotFound
+	}
+	return label, nil
+}
+
+func (r *fakeLabelRepo) Attach(taskID, labelID string) error {
+	r.attached[taskID+"|"+labelID] = true
+	return nil
+}
+
+func newTestLabelService() (LabelService, *fakeLabelRepo) {
+	members := &fakeMemberRepo{roles: map[string]domain.BoardRole{
+		"board-1|owner":  domain.BoardRoleOwner,
+		"board-1|viewer": domain.BoardRoleViewer,
+	}}
+	labels := &fakeLabelRepo{
+		labels: map[string]domain.Label{
+			"bug":     {ID: "bug", BoardID: "board-1", Name: "bug", Color: "#ff0000"},
+			"foreign": {ID: "foreign", BoardID: "board-2", Name: "bug", Color: "#ff0000"},
+		},
+		attached: map[string]bool{},
+	}
+
+	service := NewLabelService(
+		labels,
+		&fakeTaskRepo{tasks: map[string][]domain.Task{
+			"col-1": {{ID: "task-1", ColumnID: "col-1", Title: "Dark mode"}},
+		}},
+		&fakeColumnRepo{columns: []domain.Column{{ID: "col-1", BoardID: "board-1"}}},
+		NewBoardPolicy(members, newFakeRoleRepo(), newFakeWorkspaceMemberRepo()),
+		discardEvents{},
+		func() string { return "label-id" },
+	)
+
+	return service, labels
+}
+
+func TestCreateLabel(t *testing.T) {
+	service, _ := newTestLabelService()
+
+	label, err := service.Create("owner", "board-1", "  feature ", "#00AA00")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if label.Name != "feature" || label.Color != "#00aa00" {
+		t.Errorf("unexpected label: %+v", label)
+	}
+
+	for _, color := range []string{"", "green", "#0a0", "#00aa00ff"} {
+		if _, err := service.Create("owner", "board-1", "other", color); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("color %q: expected ErrInvalidInput, got %v", color, err)
+		}
+	}
+
+	if _, err := service.Create("owner", "board-1", "bug", "#00aa00"); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("expected ErrConflict for duplicate name, got %v", err)
+	}
+}
+
+func TestCreateLabelRequiresLabelManage(t *testing.T) {
+	service, _ := newTestLabelService()
+
+	if _, err := service.Create("viewer", "board-1", "feature", "#00aa00"); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("expected ErrForbidden, got %v", err)
+	}
+}
+
+func TestAttachLabel(t *testing.T) {
+	service, labels := newTestLabelService()
+
+	if err := service.Attach("owner", "task-1", "bug"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !labels.attached["task-1|bug"] {
+		t.Error("expected label to be attached")
+	}
+}
+
+func TestAttachLabelFromAnotherBoard(t *testing.T) {
+	service, labels := newTestLabelService()
+
+	if err := service.Attach("owner", "task-1", "foreign"); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput, got %v", err)
+	}
+	if labels.attached["task-1|foreign"] {
+		t.Error("label from another board must not be attached")
+	}
+}
+
+func TestGetTasksByLabel(t *testing.T) {
+	members := &fakeMemberRepo{roles: map[string]domain.BoardRole{"board-1|viewer": domain.BoardRoleViewer}}
+
+	service := NewTaskService(
+		&fakeTaskRepo{tasks: map[string][]domain.Task{
+			"col-1": {
+				{ID: "t1", ColumnID: "col-1", LabelIDs: []string{"bug"}},
+				{ID: "t2", ColumnID: "col-1"},
+				{ID: "t3", ColumnID: "col-1", LabelIDs: []string{"ui", "bug"}},
+			},
+		}},
+		&fakeColumnRepo{columns: []domain.Column{{ID: "col-1", BoardID: "board-1"}}},
+		&fakeFieldRepo{},
+		members,
+		NewBoardPolicy(members, newFakeRoleRepo(), newFakeWorkspaceMemberRepo()),
+		discardEvents{},
+		func() string { return "id" },
+	)
+
+	tasks, err := service.GetByColumnID("viewer", "col-1", "bug")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tasks) != 2 || tasks[0].ID != "t1" || tasks[1].ID != "t3" {
+		t.Errorf("unexpected tasks: %+v", tasks)
+	}
+
+	all, err := service.GetByColumnID("viewer", "col-1", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(all) != 3 {
+		t.Errorf("expected all 3 tasks without filter, got %d", len(all))
+	}
+}
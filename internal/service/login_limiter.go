@@ -0,0 +1,146 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/storage"
+)
+
+// Политика блокировки: после нескольких бесплатных попыток каждая следующая
+// неудача удваивает время блокировки. С одного IP допускается больше ошибок,
+// чем на один аккаунт, чтобы не блокировать офис за общим NAT.
+const (
+	loginAccountFreeAttempts = 5
+	loginIPFreeAttempts      = 20
+	loginBaseLockout         = 30 * time.Second
+	loginMaxLockout          = time.Hour
+	// счётчик сбрасывается, если неудач не было дольше этого окна
+	loginFailureWindow = time.Hour
+)
+
+func loginLockoutPolicy(freeAttempts int) domain.LockoutPolicy {
+	return domain.LockoutPolicy{
+		FreeAttempts: freeAttempts,
+		Window:       loginFailureWindow,
+		BaseLockout:  loginBaseLockout,
+		MaxLockout:   loginMaxLockout,
+	}
+}
+
+type LoginLimiter interface {
+	// Check возвращает *domain.RateLimitError, если вход для адреса или IP заблокирован
+	Check(email, ip string) error
+	// Failure учитывает неудачу и возвращает *domain.RateLimitError,
+	// если после неё вход заблокирован
+	Failure(email, ip string) error
+	Success(email, ip string) error
+}
+
+type loginLimiter struct {
+	repo       storage.LoginAttemptRepository
+	generateID func() string
+	now        func() time.Time
+}
+
+func NewLoginLimiter(repo storage.LoginAttemptRepository, generateID func() string) LoginLimiter {
+	return &loginLimiter{
+		repo:       repo,
+		generateID: generateID,
+		now:        time.Now,
+	}
+}
+
+func (l *loginLimiter) Check(email, ip string) error {
+	now := l.now()
+
+	var retryAfter time.Duration
+
+	for _, key := range loginLimiterKeys(email, ip) {
+		attempt, err := l.repo.Get(key.key)
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				continue
+			}
+			return err
+		}
+
+		if attempt.LockedUntil != nil && attempt.LockedUntil.After(now) {
+			if wait := attempt.LockedUntil.Sub(now); wait > retryAfter {
+				retryAfter = wait
+			}
+		}
+	}
+
+	if retryAfter > 0 {
+		return &domain.RateLimitError{RetryAfter: retryAfter}
+	}
+
+	return nil
+}
+
+func (l *loginLimiter) Failure(email, ip string) error {
+	now := l.now()
+
+	var retryAfter time.Duration
+
+	for _, key := range loginLimiterKeys(email, ip) {
+		attempt, err := l.repo.RecordFailure(key.key, now, key.policy)
+		if err != nil {
+			return err
+		}
+
+		if attempt.LockedUntil == nil || !attempt.LockedUntil.After(now) {
+			continue
+		}
+
+		if wait := attempt.LockedUntil.Sub(now); wait > retryAfter {
+			retryAfter = wait
+		}
+
+		log.Printf("login locked for %s until %s after %d failures", key.key, attempt.LockedUntil.Format(time.RFC3339), attempt.Failures)
+
+		if err := l.repo.RecordLockout(domain.LoginLockout{
+			ID:          l.generateID(),
+			Key:         key.key,
+			Failures:    attempt.Failures,
+			IP:          ip,
+			LockedUntil: *attempt.LockedUntil,
+			CreatedAt:   now,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if retryAfter > 0 {
+		return &domain.RateLimitError{RetryAfter: retryAfter}
+	}
+
+	return nil
+}
+
+// Success сбрасывает только счётчик аккаунта: удачный вход одного
+// пользователя не должен обнулять ошибки, набранные с того же IP
+func (l *loginLimiter) Success(email, ip string) error {
+	return l.repo.Reset(accountLimiterKey(email))
+}
+
+type limiterKey struct {
+	key    string
+	policy domain.LockoutPolicy
+}
+
+func loginLimiterKeys(email, ip string) []limiterKey {
+	keys := []limiterKey{{accountLimiterKey(email), loginLockoutPolicy(loginAccountFreeAttempts)}}
+	if ip != "" {
+		keys = append(keys, limiterKey{"ip:" + ip, loginLockoutPolicy(loginIPFreeAttempts)})
+	}
+	return keys
+}
+
+func accountLimiterKey(email string) string {
+	return "account:" + strings.ToLower(email)
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/storage/memory"
+)
+
+func newTestLoginLimiter(now *time.Time) (*loginLimiter, *memory.LoginAttemptRepository) {
+	repo := memory.NewLoginAttemptRepository()
+
+	limiter := NewLoginLimiter(repo, func() string { return "lockout-1" }).(*loginLimiter)
+	limiter.now = func() time.Time { return *now }
+
+	return limiter, repo
+}
+
+func TestLoginLimiterLocksAccountWithBackoff(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter, repo := newTestLoginLimiter(&now)
+
+	for i := 0; i < loginAccountFreeAttempts-1; i++ {
+		if err := limiter.Failure("User@example.com", "10.0.0.1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := limiter.Check("user@example.com", "10.0.0.1"); err != nil {
+		t.Fatalf("expected no lockout before limit, got %v", err)
+	}
+
+	if err := limiter.Failure("user@example.com", "10.0.0.1"); !errors.Is(err, domain.ErrTooManyRequests) {
+		t.Fatalf("expected lockout after limit, got %v", err)
+	}
+
+	err := limiter.Check("user@example.com", "10.0.0.2")
+
+	var rateLimit *domain.RateLimitError
+	if !errors.As(err, &rateLimit) || !errors.Is(err, domain.ErrTooManyRequests) {
+		t.Fatalf("expected rate limit error, got %v", err)
+	}
+	if rateLimit.RetryAfter != loginBaseLockout {
+		t.Errorf("expected %s lockout, got %s", loginBaseLockout, rateLimit.RetryAfter)
+	}
+
+	// следующая неудача после окончания блокировки удваивает её
+	now = now.Add(loginBaseLockout)
+	if err := limiter.Failure("user@example.com", "10.0.0.1"); !errors.Is(err, domain.ErrTooManyRequests) {
+		t.Fatalf("expected lockout, got %v", err)
+	}
+
+	err = limiter.Check("user@example.com", "")
+	if !errors.As(err, &rateLimit) || rateLimit.RetryAfter != 2*loginBaseLockout {
+		t.Fatalf("expected %s lockout, got %v", 2*loginBaseLockout, err)
+	}
+
+	if got := len(repo.Lockouts()); got != 2 {
+		t.Errorf("expected 2 lockout audit records, got %d", got)
+	}
+}
+
+func TestLoginLimiterSuccessResetsAccountOnly(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter, repo := newTestLoginLimiter(&now)
+
+	for i := 0; i < 3; i++ {
+		if err := limiter.Failure("user@example.com", "10.0.0.1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := limiter.Success("user@example.com", "10.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repo.Get(accountLimiterKey("user@example.com")); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected account counter to be reset, got %v", err)
+	}
+
+	ipAttempt, err := repo.Get("ip:10.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ipAttempt.Failures != 3 {
+		t.Errorf("expected ip counter to keep 3 failures, got %d", ipAttempt.Failures)
+	}
+}
+
+func TestLoginLimiterForgetsOldFailures(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter, repo := newTestLoginLimiter(&now)
+
+	for i := 0; i < loginAccountFreeAttempts-1; i++ {
+		if err := limiter.Failure("user@example.com", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	now = now.Add(loginFailureWindow + time.Minute)
+	if err := limiter.Failure("user@example.com", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	attempt, err := repo.Get(accountLimiterKey("user@example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attempt.Failures != 1 {
+		t.Errorf("expected counter to restart, got %d", attempt.Failures)
+	}
+}
+
+func TestLockoutDurationIsCapped(t *testing.T) {
+	policy := loginLockoutPolicy(loginAccountFreeAttempts)
+
+	if got := policy.Lockout(loginAccountFreeAttempts - 1); got != 0 {
+		t.Errorf("expected no lockout for free attempts, got %s", got)
+	}
+	if got := policy.Lockout(loginAccountFreeAttempts); got != loginBaseLockout {
+		t.Errorf("expected %s, got %s", loginBaseLockout, got)
+	}
+	if got := policy.Lockout(loginAccountFreeAttempts + 100); got != loginMaxLockout {
+		t.Errorf("expected %s, got %s", loginMaxLockout, got)
+	}
+}
+
+func TestLoginLimiterFailureReportsLockout(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter, _ := newTestLoginLimiter(&now)
+
+	for i := 0; i < loginAccountFreeAttempts-1; i++ {
+		if err := limiter.Failure("user@example.com", ""); err != nil {
+			t.Fatalf("expected no lockout before limit, got %v", err)
+		}
+	}
+
+	// блокировку сообщает сама неудача, без отдельного Check
+	var rateLimit *domain.RateLimitError
+	if err := limiter.Failure("user@example.com", ""); !errors.As(err, &rateLimit) || rateLimit.RetryAfter != loginBaseLockout {
+		t.Fatalf("expected %s lockout, got %v", loginBaseLockout, err)
+	}
+
+	// неудача, пришедшая параллельно во время блокировки, продлевает её, а не сбрасывает
+	now = now.Add(10 * time.Second)
+	if err := limiter.Failure("user@example.com", ""); !errors.As(err, &rateLimit) || rateLimit.RetryAfter != 2*loginBaseLockout {
+		t.Fatalf("expected %s lockout, got %v", 2*loginBaseLockout, err)
+	}
+}
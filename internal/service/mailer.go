@@ -0,0 +1,12 @@
+package service
+
+type Email struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer отправляет письма пользователям. Реализации — в infra/mail.
+type Mailer interface {
+	Send(email Email) error
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/storage"
+)
+
+// между редиректом на провайдера и возвратом обратно должно пройти не больше
+const oidcStateTTL = 10 * time.Minute
+
+// OIDCProvider — клиент провайдера OpenID Connect (см. infra/oidc).
+type OIDCProvider interface {
+	Issuer() string
+	AuthCodeURL(state, nonce, codeChallenge string) (string, error)
+	// Exchange меняет code на токены и возвращает личность из проверенного id_token
+	Exchange(code, codeVerifier, nonce string) (OIDCIdentity, error)
+}
+
+type OIDCIdentity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+}
+
+type OIDCService interface {
+	// Begin возвращает адрес провайдера для редиректа и значение state,
+	// которое клиент должен вернуть в Complete
+	Begin() (redirectURL, state string, err error)
+	// Complete при включённой у пользователя 2FA возвращает challenge-токен:
+	// вход через провайдера не заменяет второй фактор
+	Complete(state, code string, client ClientInfo) (LoginResult, error)
+}
+
+type oidcService struct {
+	provider     OIDCProvider
+	stateRepo    storage.OIDCStateRepository
+	identityRepo storage.UserIdentityRepository
+	userRepo     storage.UserRepository
+	sessions     SessionStarter
+	invitations  InvitationClaimer
+	generateID   func() string
+}
+
+func NewOIDCService(
+	provider OIDCProvider,
+	stateRepo storage.OIDCStateRepository,
+	identityRepo storage.UserIdentityRepository,
+	userRepo storage.UserRepository,
+	sessions SessionStarter,
+	invitations InvitationClaimer,
+	generateID func() string,
+) OIDCService {
+	return &oidcService{
+		provider:     provider,
+		stateRepo:    stateRepo,
+		identityRepo: identityRepo,
+		userRepo:     userRepo,
+		sessions:     sessions,
+		invitations:  invitations,
+		generateID:   generateID,
+	}
+}
+
+func (s *oidcService) Begin() (string, string, error) {
+	state, err := generateSecret("")
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := generateSecret("")
+	if err != nil {
+		return "", "", err
+	}
+	verifier, err := generateSecret("")
+	if err != nil {
+		return "", "", err
+	}
+
+	now := time.Now()
+
+	if err := s.stateRepo.Create(domain.OIDCState{
+		ID:           hashToken(state),
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(oidcStateTTL),
+	}); err != nil {
+		return "", "", err
+	}
+
+	redirectURL, err := s.provider.AuthCodeURL(state, nonce, pkceChallenge(verifier))
+	if err != nil {
+		return "", "", err
+	}
+
+	return redirectURL, state, nil
+}
+
+func (s *oidcService) Complete(state, code string, client ClientInfo) (LoginResult, error) {
+	if state == "" || code == "" {
+		return LoginResult{}, domain.ErrInvalidInput
+	}
+
+	stored, err := s.stateRepo.Consume(hashToken(state), time.Now())
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return LoginResult{}, domain.ErrInvalidCredentials
+		}
+		return LoginResult{}, err
+	}
+
+	identity, err := s.provider.Exchange(code, stored.CodeVerifier, stored.Nonce)
+	if err != nil {
+		return LoginResult{}, domain.ErrInvalidCredentials
+	}
+
+	user, err := s.resolveUser(identity)
+	if err != nil {
+		return LoginResult{}, err
+	}
+	if user.Disabled() {
+		return LoginResult{}, domain.ErrUserDisabled
+	}
+
+	return s.sessions.StartSession(user, client)
+}
+
+// resolveUser ищет пользователя по привязке к провайдеру; при первом входе
+// привязывает существующего пользователя с тем же адресом или создаёт нового.
+// Без email_verified адресу провайдера не доверяем: иначе любой, кто заведёт
+// у провайдера чужой адрес, войдёт в чужой аккаунт. Локальный аккаунт с
+// неподтверждённым адресом тоже не привязываем: его мог заранее завести
+// кто-то другой и сохранить себе вход по паролю.
+func (s *oidcService) resolveUser(identity OIDCIdentity) (domain.User, error) {
+	provider := s.provider.Issuer()
+
+	linked, err := s.identityRepo.Get(provider, identity.Subject)
+	if err == nil {
+		return s.userRepo.GetByID(linked.UserID)
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return domain.User{}, err
+	}
+
+	if identity.Email == "" || !identity.EmailVerified {
+		return domain.User{}, domain.ErrEmailNotVerified
+	}
+
+	now := time.Now()
+
+	user, err := s.userRepo.GetByEmail(identity.Email)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		// пароля у такого пользователя нет, войти по паролю он сможет
+		// только после /auth/password/forgot
+		user = domain.User{
+			ID:            s.generateID(),
+			Email:         identity.Email,
+			CreatedAt:     now,
+			EmailVerified: true,
+		}
+		err = s.userRepo.Create(user)
+	case err == nil && !user.EmailVerified:
+		return domain.User{}, domain.ErrEmailNotVerified
+	}
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	err = s.identityRepo.Create(domain.UserIdentity{
+		Provider:  provider,
+		Subject:   identity.Subject,
+		UserID:    user.ID,
+		Email:     identity.Email,
+		CreatedAt: now,
+	})
+	// параллельный первый вход уже создал привязку — используем её
+	if errors.Is(err, domain.ErrConflict) {
+		linked, err := s.identityRepo.Get(provider, identity.Subject)
+		if err != nil {
+			return domain.User{}, err
+		}
+		return s.userRepo.GetByID(linked.UserID)
+	}
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	// адрес подтверждён провайдером, поэтому приглашения на него сразу становятся участием
+	if err := s.invitations.ClaimPending(user); err != nil {
+		log.Printf("claim invitations for user %s: %v", user.ID, err)
+	}
+
+	return user, nil
+}
+
+// pkceChallenge — code_challenge для метода S256 (RFC 7636)
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/storage"
+)
+
+const personalTokenPrefix = "tsp_"
+
+type PersonalTokenService interface {
+	// Create возвращает сохранённый токен и его значение; значение
+	// показывается только один раз и нигде не хранится
+	Create(userID, name string, scopes []domain.TokenScope, expiresAt *time.Time) (domain.PersonalAccessToken, string, error)
+	List(userID string) ([]domain.PersonalAccessToken, error)
+	Revoke(userID, tokenID string) error
+}
+
+type personalTokenService struct {
+	tokenRepo  storage.PersonalTokenRepository
+	generateID func() string
+}
+
+func NewPersonalTokenService(
+	tokenRepo storage.PersonalTokenRepository,
+	generateID func() string,
+) PersonalTokenService {
+	return &personalTokenService{
+		tokenRepo:  tokenRepo,
+		generateID: generateID,
+	}
+}
+
+func (s *personalTokenService) Create(
+	userID, name string,
+	scopes []domain.TokenScope,
+	expiresAt *time.Time,
+) (domain.PersonalAccessToken, string, error) {
+	if userID == "" || name == "" || len(scopes) == 0 {
+		return domain.PersonalAccessToken{}, "", domain.ErrInvalidInput
+	}
+
+	seen := make(map[domain.TokenScope]bool, len(scopes))
+	unique := make([]domain.TokenScope, 0, len(scopes))
+	for _, scope := range scopes {
+		if !scope.Valid() {
+			return domain.PersonalAccessToken{}, "", domain.ErrInvalidInput
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			unique = append(unique, scope)
+		}
+	}
+
+	now := time.Now()
+	if expiresAt != nil && !expiresAt.After(now) {
+		return domain.PersonalAccessToken{}, "", domain.ErrInvalidInput
+	}
+
+	secret, err := generateSecret(personalTokenPrefix)
+	if err != nil {
+		return domain.PersonalAccessToken{}, "", err
+	}
+
+	token := domain.PersonalAccessToken{
+		ID:        s.generateID(),
+		UserID:    userID,
+		Name:      name,
+		TokenHash: hashToken(secret),
+		Scopes:    unique,
+		CreatedAt: now,
+		ExpiresAt: expiresAt,
+	}
+
+	if err := s.tokenRepo.Create(token); err != nil {
+		return domain.PersonalAccessToken{}, "", err
+	}
+
+	return token, secret, nil
+}
+
+func (s *personalTokenService) List(userID string) ([]domain.PersonalAccessToken, error) {
+	if userID == "" {
+		return nil, domain.ErrInvalidInput
+	}
+
+	return s.tokenRepo.ListByUser(userID)
+}
+
+func (s *personalTokenService) Revoke(userID, tokenID string) error {
+	if userID == "" || tokenID == "" {
+		return domain.ErrInvalidInput
+	}
+
+	return s.tokenRepo.Revoke(userID, tokenID, time.Now())
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/storage"
+)
+
+// BoardPolicy решает, может ли пользователь выполнить действие на доске.
+// Все сервисы проверяют права только через неё.
+type BoardPolicy interface {
+	// Authorize возвращает роль пользователя на доске или ErrForbidden,
+	// если он не участник или его роли действие не разрешено. Администратор
+	// пространства доски получает доступ и без членства — тогда роль пустая.
+	Authorize(boardID, userID string, action domain.Action) (domain.BoardRole, error)
+	// AssignableRole проверяет, что роль можно выдать участнику доски:
+	// это editor, viewer или собственная роль этой доски
+	AssignableRole(boardID string, role domain.BoardRole) error
+}
+
+// rolePermissions — матрица «роль × действие» для встроенных ролей. Чтение
+// доступно всем участникам, изменение содержимого — редакторам, управление
+// составом, полями задач, публикация, удаление и передача доски — только владельцу.
+var rolePermissions = map[domain.BoardRole]map[domain.Action]bool{
+	domain.BoardRoleOwner: {
+		domain.ActionBoardRead:     true,
+		domain.ActionBoardUpdate:   true,
+		domain.ActionBoardDelete:   true,
+		domain.ActionBoardTransfer: true,
+		domain.ActionBoardShare:    true,
+		domain.ActionMemberManage:  true,
+		domain.ActionFieldManage:   true,
+		domain.ActionColumnWrite:   true,
+		domain.ActionColumnDelete:  true,
+		domain.ActionTaskWrite:     true,
+		domain.ActionTaskMove:      true,
+		domain.ActionTaskDelete:    true,
+		domain.ActionLabelManage:   true,
+	},
+	domain.BoardRoleEditor: {
+		domain.ActionBoardRead:    true,
+		domain.ActionBoardUpdate:  true,
+		domain.ActionColumnWrite:  true,
+		domain.ActionColumnDelete: true,
+		domain.ActionTaskWrite:    true,
+		domain.ActionTaskMove:     true,
+		domain.ActionTaskDelete:   true,
+		domain.ActionLabelManage:  true,
+	},
+	domain.BoardRoleViewer: {
+		domain.ActionBoardRead: true,
+	},
+}
+
+func roleAllows(role domain.BoardRole, action domain.Action) bool {
+	return rolePermissions[role][action]
+}
+
+// builtInRoles возвращает встроенные роли в виде определений для списка ролей доски
+func builtInRoles() []domain.BoardRoleDefinition {
+	roles := []domain.BoardRole{domain.BoardRoleOwner, domain.BoardRoleEditor, domain.BoardRoleViewer}
+
+	result := make([]domain.BoardRoleDefinition, 0, len(roles))
+	for _, role := range roles {
+		permissions := make([]domain.Action, 0, len(domain.Actions))
+		for _, action := range domain.Actions {
+			if roleAllows(role, action) {
+				permissions = append(permissions, action)
+			}
+		}
+		result = append(result, domain.BoardRoleDefinition{Name: role, Permissions: permissions, BuiltIn: true})
+	}
+
+	return result
+}
+
+// workspaceAdminAllows — права администратора пространства на его доски:
+// всё, что может владелец, кроме передачи доски — владелец у неё остаётся свой
+func workspaceAdminAllows(action domain.Action) bool {
+	return action != domain.ActionBoardTransfer && roleAllows(domain.BoardRoleOwner, action)
+}
+
+type boardPolicy struct {
+	boardMemberRepo     storage.BoardMemberRepository
+	boardRoleRepo       storage.BoardRoleRepository
+	workspaceMemberRepo storage.WorkspaceMemberRepository
+}
+
+func NewBoardPolicy(
+	boardMemberRepo storage.BoardMemberRepository,
+	boardRoleRepo storage.BoardRoleRepository,
+	workspaceMemberRepo storage.WorkspaceMemberRepository,
+) BoardPolicy {
+	return &boardPolicy{
+		boardMemberRepo:     boardMemberRepo,
+		boardRoleRepo:       boardRoleRepo,
+		workspaceMemberRepo: workspaceMemberRepo,
+	}
+}
+
+func (p *boardPolicy) Authorize(boardID, userID string, action domain.Action) (domain.BoardRole, error) {
+	role, err := p.boardMemberRepo.GetRole(boardID, userID)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return "", err
+	}
+	member := err == nil
+
+	if member {
+		allowed, err := p.allows(boardID, role, action)
+		if err != nil {
+			return "", err
+		}
+		if allowed {
+			return role, nil
+		}
+	}
+
+	admin, err := p.isWorkspaceAdmin(boardID, userID)
+	if err != nil {
+		return "", err
+	}
+	if admin && workspaceAdminAllows(action) {
+		return role, nil
+	}
+
+	if !member {
+		return "", domain.ErrForbidden
+	}
+
+	return role, fmt.Errorf("%w: role %s cannot %s", domain.ErrForbidden, role, action)
+}
+
+func (p *boardPolicy) isWorkspaceAdmin(boardID, userID string) (bool, error) {
+	role, err := p.workspaceMemberRepo.GetRoleByBoard(boardID, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return role == domain.WorkspaceRoleAdmin, nil
+}
+
+func (p *boardPolicy) AssignableRole(boardID string, role domain.BoardRole) error {
+	switch role {
+	case domain.BoardRoleEditor, domain.BoardRoleViewer:
+		return nil
+	case domain.BoardRoleOwner, "":
+		return domain.ErrInvalidInput
+	}
+
+	if _, err := p.boardRoleRepo.GetByName(boardID, role); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.ErrInvalidInput
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (p *boardPolicy) allows(boardID string, role domain.BoardRole, action domain.Action) (bool, error) {
+	if role.IsBuiltIn() {
+		return roleAllows(role, action), nil
+	}
+
+	// любой участник может читать доску, даже если его роль удалена
+	if action == domain.ActionBoardRead {
+		return true, nil
+	}
+
+	custom, err := p.boardRoleRepo.GetByName(boardID, role)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	for _, permission := range custom.Permissions {
+		if permission == action {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/storage"
+)
+
+type fakeRoleRepo struct {
+	storage.BoardRoleRepository
+	roles map[string]domain.BoardRoleDefinition
+}
+
+func newFakeRoleRepo(roles ...domain.BoardRoleDefinition) *fakeRoleRepo {
+	repo := &fakeRoleRepo{roles: make(map[string]domain.BoardRoleDefinition)}
+	for _, role := range roles {
+		repo.roles[role.BoardID+"|"+string(role.Name)] = role
+	}
+	return repo
+}
+
+func (r *fakeRoleRepo) GetByName(boardID string, name domain.BoardRole) (domain.BoardRoleDefinition, error) {
+	role, ok := r.roles[boardID+"|"+string(name)]
+	if !ok {
+		return domain.BoardRoleDefinition{}, domain.ErrNotFound
+	}
+	return role, nil
+}
+
+func TestRolePermissions(t *testing.T) {
+	const (
+		owner = 1 << iota
+		editor
+		viewer
+	)
+
+	// кому разрешено каждое действие; тест падает, если в domain.Actions
+	// появится действие без строки в этой таблице
+	allowed := map[domain.Action]int{
+		domain.ActionBoardRead:     owner | editor | viewer,
+		domain.ActionBoardUpdate:   owner | editor,
+		domain.ActionBoardDelete:   owner,
+		domain.ActionBoardTransfer: owner,
+		domain.ActionBoardShare:    owner,
+		domain.ActionMemberManage:  owner,
+		domain.ActionFieldManage:   owner,
+		domain.ActionColumnWrite:   owner | editor,
+		domain.ActionColumnDelete:  owner | editor,
+		domain.ActionTaskWrite:     owner | editor,
+		domain.ActionTaskMove:      owner | editor,
+		domain.ActionTaskDelete:    owner | editor,
+		domain.ActionLabelManage:   owner | editor,
+	}
+
+	roles := []struct {
+		role domain.BoardRole
+		bit  int
+	}{
+		{domain.BoardRoleOwner, owner},
+		{domain.BoardRoleEditor, editor},
+		{domain.BoardRoleViewer, viewer},
+	}
+
+	for _, action := range domain.Actions {
+		mask, ok := allowed[action]
+		if !ok {
+			t.Errorf("action %s is missing from the test table", action)
+			continue
+		}
+
+		for _, r := range roles {
+			want := mask&r.bit != 0
+
+			t.Run(string(r.role)+"/"+string(action), func(t *testing.T) {
+				if got := roleAllows(r.role, action); got != want {
+					t.Errorf("roleAllows(%s, %s) = %v, want %v", r.role, action, got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestBoardPolicyAuthorize(t *testing.T) {
+	policy := NewBoardPolicy(
+		&fakeMemberRepo{roles: map[string]domain.BoardRole{
+			"board-1|editor":  domain.BoardRoleEditor,
+			"board-1|viewer":  domain.BoardRoleViewer,
+			"board-1|triager": "triager",
+			"board-1|orphan":  "deleted-role",
+			"board-1|lead":    domain.BoardRoleViewer,
+		}},
+		newFakeRoleRepo(domain.BoardRoleDefinition{
+			BoardID:     "board-1",
+			Name:        "triager",
+			Permissions: []domain.Action{domain.ActionTaskMove},
+		}),
+		&fakeWorkspaceMemberRepo{
+			roles: map[string]domain.WorkspaceRole{
+				"ws-1|admin":  domain.WorkspaceRoleAdmin,
+				"ws-1|lead":   domain.WorkspaceRoleAdmin,
+				"ws-1|member": domain.WorkspaceRoleMember,
+			},
+			boards: map[string]string{"board-1": "ws-1"},
+		},
+	)
+
+	tests := []struct {
+		name   string
+		userID string
+		action domain.Action
+		want   error
+	}{
+		{"editor writes tasks", "editor", domain.ActionTaskWrite, nil},
+		{"editor cannot delete board", "editor", domain.ActionBoardDelete, domain.ErrForbidden},
+		{"viewer reads board", "viewer", domain.ActionBoardRead, nil},
+		{"viewer cannot move tasks", "viewer", domain.ActionTaskMove, domain.ErrForbidden},
+		{"viewer cannot delete columns", "viewer", domain.ActionColumnDelete, domain.ErrForbidden},
+		{"non-member cannot read", "stranger", domain.ActionBoardRead, domain.ErrForbidden},
+		{"custom role reads board", "triager", domain.ActionBoardRead, nil},
+		{"custom role moves tasks", "triager", domain.ActionTaskMove, nil},
+		{"custom role cannot edit tasks", "triager", domain.ActionTaskWrite, domain.ErrForbidden},
+		{"deleted role keeps read access", "orphan", domain.ActionBoardRead, nil},
+		{"deleted role cannot move tasks", "orphan", domain.ActionTaskMove, domain.ErrForbidden},
+		{"workspace admin reads board", "admin", domain.ActionBoardRead, nil},
+		{"workspace admin manages members", "admin", domain.ActionMemberManage, nil},
+		{"workspace admin deletes board", "admin", domain.ActionBoardDelete, nil},
+		{"workspace admin cannot transfer board", "admin", domain.ActionBoardTransfer, domain.ErrForbidden},
+		{"workspace admin overrides viewer role", "lead", domain.ActionTaskWrite, nil},
+		{"workspace member has no implicit access", "member", domain.ActionBoardRead, domain.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := policy.Authorize("board-1", tt.userID, tt.action)
+			if tt.want == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestBoardPolicyAssignableRole(t *testing.T) {
+	policy := NewBoardPolicy(&fakeMemberRepo{}, newFakeRoleRepo(domain.BoardRoleDefinition{
+		BoardID: "board-1",
+		Name:    "triager",
+	}), newFakeWorkspaceMemberRepo())
+
+	tests := []struct {
+		role domain.BoardRole
+		want error
+	}{
+		{domain.BoardRoleEditor, nil},
+		{domain.BoardRoleViewer, nil},
+		{"triager", nil},
+		{domain.BoardRoleOwner, domain.ErrInvalidInput},
+		{"unknown", domain.ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.role), func(t *testing.T) {
+			err := policy.AssignableRole("board-1", tt.role)
+			if !errors.Is(err, tt.want) && !(tt.want == nil && err == nil) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestNormalizeRolePermissions(t *testing.T) {
+	permissions, err := normalizeRolePermissions([]domain.Action{
+		domain.ActionTaskMove,
+		domain.ActionBoardRead,
+		domain.ActionTaskMove,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(permissions) != 1 || permissions[0] != domain.ActionTaskMove {
+		t.Errorf("expected [task:move], got %v", permissions)
+	}
+
+	for _, action := range []domain.Action{domain.ActionMemberManage, domain.ActionBoardDelete, "task:everything"} {
+		if _, err := normalizeRolePermissions([]domain.Action{action}); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("expected %s to be rejected, got %v", action, err)
+		}
+	}
+}
@@ -0,0 +1,770 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+// Обёртки ниже ограничивают сервисы набором прав персонального токена.
+// Проверка членства и ролей остаётся во внутренних сервисах: токен может
+// только сузить права владельца, но не расширить их.
+
+func requireScope(principal domain.Principal, scope domain.TokenScope) error {
+	if !principal.HasScope(scope) {
+		return fmt.Errorf("%w: token has no %s scope", domain.ErrForbidden, scope)
+	}
+	return nil
+}
+
+type scopedBoardService struct {
+	next      BoardService
+	principal domain.Principal
+}
+
+func NewScopedBoardService(next BoardService, principal domain.Principal) BoardService {
+	return &scopedBoardService{next: next, principal: principal}
+}
+
+func (s *scopedBoardService) Create(userID, name string) (domain.Board, error) {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return domain.Board{}, err
+	}
+	return s.next.Create(userID, name)
+}
+
+func (s *scopedBoardService) GetAll(userID string) ([]domain.Board, error) {
+	if err := requireScope(s.principal, domain.ScopeReadBoards); err != nil {
+		return nil, err
+	}
+	return s.next.GetAll(userID)
+}
+
+func (s *scopedBoardService) Update(userID, boardID, name string) (domain.Board, error) {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return domain.Board{}, err
+	}
+	return s.next.Update(userID, boardID, name)
+}
+
+func (s *scopedBoardService) Delete(userID, boardID string) error {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return err
+	}
+	return s.next.Delete(userID, boardID)
+}
+
+func (s *scopedBoardService) InviteUser(ownerID string, boardID string, userID string, role domain.BoardRole) error {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return err
+	}
+	return s.next.InviteUser(ownerID, boardID, userID, role)
+}
+
+func (s *scopedBoardService) GetMembers(requesterID, boardID string) ([]domain.BoardMember, error) {
+	if err := requireScope(s.principal, domain.ScopeReadBoards); err != nil {
+		return nil, err
+	}
+	return s.next.GetMembers(requesterID, boardID)
+}
+
+func (s *scopedBoardService) RemoveUser(requesterID, boardID, userID string) error {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return err
+	}
+	return s.next.RemoveUser(requesterID, boardID, userID)
+}
+
+func (s *scopedBoardService) ChangeMemberRole(ownerID, boardID, userID string, role domain.BoardRole) error {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return err
+	}
+	return s.next.ChangeMemberRole(ownerID, boardID, userID, role)
+}
+
+func (s *scopedBoardService) TransferOwnership(ownerID, boardID, newOwnerID string) error {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return err
+	}
+	return s.next.TransferOwnership(ownerID, boardID, newOwnerID)
+}
+
+func (s *scopedBoardService) Leave(userID, boardID string) error {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return err
+	}
+	return s.next.Leave(userID, boardID)
+}
+
+func (s *scopedBoardService) CreateInviteLink(
+	ownerID, boardID string,
+	role domain.BoardRole,
+	maxUses *int,
+	expiresAt *time.Time,
+) (domain.BoardInviteLink, string, error) {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return domain.BoardInviteLink{}, "", err
+	}
+	return s.next.CreateInviteLink(ownerID, boardID, role, maxUses, expiresAt)
+}
+
+func (s *scopedBoardService) GetInviteLinks(ownerID, boardID string) ([]domain.BoardInviteLink, error) {
+	if err := requireScope(s.principal, domain.ScopeReadBoards); err != nil {
+		return nil, err
+	}
+	return s.next.GetInviteLinks(ownerID, boardID)
+}
+
+func (s *scopedBoardService) RevokeInviteLink(ownerID, linkID string) error {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return err
+	}
+	return s.next.RevokeInviteLink(ownerID, linkID)
+}
+
+func (s *scopedBoardService) JoinByInviteLink(userID, token string) (domain.Board, error) {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return domain.Board{}, err
+	}
+	return s.next.JoinByInviteLink(userID, token)
+}
+
+type scopedColumnService struct {
+	next      ColumnService
+	principal domain.Principal
+}
+
+func NewScopedColumnService(next ColumnService, principal domain.Principal) ColumnService {
+	return &scopedColumnService{next: next, principal: principal}
+}
+
+func (s *scopedColumnService) Create(userID, title string, boardID string) (domain.Column, error) {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return domain.Column{}, err
+	}
+	return s.next.Create(userID, title, boardID)
+}
+
+func (s *scopedColumnService) GetByBoardID(userID, boardID string) ([]domain.Column, error) {
+	if err := requireScope(s.principal, domain.ScopeReadBoards); err != nil {
+		return nil, err
+	}
+	return s.next.GetByBoardID(userID, boardID)
+}
+
+func (s *scopedColumnService) Update(userID, columnID string, title string) (domain.Column, error) {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return domain.Column{}, err
+	}
+	return s.next.Update(userID, columnID, title)
+}
+
+func (s *scopedColumnService) Move(userID, columnID string, position int) (domain.Column, error) {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return domain.Column{}, err
+	}
+	return s.next.Move(userID, columnID, position)
+}
+
+func (s *scopedColumnService) Delete(userID, columnID string) error {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return err
+	}
+	return s.next.Delete(userID, columnID)
+}
+
+type scopedTaskService struct {
+	next      TaskService
+	principal domain.Principal
+}
+
+func NewScopedTaskService(next TaskService, principal domain.Principal) TaskService {
+	return &scopedTaskService{next: next, principal: principal}
+}
+
+func (s *scopedTaskService) Create(userID, title, description, columnID string, dates TaskDates, fields domain.CustomFieldValues) (domain.Task, error) {
+	if err := requireScope(s.principal, domain.ScopeWriteTasks); err != nil {
+		return domain.Task{}, err
+	}
+	return s.next.Create(userID, title, description, columnID, dates, fields)
+}
+
+func (s *scopedTaskService) GetByColumnID(userID, columnID, labelID string) ([]domain.Task, error) {
+	if err := requireScope(s.principal, domain.ScopeReadTasks); err != nil {
+		return nil, err
+	}
+	return s.next.GetByColumnID(userID, columnID, labelID)
+}
+
+func (s *scopedTaskService) Update(userID, taskID string, title string, description string, update TaskUpdate) (domain.Task, error) {
+	if err := requireScope(s.principal, domain.ScopeWriteTasks); err != nil {
+		return domain.Task{}, err
+	}
+	return s.next.Update(userID, taskID, title, description, update)
+}
+
+func (s *scopedTaskService) Move(userID, taskID string, columnID string, position int) (domain.Task, error) {
+	if err := requireScope(s.principal, domain.ScopeWriteTasks); err != nil {
+		return domain.Task{}, err
+	}
+	return s.next.Move(userID, taskID, columnID, position)
+}
+
+func (s *scopedTaskService) Delete(userID, taskID string) error {
+	if err := requireScope(s.principal, domain.ScopeWriteTasks); err != nil {
+		return err
+	}
+	return s.next.Delete(userID, taskID)
+}
+
+func (s *scopedTaskService) GetOverdue(userID, boardID string) ([]domain.Task, error) {
+	if err := requireScope(s.principal, domain.ScopeReadTasks); err != nil {
+		return nil, err
+	}
+	return s.next.GetOverdue(userID, boardID)
+}
+
+func (s *scopedTaskService) GetDueWithin(userID, boardID string, days int) ([]domain.Task, error) {
+	if err := requireScope(s.principal, domain.ScopeReadTasks); err != nil {
+		return nil, err
+	}
+	return s.next.GetDueWithin(userID, boardID, days)
+}
+
+type scopedTaskAssigneeService struct {
+	next      TaskAssigneeService
+	principal domain.Principal
+}
+
+func NewScopedTaskAssigneeService(next TaskAssigneeService, principal domain.Principal) TaskAssigneeService {
+	return &scopedTaskAssigneeService{next: next, principal: principal}
+}
+
+func (s *scopedTaskAssigneeService) List(userID, taskID string) ([]domain.TaskAssignee, error) {
+	if err := requireScope(s.principal, domain.ScopeReadTasks); err != nil {
+		return nil, err
+	}
+	return s.next.List(userID, taskID)
+}
+
+func (s *scopedTaskAssigneeService) Assign(userID, taskID, assigneeID string) error {
+	if err := requireScope(s.principal, domain.ScopeWriteTasks); err != nil {
+		return err
+	}
+	return s.next.Assign(userID, taskID, assigneeID)
+}
+
+func (s *scopedTaskAssigneeService) Unassign(userID, taskID, assigneeID string) error {
+	if err := requireScope(s.principal, domain.ScopeWriteTasks); err != nil {
+		return err
+	}
+	return s.next.Unassign(userID, taskID, assigneeID)
+}
+
+func (s *scopedTaskAssigneeService) ListMine(userID string) ([]domain.AssignedBoard, error) {
+	if err := requireScope(s.principal, domain.ScopeReadTasks); err != nil {
+		return nil, err
+	}
+	return s.next.ListMine(userID)
+}
+
+func (s *scopedTaskAssigneeService) ListMineOverdue(userID string) ([]domain.AssignedTask, error) {
+	if err := requireScope(s.principal, domain.ScopeReadTasks); err != nil {
+		return nil, err
+	}
+	return s.next.ListMineOverdue(userID)
+}
+
+func (s *scopedTaskAssigneeService) ListMineDueWithin(userID string, days int) ([]domain.AssignedTask, error) {
+	if err := requireScope(s.principal, domain.ScopeReadTasks); err != nil {
+		return nil, err
+	}
+	return s.next.ListMineDueWithin(userID, days)
+}
+
+type scopedLabelService struct {
+	next      LabelService
+	principal domain.Principal
+}
+
+func NewScopedLabelService(next LabelService, principal domain.Principal) LabelService {
+	return &scopedLabelService{next: next, principal: principal}
+}
+
+func (s *scopedLabelService) List(userID, boardID string) ([]domain.Label, error) {
+	if err := requireScope(s.principal, domain.ScopeReadBoards); err != nil {
+		return nil, err
+	}
+	return s.next.List(userID, boardID)
+}
+
+func (s *scopedLabelService) Create(userID, boardID, name, color string) (domain.Label, error) {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return domain.Label{}, err
+	}
+	return s.next.Create(userID, boardID, name, color)
+}
+
+func (s *scopedLabelService) Update(userID, labelID, name, color string) (domain.Label, error) {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return domain.Label{}, err
+	}
+	return s.next.Update(userID, labelID, name, color)
+}
+
+func (s *scopedLabelService) Delete(userID, labelID string) error {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return err
+	}
+	return s.next.Delete(userID, labelID)
+}
+
+func (s *scopedLabelService) Attach(userID, taskID, labelID string) error {
+	if err := requireScope(s.principal, domain.ScopeWriteTasks); err != nil {
+		return err
+	}
+	return s.next.Attach(userID, taskID, labelID)
+}
+
+func (s *scopedLabelService) Detach(userID, taskID, labelID string) error {
+	if err := requireScope(s.principal, domain.ScopeWriteTasks); err != nil {
+		return err
+	}
+	return s.next.Detach(userID, taskID, labelID)
+}
+
+type scopedCustomFieldService struct {
+	next      CustomFieldService
+	principal domain.Principal
+}
+
+func NewScopedCustomFieldService(next CustomFieldService, principal domain.Principal) CustomFieldService {
+	return &scopedCustomFieldService{next: next, principal: principal}
+}
+
+func (s *scopedCustomFieldService) List(userID, boardID string) ([]domain.CustomField, error) {
+	if err := requireScope(s.principal, domain.ScopeReadBoards); err != nil {
+		return nil, err
+	}
+	return s.next.List(userID, boardID)
+}
+
+func (s *scopedCustomFieldService) Create(
+	userID, boardID, name string,
+	fieldType domain.CustomFieldType,
+	options []string,
+) (domain.CustomField, error) {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return domain.CustomField{}, err
+	}
+	return s.next.Create(userID, boardID, name, fieldType, options)
+}
+
+func (s *scopedCustomFieldService) Update(userID, fieldID, name string, options []string) (domain.CustomField, error) {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return domain.CustomField{}, err
+	}
+	return s.next.Update(userID, fieldID, name, options)
+}
+
+func (s *scopedCustomFieldService) Delete(userID, fieldID string) error {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return err
+	}
+	return s.next.Delete(userID, fieldID)
+}
+
+type scopedChecklistService struct {
+	next      ChecklistService
+	principal domain.Principal
+}
+
+func NewScopedChecklistService(next ChecklistService, principal domain.Principal) ChecklistService {
+	return &scopedChecklistService{next: next, principal: principal}
+}
+
+func (s *scopedChecklistService) List(userID, taskID string) ([]domain.Checklist, error) {
+	if err := requireScope(s.principal, domain.ScopeReadTasks); err != nil {
+		return nil, err
+	}
+	return s.next.List(userID, taskID)
+}
+
+func (s *scopedChecklistService) Create(userID, taskID, title string) (domain.Checklist, error) {
+	if err := requireScope(s.principal, domain.ScopeWriteTasks); err != nil {
+		return domain.Checklist{}, err
+	}
+	return s.next.Create(userID, taskID, title)
+}
+
+func (s *scopedChecklistService) Rename(userID, checklistID, title string) (domain.Checklist, error) {
+	if err := requireScope(s.principal, domain.ScopeWriteTasks); err != nil {
+		return domain.Checklist{}, err
+	}
+	return s.next.Rename(userID, checklistID, title)
+}
+
+func (s *scopedChecklistService) Delete(userID, checklistID string) error {
+	if err := requireScope(s.principal, domain.ScopeWriteTasks); err != nil {
+		return err
+	}
+	return s.next.Delete(userID, checklistID)
+}
+
+func (s *scopedChecklistService) AddItem(userID, checklistID, title string) (domain.ChecklistItem, error) {
+	if err := requireScope(s.principal, domain.ScopeWriteTasks); err != nil {
+		return domain.ChecklistItem{}, err
+	}
+	return s.next.AddItem(userID, checklistID, title)
+}
+
+func (s *scopedChecklistService) UpdateItem(userID, itemID, title string, done bool) (domain.ChecklistItem, error) {
+	if err := requireScope(s.principal, domain.ScopeWriteTasks); err != nil {
+		return domain.ChecklistItem{}, err
+	}
+	return s.next.UpdateItem(userID, itemID, title, done)
+}
+
+func (s *scopedChecklistService) MoveItem(userID, itemID, checklistID string, position int) (domain.ChecklistItem, error) {
+	if err := requireScope(s.principal, domain.ScopeWriteTasks); err != nil {
+		return domain.ChecklistItem{}, err
+	}
+	return s.next.MoveItem(userID, itemID, checklistID, position)
+}
+
+func (s *scopedChecklistService) DeleteItem(userID, itemID string) error {
+	if err := requireScope(s.principal, domain.ScopeWriteTasks); err != nil {
+		return err
+	}
+	return s.next.DeleteItem(userID, itemID)
+}
+
+func (s *scopedChecklistService) ConvertItem(userID, itemID, columnID string) (domain.Task, error) {
+	if err := requireScope(s.principal, domain.ScopeWriteTasks); err != nil {
+		return domain.Task{}, err
+	}
+	return s.next.ConvertItem(userID, itemID, columnID)
+}
+
+type scopedEventService struct {
+	next      EventService
+	principal domain.Principal
+}
+
+func NewScopedEventService(next EventService, principal domain.Principal) EventService {
+	return &scopedEventService{next: next, principal: principal}
+}
+
+func (s *scopedEventService) Publish(event domain.BoardEvent) {
+	s.next.Publish(event)
+}
+
+func (s *scopedEventService) Subscribe(userID, boardID string) (<-chan domain.BoardEvent, func(), error) {
+	if err := requireScope(s.principal, domain.ScopeReadBoards); err != nil {
+		return nil, nil, err
+	}
+	return s.next.Subscribe(userID, boardID)
+}
+
+func (s *scopedEventService) History(userID, boardID string, afterID int64) ([]domain.BoardEvent, error) {
+	if err := requireScope(s.principal, domain.ScopeReadBoards); err != nil {
+		return nil, err
+	}
+	return s.next.History(userID, boardID, afterID)
+}
+
+type scopedUserService struct {
+	next      UserService
+	principal domain.Principal
+}
+
+func NewScopedUserService(next UserService, principal domain.Principal) UserService {
+	return &scopedUserService{next: next, principal: principal}
+}
+
+func (s *scopedUserService) GetMe(userID string) (domain.User, error) {
+	return s.next.GetMe(userID)
+}
+
+func (s *scopedUserService) UpdateProfile(userID string, input ProfileInput) (domain.User, error) {
+	return s.next.UpdateProfile(userID, input)
+}
+
+// каталог раскрывает участников досок, поэтому требует права на чтение досок
+func (s *scopedUserService) Search(requesterID, query string) ([]domain.UserProfile, error) {
+	if err := requireScope(s.principal, domain.ScopeReadBoards); err != nil {
+		return nil, err
+	}
+	return s.next.Search(requesterID, query)
+}
+
+type scopedInvitationService struct {
+	next      InvitationService
+	principal domain.Principal
+}
+
+func NewScopedInvitationService(next InvitationService, principal domain.Principal) InvitationService {
+	return &scopedInvitationService{next: next, principal: principal}
+}
+
+func (s *scopedInvitationService) Invite(ownerID, boardID, email string, role domain.BoardRole) (domain.BoardInvitation, error) {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return domain.BoardInvitation{}, err
+	}
+	return s.next.Invite(ownerID, boardID, email, role)
+}
+
+func (s *scopedInvitationService) ListForBoard(requesterID, boardID string) ([]domain.BoardInvitation, error) {
+	if err := requireScope(s.principal, domain.ScopeReadBoards); err != nil {
+		return nil, err
+	}
+	return s.next.ListForBoard(requesterID, boardID)
+}
+
+func (s *scopedInvitationService) Revoke(ownerID, invitationID string) error {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return err
+	}
+	return s.next.Revoke(ownerID, invitationID)
+}
+
+// входящие приглашения меняют состав досок пользователя, поэтому
+// персональному токену без прав на запись доступен только их список
+func (s *scopedInvitationService) ListMine(userID string) ([]domain.BoardInvitation, error) {
+	if err := requireScope(s.principal, domain.ScopeReadBoards); err != nil {
+		return nil, err
+	}
+	return s.next.ListMine(userID)
+}
+
+func (s *scopedInvitationService) Accept(userID, invitationID string) (domain.BoardInvitation, error) {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return domain.BoardInvitation{}, err
+	}
+	return s.next.Accept(userID, invitationID)
+}
+
+func (s *scopedInvitationService) AcceptByToken(userID, token string) (domain.BoardInvitation, error) {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return domain.BoardInvitation{}, err
+	}
+	return s.next.AcceptByToken(userID, token)
+}
+
+func (s *scopedInvitationService) Decline(userID, invitationID string) error {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return err
+	}
+	return s.next.Decline(userID, invitationID)
+}
+
+type scopedBoardRoleService struct {
+	next      BoardRoleService
+	principal domain.Principal
+}
+
+func NewScopedBoardRoleService(next BoardRoleService, principal domain.Principal) BoardRoleService {
+	return &scopedBoardRoleService{next: next, principal: principal}
+}
+
+func (s *scopedBoardRoleService) List(requesterID, boardID string) ([]domain.BoardRoleDefinition, error) {
+	if err := requireScope(s.principal, domain.ScopeReadBoards); err != nil {
+		return nil, err
+	}
+	return s.next.List(requesterID, boardID)
+}
+
+func (s *scopedBoardRoleService) Create(
+	ownerID, boardID string,
+	name domain.BoardRole,
+	permissions []domain.Action,
+) (domain.BoardRoleDefinition, error) {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return domain.BoardRoleDefinition{}, err
+	}
+	return s.next.Create(ownerID, boardID, name, permissions)
+}
+
+func (s *scopedBoardRoleService) Update(
+	ownerID, boardID string,
+	name domain.BoardRole,
+	permissions []domain.Action,
+) (domain.BoardRoleDefinition, error) {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return domain.BoardRoleDefinition{}, err
+	}
+	return s.next.Update(ownerID, boardID, name, permissions)
+}
+
+func (s *scopedBoardRoleService) Delete(ownerID, boardID string, name domain.BoardRole) error {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return err
+	}
+	return s.next.Delete(ownerID, boardID, name)
+}
+
+type scopedWorkspaceService struct {
+	next      WorkspaceService
+	principal domain.Principal
+}
+
+func NewScopedWorkspaceService(next WorkspaceService, principal domain.Principal) WorkspaceService {
+	return &scopedWorkspaceService{next: next, principal: principal}
+}
+
+func (s *scopedWorkspaceService) Create(userID, name string) (domain.Workspace, error) {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return domain.Workspace{}, err
+	}
+	return s.next.Create(userID, name)
+}
+
+func (s *scopedWorkspaceService) GetAll(userID string) ([]domain.Workspace, error) {
+	if err := requireScope(s.principal, domain.ScopeReadBoards); err != nil {
+		return nil, err
+	}
+	return s.next.GetAll(userID)
+}
+
+func (s *scopedWorkspaceService) GetMembers(requesterID, workspaceID string) ([]domain.WorkspaceMember, error) {
+	if err := requireScope(s.principal, domain.ScopeReadBoards); err != nil {
+		return nil, err
+	}
+	return s.next.GetMembers(requesterID, workspaceID)
+}
+
+func (s *scopedWorkspaceService) AddMember(adminID, workspaceID, userID string, role domain.WorkspaceRole) error {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return err
+	}
+	return s.next.AddMember(adminID, workspaceID, userID, role)
+}
+
+func (s *scopedWorkspaceService) RemoveMember(adminID, workspaceID, userID string) error {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return err
+	}
+	return s.next.RemoveMember(adminID, workspaceID, userID)
+}
+
+func (s *scopedWorkspaceService) GetBoards(userID, workspaceID string) ([]domain.Board, error) {
+	if err := requireScope(s.principal, domain.ScopeReadBoards); err != nil {
+		return nil, err
+	}
+	return s.next.GetBoards(userID, workspaceID)
+}
+
+func (s *scopedWorkspaceService) CreateBoard(userID, workspaceID, name string) (domain.Board, error) {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return domain.Board{}, err
+	}
+	return s.next.CreateBoard(userID, workspaceID, name)
+}
+
+func (s *scopedWorkspaceService) MoveBoard(userID, boardID, workspaceID string) (domain.Board, error) {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return domain.Board{}, err
+	}
+	return s.next.MoveBoard(userID, boardID, workspaceID)
+}
+
+type scopedTeamService struct {
+	next      TeamService
+	principal domain.Principal
+}
+
+func NewScopedTeamService(next TeamService, principal domain.Principal) TeamService {
+	return &scopedTeamService{next: next, principal: principal}
+}
+
+func (s *scopedTeamService) Create(userID, name string) (domain.Team, error) {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return domain.Team{}, err
+	}
+	return s.next.Create(userID, name)
+}
+
+func (s *scopedTeamService) GetAll(userID string) ([]domain.Team, error) {
+	if err := requireScope(s.principal, domain.ScopeReadBoards); err != nil {
+		return nil, err
+	}
+	return s.next.GetAll(userID)
+}
+
+func (s *scopedTeamService) GetMembers(requesterID, teamID string) ([]domain.TeamMember, error) {
+	if err := requireScope(s.principal, domain.ScopeReadBoards); err != nil {
+		return nil, err
+	}
+	return s.next.GetMembers(requesterID, teamID)
+}
+
+func (s *scopedTeamService) AddMember(maintainerID, teamID, userID string, role domain.TeamRole) error {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return err
+	}
+	return s.next.AddMember(maintainerID, teamID, userID, role)
+}
+
+func (s *scopedTeamService) RemoveMember(maintainerID, teamID, userID string) error {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return err
+	}
+	return s.next.RemoveMember(maintainerID, teamID, userID)
+}
+
+func (s *scopedTeamService) GrantBoard(ownerID, boardID, teamID string, role domain.BoardRole) error {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return err
+	}
+	return s.next.GrantBoard(ownerID, boardID, teamID, role)
+}
+
+func (s *scopedTeamService) RevokeBoard(ownerID, boardID, teamID string) error {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return err
+	}
+	return s.next.RevokeBoard(ownerID, boardID, teamID)
+}
+
+func (s *scopedTeamService) GetBoardTeams(requesterID, boardID string) ([]domain.BoardTeamGrant, error) {
+	if err := requireScope(s.principal, domain.ScopeReadBoards); err != nil {
+		return nil, err
+	}
+	return s.next.GetBoardTeams(requesterID, boardID)
+}
+
+type scopedBoardShareService struct {
+	next      BoardShareService
+	principal domain.Principal
+}
+
+func NewScopedBoardShareService(next BoardShareService, principal domain.Principal) BoardShareService {
+	return &scopedBoardShareService{next: next, principal: principal}
+}
+
+func (s *scopedBoardShareService) Get(ownerID, boardID string) (domain.BoardShare, error) {
+	if err := requireScope(s.principal, domain.ScopeReadBoards); err != nil {
+		return domain.BoardShare{}, err
+	}
+	return s.next.Get(ownerID, boardID)
+}
+
+func (s *scopedBoardShareService) Enable(ownerID, boardID string) (domain.BoardShare, string, error) {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return domain.BoardShare{}, "", err
+	}
+	return s.next.Enable(ownerID, boardID)
+}
+
+func (s *scopedBoardShareService) Disable(ownerID, boardID string) error {
+	if err := requireScope(s.principal, domain.ScopeWriteBoards); err != nil {
+		return err
+	}
+	return s.next.Disable(ownerID, boardID)
+}
+
+// публичная доска не требует авторизации, scope здесь не проверяется
+func (s *scopedBoardShareService) GetPublicBoard(token string) (domain.PublicBoard, error) {
+	return s.next.GetPublicBoard(token)
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+type stubTaskService struct {
+	TaskService
+	moved bool
+}
+
+func (s *stubTaskService) Move(userID, taskID string, columnID string, position int) (domain.Task, error) {
+	s.moved = true
+	return domain.Task{ID: taskID, ColumnID: columnID, Position: position}, nil
+}
+
+func (s *stubTaskService) GetByColumnID(userID, columnID, labelID string) ([]domain.Task, error) {
+	return []domain.Task{}, nil
+}
+
+func TestScopedTaskServiceReadOnlyTokenCannotMove(t *testing.T) {
+	inner := &stubTaskService{}
+	service := NewScopedTaskService(inner, domain.Principal{
+		UserID: "1",
+		Scopes: []domain.TokenScope{domain.ScopeReadBoards, domain.ScopeReadTasks},
+	})
+
+	if _, err := service.GetByColumnID("1", "column-1", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err := service.Move("1", "task-1", "column-1", 0)
+	if !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden, got %v", err)
+	}
+
+	if inner.moved {
+		t.Error("inner service must not be called without scope")
+	}
+}
+
+func TestScopedTaskServiceSessionHasFullAccess(t *testing.T) {
+	inner := &stubTaskService{}
+	service := NewScopedTaskService(inner, domain.Principal{UserID: "1"})
+
+	if _, err := service.Move("1", "task-1", "column-1", 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !inner.moved {
+		t.Error("expected inner service to be called")
+	}
+}
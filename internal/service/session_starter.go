@@ -0,0 +1,115 @@
+package service
+
+import (
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/storage"
+)
+
+// SessionStarter завершает вход пользователя, личность которого уже
+// подтверждена внешним способом (OIDC): выдаёт токены или, если у него
+// включена 2FA, challenge-токен для /auth/login/2fa.
+type SessionStarter interface {
+	StartSession(user domain.User, client ClientInfo) (LoginResult, error)
+}
+
+type sessionStarter struct {
+	refreshTokenRepo storage.RefreshTokenRepository
+	sessionRepo      storage.SessionRepository
+	jwt              JWTManager
+	generateID       func() string
+}
+
+// NewSessionStarter отдаёт внешним способам входа только выдачу сессий:
+// в AuthService входа без пароля нет
+func NewSessionStarter(
+	refreshTokenRepo storage.RefreshTokenRepository,
+	sessionRepo storage.SessionRepository,
+	jwt JWTManager,
+	generateID func() string,
+) SessionStarter {
+	return newSessionStarter(refreshTokenRepo, sessionRepo, jwt, generateID)
+}
+
+func newSessionStarter(
+	refreshTokenRepo storage.RefreshTokenRepository,
+	sessionRepo storage.SessionRepository,
+	jwt JWTManager,
+	generateID func() string,
+) *sessionStarter {
+	return &sessionStarter{
+		refreshTokenRepo: refreshTokenRepo,
+		sessionRepo:      sessionRepo,
+		jwt:              jwt,
+		generateID:       generateID,
+	}
+}
+
+func (s *sessionStarter) StartSession(user domain.User, client ClientInfo) (LoginResult, error) {
+	if user.TOTPEnabled {
+		challenge, err := s.jwt.GenerateChallengeToken(user.ID)
+		if err != nil {
+			return LoginResult{}, err
+		}
+		return LoginResult{ChallengeToken: challenge}, nil
+	}
+
+	tokens, err := s.start(user.ID, client)
+	if err != nil {
+		return LoginResult{}, err
+	}
+
+	return LoginResult{Tokens: tokens}, nil
+}
+
+// каждый логин открывает новую сессию и новое семейство refresh-токенов
+func (s *sessionStarter) start(userID string, client ClientInfo) (Tokens, error) {
+	now := time.Now()
+
+	session := domain.Session{
+		ID:         s.generateID(),
+		UserID:     userID,
+		UserAgent:  client.UserAgent,
+		IP:         client.IP,
+		CreatedAt:  now,
+		LastUsedAt: now,
+		ExpiresAt:  now.Add(s.jwt.RefreshTTL()),
+	}
+
+	if err := s.sessionRepo.Create(session); err != nil {
+		return Tokens{}, err
+	}
+
+	return s.issueTokens(userID, session.ID)
+}
+
+func (s *sessionStarter) issueTokens(userID, familyID string) (Tokens, error) {
+	accessToken, err := s.jwt.GenerateAccessToken(userID)
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	refreshToken, err := s.jwt.GenerateRefreshToken(userID)
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	now := time.Now()
+
+	if err := s.refreshTokenRepo.Create(domain.RefreshToken{
+		ID:        s.generateID(),
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: now.Add(s.jwt.RefreshTTL()),
+		CreatedAt: now,
+	}); err != nil {
+		return Tokens{}, err
+	}
+
+	return Tokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
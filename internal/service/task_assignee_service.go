@@ -0,0 +1,200 @@
+package service
+
+import (
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/storage"
+)
+
+// TaskAssigneeService управляет исполнителями задач. Исполнителем может
+// быть только тот, у кого есть доступ к доске задачи.
+type TaskAssigneeService interface {
+	List(userID, taskID string) ([]domain.TaskAssignee, error)
+	Assign(userID, taskID, assigneeID string) error
+	Unassign(userID, taskID, assigneeID string) error
+
+	// ListMine возвращает назначенные пользователю задачи со всех его досок,
+	// сгруппированные по доскам и колонкам
+	ListMine(userID string) ([]domain.AssignedBoard, error)
+	// ListMineOverdue и ListMineDueWithin возвращают назначенные задачи
+	// со всех досок по возрастанию срока
+	ListMineOverdue(userID string) ([]domain.AssignedTask, error)
+	ListMineDueWithin(userID string, days int) ([]domain.AssignedTask, error)
+}
+
+type taskAssigneeService struct {
+	assigneeRepo    storage.TaskAssigneeRepository
+	taskRepo        storage.TaskRepository
+	columnRepo      storage.ColumnRepository
+	boardMemberRepo storage.BoardMemberRepository
+	policy          BoardPolicy
+	events          EventPublisher
+}
+
+func NewTaskAssigneeService(
+	assigneeRepo storage.TaskAssigneeRepository,
+	taskRepo storage.TaskRepository,
+	columnRepo storage.ColumnRepository,
+	boardMemberRepo storage.BoardMemberRepository,
+	policy BoardPolicy,
+	events EventPublisher,
+) TaskAssigneeService {
+	return &taskAssigneeService{
+		assigneeRepo:    assigneeRepo,
+		taskRepo:        taskRepo,
+		columnRepo:      columnRepo,
+		boardMemberRepo: boardMemberRepo,
+		policy:          policy,
+		events:          events,
+	}
+}
+
+func (s *taskAssigneeService) List(userID, taskID string) ([]domain.TaskAssignee, error) {
+	if taskID == "" {
+		return nil, domain.ErrInvalidInput
+	}
+
+	if _, err := s.authorizeTask(taskID, userID, domain.ActionBoardRead); err != nil {
+		return nil, err
+	}
+
+	return s.assigneeRepo.ListByTask(taskID)
+}
+
+func (s *taskAssigneeService) Assign(userID, taskID, assigneeID string) error {
+	if taskID == "" || assigneeID == "" {
+		return domain.ErrInvalidInput
+	}
+
+	boardID, err := s.authorizeTask(taskID, userID, domain.ActionTaskWrite)
+	if err != nil {
+		return err
+	}
+
+	isMember, err := s.boardMemberRepo.IsMember(boardID, assigneeID)
+	if err != nil {
+		return err
+	}
+	if !isMember {
+		return domain.ErrInvalidInput
+	}
+
+	if err := s.assigneeRepo.Add(domain.TaskAssignee{
+		TaskID:     taskID,
+		UserID:     assigneeID,
+		AssignedBy: userID,
+		CreatedAt:  time.Now(),
+	}); err != nil {
+		return err
+	}
+
+	s.events.Publish(newBoardEvent(domain.EventTaskAssigned, boardID, userID, map[string]string{
+		"task_id": taskID,
+		"user_id": assigneeID,
+	}))
+
+	return nil
+}
+
+func (s *taskAssigneeService) Unassign(userID, taskID, assigneeID string) error {
+	if taskID == "" || assigneeID == "" {
+		return domain.ErrInvalidInput
+	}
+
+	boardID, err := s.authorizeTask(taskID, userID, domain.ActionTaskWrite)
+	if err != nil {
+		return err
+	}
+
+	if err := s.assigneeRepo.Remove(taskID, assigneeID); err != nil {
+		return err
+	}
+
+	s.events.Publish(newBoardEvent(domain.EventTaskUnassigned, boardID, userID, map[string]string{
+		"task_id": taskID,
+		"user_id": assigneeID,
+	}))
+
+	return nil
+}
+
+func (s *taskAssigneeService) ListMine(userID string) ([]domain.AssignedBoard, error) {
+	if userID == "" {
+		return nil, domain.ErrInvalidInput
+	}
+
+	tasks, err := s.assigneeRepo.ListByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return groupAssignedTasks(tasks), nil
+}
+
+func (s *taskAssigneeService) ListMineOverdue(userID string) ([]domain.AssignedTask, error) {
+	if userID == "" {
+		return nil, domain.ErrInvalidInput
+	}
+
+	return s.assigneeRepo.ListDueByUser(userID, nil, time.Now())
+}
+
+func (s *taskAssigneeService) ListMineDueWithin(userID string, days int) ([]domain.AssignedTask, error) {
+	if userID == "" || days < 1 || days > maxDueWithinDays {
+		return nil, domain.ErrInvalidInput
+	}
+
+	now := time.Now()
+
+	return s.assigneeRepo.ListDueByUser(userID, &now, now.AddDate(0, 0, days))
+}
+
+// authorizeTask проверяет право на доске задачи и возвращает ID доски
+func (s *taskAssigneeService) authorizeTask(taskID, userID string, action domain.Action) (string, error) {
+	task, err := s.taskRepo.GetByID(taskID)
+	if err != nil {
+		return "", err
+	}
+
+	column, err := s.columnRepo.GetByID(task.ColumnID)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := s.policy.Authorize(column.BoardID, userID, action); err != nil {
+		return "", err
+	}
+
+	return column.BoardID, nil
+}
+
+// groupAssignedTasks ожидает задачи, упорядоченные по доске, колонке и позиции
+func groupAssignedTasks(tasks []domain.AssignedTask) []domain.AssignedBoard {
+	boards := make([]domain.AssignedBoard, 0)
+
+	for _, t := range tasks {
+		if len(boards) == 0 || boards[len(boards)-1].ID != t.BoardID {
+			boards = append(boards, domain.AssignedBoard{
+				ID:      t.BoardID,
+				Name:    t.BoardName,
+				Columns: []domain.AssignedColumn{},
+			})
+		}
+		board := &boards[len(boards)-1]
+
+		if len(board.Columns) == 0 || board.Columns[len(board.Columns)-1].ID != t.ColumnID {
+			board.Columns = append(board.Columns, domain.AssignedColumn{
+				ID:       t.ColumnID,
+				Title:    t.ColumnTitle,
+				Position: t.ColumnPosition,
+				Tasks:    []domain.Task{},
+			})
+		}
+		column := &board.Columns[len(board.Columns)-1]
+
+		column.Tasks = append(column.Tasks, t.Task)
+	}
+
+	return boards
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/storage"
+)
+
+type fakeAssigneeRepo struct {
+	storage.TaskAssigneeRepository
+	// ключ "задача|пользователь"
+	assigned map[string]bool
+	// задачи досок, чтобы снимать назначения в RemoveFromBoard
+	boardTasks map[string][]string
+}
+
+func (r *fakeAssigneeRepo) Add(assignee domain.TaskAssignee) error {
+	r.assigned[assignee.TaskID+"|"+assignee.UserID] = true
+	return nil
+}
+
+func (r *fakeAssigneeRepo) Remove(taskID, userID string) error {
+	if !r.assigned[taskID+"|"+userID] {
+		return domain.ErrNotFound
+	}
+	delete(r.assigned, taskID+"|"+userID)
+	return nil
+}
+
+func (r *fakeAssigneeRepo) RemoveFromBoard(boardID, userID string) error {
+	for _, taskID := range r.boardTasks[boardID] {
+		delete(r.assigned, taskID+"|"+userID)
+	}
+	return nil
+}
+
+func (r *fakeColumnRepo) GetByID(columnID string) (domain.Column, error) {
+	for _, column := range r.columns {
+		if column.ID == columnID {
+			return column, nil
+		}
+	}
+	return domain.Column{}, domain.ErrNotFound
+}
+
+func (r *fakeTaskRepo) GetByID(taskID string) (domain.Task, error) {
+	for _, tasks := range r.tasks {
+		for _, task := range tasks {
+			if task.ID == taskID {
+				return task, nil
+			}
+		}
+	}
+	return domain.Task{}, domain.ErrNotFound
+}
+
+func newTestTaskAssigneeService() (TaskAssigneeService, *fakeMemberRepo, *fakeAssigneeRepo) {
+	members := &fakeMemberRepo{roles: map[string]domain.BoardRole{
+		"board-1|owner":  domain.BoardRoleOwner,
+		"board-1|editor": domain.BoardRoleEditor,
+		"board-1|viewer": domain.BoardRoleViewer,
+	}}
+	assignees := &fakeAssigneeRepo{
+		assigned:   map[string]bool{},
+		boardTasks: map[string][]string{"board-1": {"task-1"}},
+	}
+
+	service := NewTaskAssigneeService(
+		assignees,
+		&fakeTaskRepo{tasks: map[string][]domain.Task{
+			"col-1": {{ID: "task-1", ColumnID: "col-1", Title: "Dark mode"}},
+		}},
+		&fakeColumnRepo{columns: []domain.Column{{ID: "col-1", BoardID: "board-1", Title: "Q3"}}},
+		members,
+		NewBoardPolicy(members, newFakeRoleRepo(), newFakeWorkspaceMemberRepo()),
+		discardEvents{},
+	)
+
+	return service, members, assignees
+}
+
+func TestAssignTask(t *testing.T) {
+	service, _, assignees := newTestTaskAssigneeService()
+
+	if err := service.Assign("editor", "task-1", "viewer"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !assignees.assigned["task-1|viewer"] {
+		t.Error("expected viewer to be assigned")
+	}
+
+	if err := service.Unassign("editor", "task-1", "viewer"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if assignees.assigned["task-1|viewer"] {
+		t.Error("expected viewer to be unassigned")
+	}
+}
+
+func TestAssignTaskRequiresBoardMember(t *testing.T) {
+	service, _, _ := newTestTaskAssigneeService()
+
+	if err := service.Assign("editor", "task-1", "stranger"); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput, got %v", err)
+	}
+}
+
+func TestAssignTaskRequiresTaskWrite(t *testing.T) {
+	service, _, _ := newTestTaskAssigneeService()
+
+	if err := service.Assign("viewer", "task-1", "viewer"); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("expected ErrForbidden, got %v", err)
+	}
+}
+
+func TestRemoveUserUnassignsTasks(t *testing.T) {
+	members := &fakeMemberRepo{roles: map[string]domain.BoardRole{
+		"board-1|owner":  domain.BoardRoleOwner,
+		"board-1|editor": domain.BoardRoleEditor,
+	}}
+	assignees := &fakeAssigneeRepo{
+		assigned:   map[string]bool{"task-1|editor": true, "task-1|owner": true},
+		boardTasks: map[string][]string{"board-1": {"task-1"}},
+	}
+
+	service := NewBoardService(fakeBoardRepo{}, nil, nil, members, assignees, NewBoardPolicy(members, newFakeRoleRepo(), newFakeWorkspaceMemberRepo()), nil, discardEvents{}, func() string { return "id" })
+
+	if err := service.RemoveUser("owner", "board-1", "editor"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if assignees.assigned["task-1|editor"] {
+		t.Error("expected removed member to be unassigned")
+	}
+	if !assignees.assigned["task-1|owner"] {
+		t.Error("expected other assignees to stay")
+	}
+}
+
+func TestTeamAccessLossUnassignsTasks(t *testing.T) {
+	members := &fakeMemberRepo{roles: map[string]domain.BoardRole{
+		"board-1|anna":  domain.BoardRoleOwner,
+		"board-1|boris": domain.BoardRoleEditor,
+	}}
+	teamMembers := &fakeTeamMemberRepo{roles: map[string]domain.TeamRole{
+		"team-1|anna":  domain.TeamRoleMaintainer,
+		"team-1|boris": domain.TeamRoleMember,
+		"team-1|vera":  domain.TeamRoleMember,
+		"team-1|gleb":  domain.TeamRoleMember,
+	}}
+	grants := &fakeBoardTeamRepo{grants: map[string]domain.BoardRole{
+		"board-1|team-1": domain.BoardRoleEditor,
+	}}
+	assignees := &fakeAssigneeRepo{
+		assigned:   map[string]bool{"task-1|boris": true, "task-1|vera": true, "task-1|gleb": true},
+		boardTasks: map[string][]string{"board-1": {"task-1"}},
+	}
+
+	service := NewTeamService(
+		&fakeTeamRepo{members: teamMembers},
+		teamMembers,
+		grants,
+		members,
+		assignees,
+		&fakeUserRepo{users: map[string]domain.User{}},
+		NewBoardPolicy(members, newFakeRoleRepo(), newFakeWorkspaceMemberRepo()),
+		discardEvents{},
+		func() string { return "id" },
+	)
+
+	// vera была на доске только через команду
+	if err := service.RemoveMember("anna", "team-1", "vera"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if assignees.assigned["task-1|vera"] {
+		t.Error("expected removed team member to be unassigned")
+	}
+
+	if err := service.RevokeBoard("anna", "board-1", "team-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if assignees.assigned["task-1|gleb"] {
+		t.Error("expected member of the revoked team to be unassigned")
+	}
+	// у boris осталось прямое участие
+	if !assignees.assigned["task-1|boris"] {
+		t.Error("expected direct member to stay assigned")
+	}
+}
+
+func TestGroupAssignedTasks(t *testing.T) {
+	tasks := []domain.AssignedTask{
+		{Task: domain.Task{ID: "t1", ColumnID: "c1"}, BoardID: "b1", BoardName: "Roadmap", ColumnTitle: "Todo"},
+		{Task: domain.Task{ID: "t2", ColumnID: "c1"}, BoardID: "b1", BoardName: "Roadmap", ColumnTitle: "Todo"},
+		{Task: domain.Task{ID: "t3", ColumnID: "c2"}, BoardID: "b1", BoardName: "Roadmap", ColumnTitle: "Done", ColumnPosition: 1},
+		{Task: domain.Task{ID: "t4", ColumnID: "c3"}, BoardID: "b2", BoardName: "Bugs", ColumnTitle: "New"},
+	}
+
+	boards := groupAssignedTasks(tasks)
+
+	if len(boards) != 2 {
+		t.Fatalf("expected 2 boards, got %d", len(boards))
+	}
+	if len(boards[0].Columns) != 2 || len(boards[0].Columns[0].Tasks) != 2 {
+		t.Errorf("unexpected grouping of first board: %+v", boards[0])
+	}
+	if boards[1].Name != "Bugs" || boards[1].Columns[0].Tasks[0].ID != "t4" {
+		t.Errorf("unexpected second board: %+v", boards[1])
+	}
+}
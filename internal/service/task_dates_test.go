@@ -0,0 +1,162 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+func (r *fakeTaskRepo) ListDueByBoard(boardID string, from *time.Time, to time.Time) ([]domain.Task, error) {
+	var result []domain.Task
+	for _, tasks := range r.tasks {
+		for _, task := range tasks {
+			if task.DueAt == nil || !task.DueAt.Before(to) {
+				continue
+			}
+			if from != nil && task.DueAt.Before(*from) {
+				continue
+			}
+			result = append(result, task)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeTaskRepo) Update(task domain.Task) (domain.Task, error) {
+	tasks := r.tasks[task.ColumnID]
+	for i := range tasks {
+		if tasks[i].ID == task.ID {
+			tasks[i] = task
+		}
+	}
+	return task, nil
+}
+
+func newTestTaskDatesService() TaskService {
+	members := &fakeMemberRepo{roles: map[string]domain.BoardRole{
+		"board-1|editor": domain.BoardRoleEditor,
+	}}
+
+	now := time.Now()
+	yesterday := now.AddDate(0, 0, -1)
+	inThreeDays := now.AddDate(0, 0, 3)
+	inMonth := now.AddDate(0, 1, 0)
+
+	tasks := &fakeTaskRepo{tasks: map[string][]domain.Task{
+		"col-1": {
+			{ID: "overdue", ColumnID: "col-1", Title: "Overdue", DueAt: &yesterday},
+			{ID: "soon", ColumnID: "col-1", Title: "Soon", DueAt: &inThreeDays},
+			{ID: "later", ColumnID: "col-1", Title: "Later", DueAt: &inMonth},
+			{ID: "undated", ColumnID: "col-1", Title: "Undated"},
+		},
+	}}
+
+	return NewTaskService(
+		tasks,
+		&fakeColumnRepo{columns: []domain.Column{{ID: "col-1", BoardID: "board-1"}}},
+		&fakeFieldRepo{},
+		members,
+		NewBoardPolicy(members, newFakeRoleRepo(), newFakeWorkspaceMemberRepo()),
+		discardEvents{},
+		func() string { return "id" },
+	)
+}
+
+func TestTaskUpdateRejectsStartAfterDue(t *testing.T) {
+	service := newTestTaskDatesService()
+
+	start := time.Date(2026, 5, 2, 10, 0, 0, 0, time.UTC)
+	// тот же момент в другом часовом поясе — не раньше начала
+	due := time.Date(2026, 5, 2, 13, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+
+	task, err := service.Update("editor", "soon", "Soon", "", TaskUpdate{
+		StartAt: OptionalTime{Set: true, Value: &start},
+		DueAt:   OptionalTime{Set: true, Value: &due},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if task.StartAt == nil || !task.DueAt.Equal(due) {
+		t.Errorf("expected dates to be set, got %+v", task)
+	}
+
+	earlier := start.Add(-time.Hour)
+	if _, err := service.Update("editor", "soon", "Soon", "", TaskUpdate{
+		StartAt: OptionalTime{Set: true, Value: &start},
+		DueAt:   OptionalTime{Set: true, Value: &earlier},
+	}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput, got %v", err)
+	}
+
+	// новый срок сверяется с сохранённым началом
+	if _, err := service.Update("editor", "soon", "Soon", "", TaskUpdate{
+		DueAt: OptionalTime{Set: true, Value: &earlier},
+	}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput against stored start, got %v", err)
+	}
+}
+
+func TestTaskUpdateKeepsDatesUnlessSet(t *testing.T) {
+	service := newTestTaskDatesService()
+
+	start := time.Now().Add(-time.Hour)
+	task, err := service.Update("editor", "soon", "Soon", "", TaskUpdate{
+		StartAt: OptionalTime{Set: true, Value: &start},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if task.DueAt == nil || task.StartAt == nil {
+		t.Fatalf("expected due date to be kept and start to be set, got %+v", task)
+	}
+
+	task, err = service.Update("editor", "soon", "Renamed", "", TaskUpdate{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if task.Title != "Renamed" || task.DueAt == nil || task.StartAt == nil {
+		t.Errorf("expected only the title to change, got %+v", task)
+	}
+
+	task, err = service.Update("editor", "soon", "Renamed", "", TaskUpdate{
+		DueAt: OptionalTime{Set: true},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if task.DueAt != nil || task.StartAt == nil {
+		t.Errorf("expected only the due date to be cleared, got %+v", task)
+	}
+}
+
+func TestTaskServiceDueQueries(t *testing.T) {
+	service := newTestTaskDatesService()
+
+	overdue, err := service.GetOverdue("editor", "board-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(overdue) != 1 || overdue[0].ID != "overdue" {
+		t.Errorf("expected only the overdue task, got %+v", overdue)
+	}
+
+	due, err := service.GetDueWithin("editor", "board-1", 7)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(due) != 1 || due[0].ID != "soon" {
+		t.Errorf("expected only the task due soon, got %+v", due)
+	}
+
+	for _, days := range []int{0, -1, maxDueWithinDays + 1} {
+		if _, err := service.GetDueWithin("editor", "board-1", days); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("days=%d: expected ErrInvalidInput, got %v", days, err)
+		}
+	}
+
+	if _, err := service.GetOverdue("stranger", "board-1"); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("expected ErrForbidden, got %v", err)
+	}
+}
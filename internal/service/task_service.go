@@ -2,52 +2,110 @@ package service
 
 import (
 	"errors"
+	"slices"
 	"time"
 
 	"github.com/ovk741/TasksStream/internal/domain"
 	"github.com/ovk741/TasksStream/internal/storage"
 )
 
+// maxDueWithinDays ограничивает окно в запросах «срок в ближайшие N дней»
+const maxDueWithinDays = 365
+
 type TaskService interface {
-	Create(userID, title, description, columnID string) (domain.Task, error)
-	GetByColumnID(userID, columnID string) ([]domain.Task, error)
-	Update(userID, taskID string, title string, description string) (domain.Task, error)
+	// Create и Update принимают значения полей доски по ID поля
+	Create(userID, title, description, columnID string, dates TaskDates, fields domain.CustomFieldValues) (domain.Task, error)
+	// GetByColumnID возвращает задачи колонки; непустой labelID оставляет
+	// только задачи с этой меткой
+	GetByColumnID(userID, columnID, labelID string) ([]domain.Task, error)
+	// Update меняет необязательные поля, только если они заданы в update
+	Update(userID, taskID string, title string, description string, update TaskUpdate) (domain.Task, error)
 	Move(userID, taskID string, columnID string, position int) (domain.Task, error)
 	Delete(userID, taskID string) error
+
+	// GetOverdue и GetDueWithin возвращают задачи доски по возрастанию срока
+	GetOverdue(userID, boardID string) ([]domain.Task, error)
+	GetDueWithin(userID, boardID string, days int) ([]domain.Task, error)
+}
+
+// TaskDates — необязательные начало и срок новой задачи.
+type TaskDates struct {
+	StartAt *time.Time
+	DueAt   *time.Time
+}
+
+// OptionalTime — дата в обновлении задачи: Set=false оставляет сохранённое
+// значение, Set=true с nil Value очищает его.
+type OptionalTime struct {
+	Set   bool
+	Value *time.Time
+}
+
+// TaskUpdate — необязательные поля обновления задачи.
+type TaskUpdate struct {
+	StartAt OptionalTime
+	DueAt   OptionalTime
+	// nil оставляет значения полей доски как есть, иначе заменяет их целиком
+	CustomFields domain.CustomFieldValues
+}
+
+func (d TaskDates) validate() error {
+	if d.StartAt != nil && d.DueAt != nil && d.StartAt.After(*d.DueAt) {
+		return domain.ErrInvalidInput
+	}
+	return nil
 }
 
 type taskService struct {
 	taskRepo        storage.TaskRepository
 	columnRepo      storage.ColumnRepository
+	fieldRepo       storage.CustomFieldRepository
 	boardMemberRepo storage.BoardMemberRepository
+	policy          BoardPolicy
+	events          EventPublisher
 	generateID      func() string
 }
 
 func NewTaskService(
 	taskRepo storage.TaskRepository,
 	columnRepo storage.ColumnRepository,
+	fieldRepo storage.CustomFieldRepository,
 	boardMemberRepo storage.BoardMemberRepository,
+	policy BoardPolicy,
+	events EventPublisher,
 	generateID func() string,
 ) TaskService {
 	return &taskService{
 		taskRepo:        taskRepo,
 		columnRepo:      columnRepo,
+		fieldRepo:       fieldRepo,
 		boardMemberRepo: boardMemberRepo,
+		policy:          policy,
+		events:          events,
 		generateID:      generateID,
 	}
 }
 
-func (s *taskService) Create(userID, title string, description string, columnID string) (domain.Task, error) {
+func (s *taskService) Create(userID, title string, description string, columnID string, dates TaskDates, fields domain.CustomFieldValues) (domain.Task, error) {
 	if title == "" || columnID == "" {
 		return domain.Task{}, domain.ErrInvalidInput
 	}
 
+	if err := dates.validate(); err != nil {
+		return domain.Task{}, err
+	}
+
 	column, err := s.columnRepo.GetByID(columnID)
 	if err != nil {
 		return domain.Task{}, domain.ErrNotFound
 	}
 
-	if err := s.requireBoardAccess(column.BoardID, userID); err != nil {
+	if _, err := s.policy.Authorize(column.BoardID, userID, domain.ActionTaskWrite); err != nil {
+		return domain.Task{}, err
+	}
+
+	values, err := s.validateFields(column.BoardID, fields)
+	if err != nil {
 		return domain.Task{}, err
 	}
 
@@ -63,15 +121,22 @@ func (s *taskService) Create(userID, title string, description string, columnID
 		Description: description,
 		Position:    len(tasks),
 		CreatedAt:   time.Now(),
+		StartAt:     dates.StartAt,
+		DueAt:       dates.DueAt,
+
+		CustomFields: values,
 	}
 
-	if _, err := s.taskRepo.Create(task); err != nil {
+	created, err := s.taskRepo.Create(task)
+	if err != nil {
 		return domain.Task{}, err
 	}
 
-	return task, nil
+	s.events.Publish(newBoardEvent(domain.EventTaskCreated, column.BoardID, userID, created))
+
+	return created, nil
 }
-func (s *taskService) GetByColumnID(userID, columnID string) ([]domain.Task, error) {
+func (s *taskService) GetByColumnID(userID, columnID, labelID string) ([]domain.Task, error) {
 	if columnID == "" {
 		return nil, domain.ErrInvalidInput
 	}
@@ -81,21 +146,34 @@ func (s *taskService) GetByColumnID(userID, columnID string) ([]domain.Task, err
 		return nil, domain.ErrNotFound
 	}
 
-	if err := s.requireBoardAccess(column.BoardID, userID); err != nil {
+	if _, err := s.policy.Authorize(column.BoardID, userID, domain.ActionBoardRead); err != nil {
 		return nil, err
 	}
 
-	task, err := s.taskRepo.GetByColumnID(columnID)
+	tasks, err := s.taskRepo.GetByColumnID(columnID)
 	if err != nil {
 		return nil, err
 	}
-	return task, nil
+
+	if labelID == "" {
+		return tasks, nil
+	}
+
+	filtered := make([]domain.Task, 0, len(tasks))
+	for _, task := range tasks {
+		if slices.Contains(task.LabelIDs, labelID) {
+			filtered = append(filtered, task)
+		}
+	}
+
+	return filtered, nil
 }
 
-func (s *taskService) Update(userID, taskID string, title string, description string) (domain.Task, error) {
+func (s *taskService) Update(userID, taskID string, title string, description string, update TaskUpdate) (domain.Task, error) {
 	if taskID == "" || title == "" {
 		return domain.Task{}, domain.ErrInvalidInput
 	}
+
 	task, err := s.taskRepo.GetByID(taskID)
 	if err != nil {
 		return domain.Task{}, err
@@ -106,7 +184,7 @@ func (s *taskService) Update(userID, taskID string, title string, description st
 		return domain.Task{}, err
 	}
 
-	if err := s.requireBoardAccess(column.BoardID, userID); err != nil {
+	if _, err := s.policy.Authorize(column.BoardID, userID, domain.ActionTaskWrite); err != nil {
 		return domain.Task{}, err
 	}
 
@@ -114,11 +192,33 @@ func (s *taskService) Update(userID, taskID string, title string, description st
 
 	task.Description = description
 
+	if update.StartAt.Set {
+		task.StartAt = update.StartAt.Value
+	}
+	if update.DueAt.Set {
+		task.DueAt = update.DueAt.Value
+	}
+
+	// новое начало сверяется и с сохранённым сроком, и наоборот
+	if err := (TaskDates{StartAt: task.StartAt, DueAt: task.DueAt}).validate(); err != nil {
+		return domain.Task{}, err
+	}
+
+	if update.CustomFields != nil {
+		values, err := s.validateFields(column.BoardID, update.CustomFields)
+		if err != nil {
+			return domain.Task{}, err
+		}
+		task.CustomFields = values
+	}
+
 	updated, err := s.taskRepo.Update(task)
 	if err != nil {
 		return domain.Task{}, err
 	}
 
+	s.events.Publish(newBoardEvent(domain.EventTaskUpdated, column.BoardID, userID, updated))
+
 	return updated, nil
 }
 
@@ -138,11 +238,20 @@ func (s *taskService) Delete(userID, taskID string) error {
 		return err
 	}
 
-	if err := s.requireBoardAccess(column.BoardID, userID); err != nil {
+	if _, err := s.policy.Authorize(column.BoardID, userID, domain.ActionTaskDelete); err != nil {
+		return err
+	}
+
+	if err := s.taskRepo.Delete(taskID); err != nil {
 		return err
 	}
 
-	return s.taskRepo.Delete(taskID)
+	s.events.Publish(newBoardEvent(domain.EventTaskDeleted, column.BoardID, userID, map[string]string{
+		"id":        taskID,
+		"column_id": task.ColumnID,
+	}))
+
+	return nil
 
 }
 
@@ -177,24 +286,59 @@ func (s *taskService) Move(userID, taskID, columnID string, position int) (domai
 		return domain.Task{}, domain.ErrForbidden
 	}
 
-	if err := s.requireBoardAccess(sourceColumn.BoardID, userID); err != nil {
+	if _, err := s.policy.Authorize(sourceColumn.BoardID, userID, domain.ActionTaskMove); err != nil {
 		return domain.Task{}, err
 	}
 
-	return s.taskRepo.Move(taskID, columnID, position)
+	moved, err := s.taskRepo.Move(taskID, columnID, position)
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	s.events.Publish(newBoardEvent(domain.EventTaskMoved, sourceColumn.BoardID, userID, moved))
+
+	return moved, nil
 }
 
-func (s *taskService) requireBoardAccess(
-	boardID, userID string,
-) error {
+func (s *taskService) GetOverdue(userID, boardID string) ([]domain.Task, error) {
+	if boardID == "" {
+		return nil, domain.ErrInvalidInput
+	}
+
+	if _, err := s.policy.Authorize(boardID, userID, domain.ActionBoardRead); err != nil {
+		return nil, err
+	}
 
-	_, err := s.boardMemberRepo.GetRole(boardID, userID)
+	return s.taskRepo.ListDueByBoard(boardID, nil, time.Now())
+}
+
+func (s *taskService) GetDueWithin(userID, boardID string, days int) ([]domain.Task, error) {
+	if boardID == "" || days < 1 || days > maxDueWithinDays {
+		return nil, domain.ErrInvalidInput
+	}
+
+	if _, err := s.policy.Authorize(boardID, userID, domain.ActionBoardRead); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	return s.taskRepo.ListDueByBoard(boardID, &now, now.AddDate(0, 0, days))
+}
+
+// validateFields проверяет значения полей по схеме доски; пользователем
+// в поле типа user может быть только тот, у кого есть доступ к доске
+func (s *taskService) validateFields(boardID string, values domain.CustomFieldValues) (domain.CustomFieldValues, error) {
+	if len(values) == 0 {
+		return domain.CustomFieldValues{}, nil
+	}
+
+	fields, err := s.fieldRepo.ListByBoard(boardID)
 	if err != nil {
-		if errors.Is(err, domain.ErrNotFound) {
-			return domain.ErrForbidden
-		}
-		return err
+		return nil, err
 	}
 
-	return nil
+	return normalizeFieldValues(fields, values, func(userID string) (bool, error) {
+		return s.boardMemberRepo.IsMember(boardID, userID)
+	})
 }
@@ -7,6 +7,7 @@ import (
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/infra/events"
 	"github.com/ovk741/TasksStream/internal/storage/postgres"
 )
 
@@ -39,11 +40,11 @@ func TestCreateTask(t *testing.T) {
 
 	columnRepo.Create(column)
 
-	service := NewTaskService(taskRepo, columnRepo, boardMemberRepo, func() string {
+	service := NewTaskService(taskRepo, columnRepo, postgres.NewCustomFieldRepository(pool), boardMemberRepo, NewBoardPolicy(boardMemberRepo, postgres.NewBoardRoleRepository(pool), postgres.NewWorkspaceMemberRepository(pool)), events.NewBroker(), func() string {
 		return "task-1"
 	})
 
-	task, err := service.Create("1", "My task", "New", column.ID)
+	task, err := service.Create("1", "My task", "New", column.ID, TaskDates{}, nil)
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -88,11 +89,11 @@ func TestCreateTaskInvalidInput(t *testing.T) {
 
 	columnRepo.Create(column)
 
-	service := NewTaskService(taskRepo, columnRepo, boardMemberRepo, func() string {
+	service := NewTaskService(taskRepo, columnRepo, postgres.NewCustomFieldRepository(pool), boardMemberRepo, NewBoardPolicy(boardMemberRepo, postgres.NewBoardRoleRepository(pool), postgres.NewWorkspaceMemberRepository(pool)), events.NewBroker(), func() string {
 		return "task-1"
 	})
 
-	_, err = service.Create("1", "", "New", column.ID)
+	_, err = service.Create("1", "", "New", column.ID, TaskDates{}, nil)
 
 	if err == nil {
 		t.Fatal("expected error, got nil")
@@ -131,11 +132,11 @@ func TestTaskServiceCreateColumnNotFound(t *testing.T) {
 
 	columnRepo.Create(column)
 
-	service := NewTaskService(taskRepo, columnRepo, boardMemberRepo, func() string {
+	service := NewTaskService(taskRepo, columnRepo, postgres.NewCustomFieldRepository(pool), boardMemberRepo, NewBoardPolicy(boardMemberRepo, postgres.NewBoardRoleRepository(pool), postgres.NewWorkspaceMemberRepository(pool)), events.NewBroker(), func() string {
 		return "task-1"
 	})
 
-	_, err = service.Create("1", "Column", "New", "unknown-column")
+	_, err = service.Create("1", "Column", "New", "unknown-column", TaskDates{}, nil)
 
 	if err != domain.ErrNotFound {
 		t.Errorf("expected ErrNotFound, got %v", err)
@@ -170,11 +171,11 @@ func TestTaskServiceGetByColumnIDEmpty(t *testing.T) {
 
 	columnRepo.Create(column)
 
-	service := NewTaskService(taskRepo, columnRepo, boardMemberRepo, func() string {
+	service := NewTaskService(taskRepo, columnRepo, postgres.NewCustomFieldRepository(pool), boardMemberRepo, NewBoardPolicy(boardMemberRepo, postgres.NewBoardRoleRepository(pool), postgres.NewWorkspaceMemberRepository(pool)), events.NewBroker(), func() string {
 		return "task-1"
 	})
 
-	tasks, err := service.GetByColumnID("1", "Column-1")
+	tasks, err := service.GetByColumnID("1", "Column-1", "")
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -213,14 +214,14 @@ func TestTaskServiceGetByColumnIDWithData(t *testing.T) {
 
 	columnRepo.Create(column)
 
-	service := NewTaskService(taskRepo, columnRepo, boardMemberRepo, func() string {
+	service := NewTaskService(taskRepo, columnRepo, postgres.NewCustomFieldRepository(pool), boardMemberRepo, NewBoardPolicy(boardMemberRepo, postgres.NewBoardRoleRepository(pool), postgres.NewWorkspaceMemberRepository(pool)), events.NewBroker(), func() string {
 		return "task-1"
 	})
 
-	_, _ = service.Create("1", "Task 1", "New", "Column-1")
-	_, _ = service.Create("1", "Task 2", "Old", "Column-1")
+	_, _ = service.Create("1", "Task 1", "New", "Column-1", TaskDates{}, nil)
+	_, _ = service.Create("1", "Task 2", "Old", "Column-1", TaskDates{}, nil)
 
-	tasks, err := service.GetByColumnID("1", "Column-1")
+	tasks, err := service.GetByColumnID("1", "Column-1", "")
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -0,0 +1,272 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/storage"
+)
+
+// TeamService управляет командами и ролями, которые команды получают на досках.
+// Итоговую роль участника доски вычисляет BoardMemberRepository.
+type TeamService interface {
+	Create(userID, name string) (domain.Team, error)
+	GetAll(userID string) ([]domain.Team, error)
+
+	GetMembers(requesterID, teamID string) ([]domain.TeamMember, error)
+	AddMember(maintainerID, teamID, userID string, role domain.TeamRole) error
+	RemoveMember(maintainerID, teamID, userID string) error
+
+	// GrantBoard выдаёт команде роль на доске или меняет уже выданную
+	GrantBoard(ownerID, boardID, teamID string, role domain.BoardRole) error
+	RevokeBoard(ownerID, boardID, teamID string) error
+	GetBoardTeams(requesterID, boardID string) ([]domain.BoardTeamGrant, error)
+}
+
+type teamService struct {
+	teamRepo        storage.TeamRepository
+	teamMemberRepo  storage.TeamMemberRepository
+	boardTeamRepo   storage.BoardTeamRepository
+	boardMemberRepo storage.BoardMemberRepository
+	assigneeRepo    storage.TaskAssigneeRepository
+	userRepo        storage.UserRepository
+	policy          BoardPolicy
+	events          EventPublisher
+	generateID      func() string
+}
+
+func NewTeamService(
+	teamRepo storage.TeamRepository,
+	teamMemberRepo storage.TeamMemberRepository,
+	boardTeamRepo storage.BoardTeamRepository,
+	boardMemberRepo storage.BoardMemberRepository,
+	assigneeRepo storage.TaskAssigneeRepository,
+	userRepo storage.UserRepository,
+	policy BoardPolicy,
+	events EventPublisher,
+	generateID func() string,
+) TeamService {
+	return &teamService{
+		teamRepo:        teamRepo,
+		teamMemberRepo:  teamMemberRepo,
+		boardTeamRepo:   boardTeamRepo,
+		boardMemberRepo: boardMemberRepo,
+		assigneeRepo:    assigneeRepo,
+		userRepo:        userRepo,
+		policy:          policy,
+		events:          events,
+		generateID:      generateID,
+	}
+}
+
+func (s *teamService) Create(userID, name string) (domain.Team, error) {
+	if userID == "" || name == "" {
+		return domain.Team{}, domain.ErrInvalidInput
+	}
+
+	now := time.Now()
+
+	team := domain.Team{
+		ID:        s.generateID(),
+		Name:      name,
+		CreatedAt: now,
+	}
+
+	maintainer := domain.TeamMember{
+		ID:        s.generateID(),
+		TeamID:    team.ID,
+		UserID:    userID,
+		Role:      domain.TeamRoleMaintainer,
+		CreatedAt: now,
+	}
+
+	if err := s.teamRepo.Create(team, maintainer); err != nil {
+		return domain.Team{}, err
+	}
+
+	return team, nil
+}
+
+func (s *teamService) GetAll(userID string) ([]domain.Team, error) {
+	if userID == "" {
+		return nil, domain.ErrInvalidInput
+	}
+
+	return s.teamRepo.ListByUser(userID)
+}
+
+func (s *teamService) GetMembers(requesterID, teamID string) ([]domain.TeamMember, error) {
+	if teamID == "" {
+		return nil, domain.ErrInvalidInput
+	}
+
+	if _, err := s.requireMember(teamID, requesterID); err != nil {
+		return nil, err
+	}
+
+	return s.teamMemberRepo.GetMembers(teamID)
+}
+
+func (s *teamService) AddMember(maintainerID, teamID, userID string, role domain.TeamRole) error {
+	if teamID == "" || userID == "" {
+		return domain.ErrInvalidInput
+	}
+	if role != domain.TeamRoleMaintainer && role != domain.TeamRoleMember {
+		return domain.ErrInvalidInput
+	}
+
+	if err := s.requireMaintainer(teamID, maintainerID); err != nil {
+		return err
+	}
+
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return err
+	}
+
+	return s.teamMemberRepo.Add(domain.TeamMember{
+		ID:        s.generateID(),
+		TeamID:    teamID,
+		UserID:    userID,
+		Role:      role,
+		CreatedAt: time.Now(),
+	})
+}
+
+func (s *teamService) RemoveMember(maintainerID, teamID, userID string) error {
+	if teamID == "" || userID == "" {
+		return domain.ErrInvalidInput
+	}
+
+	if err := s.requireMaintainer(teamID, maintainerID); err != nil {
+		return err
+	}
+
+	// мейнтейнер не удаляет себя сам, чтобы команда не осталась без управления
+	if maintainerID == userID {
+		return domain.ErrForbidden
+	}
+
+	grants, err := s.boardTeamRepo.ListByTeam(teamID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.teamMemberRepo.Remove(teamID, userID); err != nil {
+		return err
+	}
+
+	// на досках команды пользователь мог потерять доступ
+	for _, grant := range grants {
+		unassignFormerMember(s.boardMemberRepo, s.assigneeRepo, grant.BoardID, userID)
+
+		s.events.Publish(newBoardEvent(domain.EventTeamMemberRemoved, grant.BoardID, maintainerID, map[string]string{
+			"team_id": teamID,
+			"user_id": userID,
+		}))
+	}
+
+	return nil
+}
+
+func (s *teamService) GrantBoard(ownerID, boardID, teamID string, role domain.BoardRole) error {
+	if boardID == "" || teamID == "" {
+		return domain.ErrInvalidInput
+	}
+
+	if _, err := s.policy.Authorize(boardID, ownerID, domain.ActionMemberManage); err != nil {
+		return err
+	}
+
+	if err := s.policy.AssignableRole(boardID, role); err != nil {
+		return err
+	}
+
+	// выдать доступ можно только своей команде
+	if _, err := s.requireMember(teamID, ownerID); err != nil {
+		return err
+	}
+
+	if err := s.boardTeamRepo.Grant(domain.BoardTeamGrant{
+		BoardID:   boardID,
+		TeamID:    teamID,
+		Role:      role,
+		GrantedBy: ownerID,
+		CreatedAt: time.Now(),
+	}); err != nil {
+		return err
+	}
+
+	s.events.Publish(newBoardEvent(domain.EventTeamGranted, boardID, ownerID, map[string]string{
+		"team_id": teamID,
+		"role":    string(role),
+	}))
+
+	return nil
+}
+
+func (s *teamService) RevokeBoard(ownerID, boardID, teamID string) error {
+	if boardID == "" || teamID == "" {
+		return domain.ErrInvalidInput
+	}
+
+	if _, err := s.policy.Authorize(boardID, ownerID, domain.ActionMemberManage); err != nil {
+		return err
+	}
+
+	members, err := s.teamMemberRepo.GetMembers(teamID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.boardTeamRepo.Revoke(boardID, teamID); err != nil {
+		return err
+	}
+
+	for _, member := range members {
+		unassignFormerMember(s.boardMemberRepo, s.assigneeRepo, boardID, member.UserID)
+	}
+
+	s.events.Publish(newBoardEvent(domain.EventTeamRevoked, boardID, ownerID, map[string]string{
+		"team_id": teamID,
+	}))
+
+	return nil
+}
+
+func (s *teamService) GetBoardTeams(requesterID, boardID string) ([]domain.BoardTeamGrant, error) {
+	if boardID == "" {
+		return nil, domain.ErrInvalidInput
+	}
+
+	if _, err := s.policy.Authorize(boardID, requesterID, domain.ActionBoardRead); err != nil {
+		return nil, err
+	}
+
+	return s.boardTeamRepo.ListByBoard(boardID)
+}
+
+func (s *teamService) requireMember(teamID, userID string) (domain.TeamRole, error) {
+	role, err := s.teamMemberRepo.GetRole(teamID, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return "", domain.ErrForbidden
+		}
+		return "", err
+	}
+
+	return role, nil
+}
+
+func (s *teamService) requireMaintainer(teamID, userID string) error {
+	role, err := s.requireMember(teamID, userID)
+	if err != nil {
+		return err
+	}
+
+	if role != domain.TeamRoleMaintainer {
+		return domain.ErrForbidden
+	}
+
+	return nil
+}
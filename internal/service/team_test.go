@@ -0,0 +1,222 @@
+package service
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/storage"
+)
+
+type fakeTeamMemberRepo struct {
+	storage.TeamMemberRepository
+	roles map[string]domain.TeamRole
+}
+
+func (r *fakeTeamMemberRepo) Add(member domain.TeamMember) error {
+	key := member.TeamID + "|" + member.UserID
+	if _, ok := r.roles[key]; ok {
+		return domain.ErrUserAlreadyExists
+	}
+	r.roles[key] = member.Role
+	return nil
+}
+
+func (r *fakeTeamMemberRepo) GetRole(teamID, userID string) (domain.TeamRole, error) {
+	role, ok := r.roles[teamID+"|"+userID]
+	if !ok {
+		return "", domain.ErrNotFound
+	}
+	return role, nil
+}
+
+func (r *fakeTeamMemberRepo) Remove(teamID, userID string) error {
+	key := teamID + "|" + userID
+	if _, ok := r.roles[key]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(r.roles, key)
+	return nil
+}
+
+func (r *fakeTeamMemberRepo) GetMembers(teamID string) ([]domain.TeamMember, error) {
+	var result []domain.TeamMember
+	for key, role := range r.roles {
+		memberTeamID, userID, _ := strings.Cut(key, "|")
+		if memberTeamID == teamID {
+			result = append(result, domain.TeamMember{TeamID: teamID, UserID: userID, Role: role})
+		}
+	}
+	return result, nil
+}
+
+type fakeTeamRepo struct {
+	storage.TeamRepository
+	members *fakeTeamMemberRepo
+}
+
+func (r *fakeTeamRepo) Create(team domain.Team, maintainer domain.TeamMember) error {
+	return r.members.Add(maintainer)
+}
+
+type fakeBoardTeamRepo struct {
+	storage.BoardTeamRepository
+	grants map[string]domain.BoardRole
+}
+
+func (r *fakeBoardTeamRepo) Grant(grant domain.BoardTeamGrant) error {
+	r.grants[grant.BoardID+"|"+grant.TeamID] = grant.Role
+	return nil
+}
+
+func (r *fakeBoardTeamRepo) Revoke(boardID, teamID string) error {
+	key := boardID + "|" + teamID
+	if _, ok := r.grants[key]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(r.grants, key)
+	return nil
+}
+
+func (r *fakeBoardTeamRepo) ListByTeam(teamID string) ([]domain.BoardTeamGrant, error) {
+	var result []domain.BoardTeamGrant
+	for key, role := range r.grants {
+		boardID, grantTeamID, _ := strings.Cut(key, "|")
+		if grantTeamID == teamID {
+			result = append(result, domain.BoardTeamGrant{BoardID: boardID, TeamID: teamID, Role: role})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].BoardID < result[j].BoardID })
+	return result, nil
+}
+
+func newTestTeamService() (TeamService, *fakeBoardTeamRepo, *recordingEvents) {
+	teamMembers := &fakeTeamMemberRepo{roles: make(map[string]domain.TeamRole)}
+	boardMembers := &fakeMemberRepo{roles: map[string]domain.BoardRole{
+		"board-1|anna":  domain.BoardRoleOwner,
+		"board-1|boris": domain.BoardRoleEditor,
+	}}
+	grants := &fakeBoardTeamRepo{grants: make(map[string]domain.BoardRole)}
+	events := &recordingEvents{}
+
+	users := &fakeUserRepo{users: map[string]domain.User{
+		"anna":  {ID: "anna"},
+		"boris": {ID: "boris"},
+		"vera":  {ID: "vera"},
+	}}
+
+	n := 0
+	generateID := func() string {
+		n++
+		return "id-" + strconv.Itoa(n)
+	}
+
+	service := NewTeamService(
+		&fakeTeamRepo{members: teamMembers},
+		teamMembers,
+		grants,
+		boardMembers,
+		&fakeAssigneeRepo{assigned: map[string]bool{}},
+		users,
+		NewBoardPolicy(boardMembers, newFakeRoleRepo(), newFakeWorkspaceMemberRepo()),
+		events,
+		generateID,
+	)
+
+	return service, grants, events
+}
+
+func TestTeamMembersManagedByMaintainers(t *testing.T) {
+	service, _, _ := newTestTeamService()
+
+	team, err := service.Create("anna", "Backend")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := service.AddMember("anna", team.ID, "boris", domain.TeamRoleMember); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := service.AddMember("boris", team.ID, "vera", domain.TeamRoleMember); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("expected ErrForbidden for plain member, got %v", err)
+	}
+	if err := service.AddMember("anna", team.ID, "vera", "owner"); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput for unknown role, got %v", err)
+	}
+	if err := service.RemoveMember("anna", team.ID, "anna"); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("expected maintainer to be unable to remove themselves, got %v", err)
+	}
+	if err := service.RemoveMember("anna", team.ID, "boris"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestTeamGrantBoard(t *testing.T) {
+	service, grants, _ := newTestTeamService()
+
+	team, _ := service.Create("anna", "Backend")
+	foreign, _ := service.Create("vera", "Design")
+
+	if err := service.GrantBoard("boris", "board-1", team.ID, domain.BoardRoleViewer); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("expected ErrForbidden for editor, got %v", err)
+	}
+	if err := service.GrantBoard("anna", "board-1", team.ID, domain.BoardRoleOwner); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput for owner role, got %v", err)
+	}
+	if err := service.GrantBoard("anna", "board-1", foreign.ID, domain.BoardRoleViewer); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("expected ErrForbidden for someone else's team, got %v", err)
+	}
+
+	if err := service.GrantBoard("anna", "board-1", team.ID, domain.BoardRoleViewer); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := service.GrantBoard("anna", "board-1", team.ID, domain.BoardRoleEditor); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if role := grants.grants["board-1|"+team.ID]; role != domain.BoardRoleEditor {
+		t.Errorf("expected grant to be updated to editor, got %s", role)
+	}
+
+	if err := service.RevokeBoard("anna", "board-1", team.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := service.RevokeBoard("anna", "board-1", team.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound on second revoke, got %v", err)
+	}
+}
+
+func TestTeamMemberRemovalNotifiesTeamBoards(t *testing.T) {
+	service, _, events := newTestTeamService()
+
+	team, _ := service.Create("anna", "Backend")
+	if err := service.AddMember("anna", team.ID, "vera", domain.TeamRoleMember); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := service.GrantBoard("anna", "board-1", team.ID, domain.BoardRoleViewer); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := service.RemoveMember("anna", team.ID, "vera"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// по этому событию потоки доски перепроверяют доступ vera
+	last := events.events[len(events.events)-1]
+	if last.Type != domain.EventTeamMemberRemoved || last.BoardID != "board-1" {
+		t.Errorf("expected team.member_removed on board-1, got %+v", last)
+	}
+}
+
+func TestBoardRoleRank(t *testing.T) {
+	// порядок, по которому выбирается итоговая роль из прямой и командных
+	order := []domain.BoardRole{domain.BoardRoleViewer, "triager", domain.BoardRoleEditor, domain.BoardRoleOwner}
+
+	for i := 1; i < len(order); i++ {
+		if order[i-1].Rank() >= order[i].Rank() {
+			t.Errorf("expected %s to rank below %s", order[i-1], order[i])
+		}
+	}
+}
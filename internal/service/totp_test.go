@@ -0,0 +1,68 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// тестовые векторы из приложения B RFC 6238 (SHA-1, 8 цифр)
+func TestHOTPMatchesRFC6238Vectors(t *testing.T) {
+	key := []byte("12345678901234567890")
+
+	cases := []struct {
+		unix int64
+		want string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+
+	for _, tc := range cases {
+		got := hotp(key, uint64(tc.unix/30), 8)
+		if got != tc.want {
+			t.Errorf("t=%d: expected %s, got %s", tc.unix, tc.want, got)
+		}
+	}
+}
+
+func TestValidateTOTPAcceptsAdjacentSteps(t *testing.T) {
+	secret := totpEncoding.EncodeToString([]byte("12345678901234567890"))
+	now := time.Unix(1111111111, 0)
+
+	// последние 6 цифр вектора 14050471
+	counter, ok := validateTOTP(secret, "050471", now)
+	if !ok {
+		t.Error("expected current code to be valid")
+	}
+	if counter != 1111111111/30 {
+		t.Errorf("expected step %d, got %d", 1111111111/30, counter)
+	}
+
+	if next, ok := validateTOTP(secret, "050471", now.Add(totpPeriod)); !ok || next != counter {
+		t.Error("expected code from previous step to be valid with its own step")
+	}
+
+	if _, ok := validateTOTP(secret, "050471", now.Add(3*totpPeriod)); ok {
+		t.Error("expected stale code to be rejected")
+	}
+
+	if _, ok := validateTOTP(secret, "50471", now); ok {
+		t.Error("expected short code to be rejected")
+	}
+}
+
+func TestTOTPURI(t *testing.T) {
+	uri := totpURI("user@example.com", "JBSWY3DPEHPK3PXP")
+
+	if !strings.HasPrefix(uri, "otpauth://totp/TasksStream:user@example.com?") {
+		t.Errorf("unexpected label: %s", uri)
+	}
+	if !strings.Contains(uri, "secret=JBSWY3DPEHPK3PXP") || !strings.Contains(uri, "issuer=TasksStream") {
+		t.Errorf("missing parameters: %s", uri)
+	}
+}
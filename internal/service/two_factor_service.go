@@ -0,0 +1,263 @@
+package service
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/storage"
+)
+
+const recoveryCodeCount = 10
+
+type TwoFactorService interface {
+	// Setup выдаёт новый секрет; 2FA включается только после Verify
+	Setup(userID string) (TwoFactorSetup, error)
+	// Verify подтверждает секрет кодом из приложения, включает 2FA и
+	// возвращает одноразовые коды восстановления — они показываются один раз
+	Verify(userID, code string) ([]string, error)
+	// Disable ограничивает подбор кода тем же LoginLimiter, что и вход
+	Disable(userID, code string, client ClientInfo) error
+}
+
+type TwoFactorSetup struct {
+	Secret string
+	URI    string
+}
+
+type twoFactorService struct {
+	userRepo         storage.UserRepository
+	recoveryCodeRepo storage.RecoveryCodeRepository
+	hasher           PasswordHasher
+	limiter          LoginLimiter
+	generateID       func() string
+}
+
+func NewTwoFactorService(
+	userRepo storage.UserRepository,
+	recoveryCodeRepo storage.RecoveryCodeRepository,
+	hasher PasswordHasher,
+	limiter LoginLimiter,
+	generateID func() string,
+) TwoFactorService {
+	return &twoFactorService{
+		userRepo:         userRepo,
+		recoveryCodeRepo: recoveryCodeRepo,
+		hasher:           hasher,
+		limiter:          limiter,
+		generateID:       generateID,
+	}
+}
+
+func (s *twoFactorService) Setup(userID string) (TwoFactorSetup, error) {
+	if userID == "" {
+		return TwoFactorSetup{}, domain.ErrInvalidInput
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return TwoFactorSetup{}, err
+	}
+
+	if user.TOTPEnabled {
+		return TwoFactorSetup{}, domain.ErrConflict
+	}
+
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		return TwoFactorSetup{}, err
+	}
+
+	if err := s.userRepo.UpdateTOTP(user.ID, secret, false); err != nil {
+		return TwoFactorSetup{}, err
+	}
+
+	return TwoFactorSetup{
+		Secret: secret,
+		URI:    totpURI(user.Email, secret),
+	}, nil
+}
+
+func (s *twoFactorService) Verify(userID, code string) ([]string, error) {
+	if userID == "" || code == "" {
+		return nil, domain.ErrInvalidInput
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.TOTPEnabled {
+		return nil, domain.ErrConflict
+	}
+	if user.TOTPSecret == "" {
+		return nil, domain.ErrInvalidInput
+	}
+
+	if err := useTOTP(s.userRepo, user, code); err != nil {
+		return nil, err
+	}
+
+	codes, stored, err := s.newRecoveryCodes(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.recoveryCodeRepo.Replace(user.ID, stored); err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.UpdateTOTP(user.ID, user.TOTPSecret, true); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+func (s *twoFactorService) Disable(userID, code string, client ClientInfo) error {
+	if userID == "" || code == "" {
+		return domain.ErrInvalidInput
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if !user.TOTPEnabled {
+		return domain.ErrConflict
+	}
+
+	// украденный access-токен не должен позволять перебрать код и снять 2FA
+	if err := s.limiter.Check(user.Email, client.IP); err != nil {
+		return err
+	}
+
+	if err := verifySecondFactor(user, code, s.userRepo, s.recoveryCodeRepo, s.hasher); err != nil {
+		if errors.Is(err, domain.ErrInvalidCredentials) {
+			return loginFailed(s.limiter, user.Email, client)
+		}
+		return err
+	}
+
+	if err := s.limiter.Success(user.Email, client.IP); err != nil {
+		log.Printf("reset login attempts for %s: %v", user.ID, err)
+	}
+
+	if err := s.recoveryCodeRepo.DeleteAllForUser(user.ID); err != nil {
+		return err
+	}
+
+	return s.userRepo.UpdateTOTP(user.ID, "", false)
+}
+
+func (s *twoFactorService) newRecoveryCodes(userID string) ([]string, []domain.RecoveryCode, error) {
+	now := time.Now()
+
+	codes := make([]string, 0, recoveryCodeCount)
+	stored := make([]domain.RecoveryCode, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		hash, err := s.hasher.Hash(normalizeRecoveryCode(code))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		codes = append(codes, code)
+		stored = append(stored, domain.RecoveryCode{
+			ID:        s.generateID(),
+			UserID:    userID,
+			CodeHash:  hash,
+			CreatedAt: now,
+		})
+	}
+
+	return codes, stored, nil
+}
+
+// verifySecondFactor принимает либо текущий TOTP-код, либо один из
+// неиспользованных кодов восстановления; использованный код гасится.
+func verifySecondFactor(
+	user domain.User,
+	code string,
+	userRepo storage.UserRepository,
+	recoveryCodeRepo storage.RecoveryCodeRepository,
+	hasher PasswordHasher,
+) error {
+	code = strings.TrimSpace(code)
+
+	if len(code) == totpDigits {
+		return useTOTP(userRepo, user, code)
+	}
+
+	normalized := normalizeRecoveryCode(code)
+	if normalized == "" {
+		return domain.ErrInvalidCredentials
+	}
+
+	stored, err := recoveryCodeRepo.ListUnused(user.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, candidate := range stored {
+		if hasher.Compare(candidate.CodeHash, normalized) != nil {
+			continue
+		}
+
+		if err := recoveryCodeRepo.MarkUsed(candidate.ID, time.Now()); err != nil {
+			if errors.Is(err, domain.ErrConflict) {
+				return domain.ErrInvalidCredentials
+			}
+			return err
+		}
+		return nil
+	}
+
+	return domain.ErrInvalidCredentials
+}
+
+// useTOTP проверяет код и запоминает его шаг: код, перехваченный по дороге,
+// нельзя предъявить второй раз, пока он ещё действует
+func useTOTP(userRepo storage.UserRepository, user domain.User, code string) error {
+	counter, ok := validateTOTP(user.TOTPSecret, code, time.Now())
+	if !ok {
+		return domain.ErrInvalidCredentials
+	}
+
+	if err := userRepo.UseTOTPCounter(user.ID, counter); err != nil {
+		if errors.Is(err, domain.ErrConflict) {
+			return domain.ErrInvalidCredentials
+		}
+		return err
+	}
+
+	return nil
+}
+
+// коды выглядят как XXXXX-XXXXX; дефис и регистр при вводе не важны
+func generateRecoveryCode() (string, error) {
+	b := make([]byte, 7)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	encoded := totpEncoding.EncodeToString(b)[:10]
+	return fmt.Sprintf("%s-%s", encoded[:5], encoded[5:]), nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ReplaceAll(code, "-", "")
+	code = strings.ReplaceAll(code, " ", "")
+	return strings.ToUpper(code)
+}
@@ -0,0 +1,317 @@
+package service
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+func (r *fakeUserRepo) UpdateTOTP(userID, secret string, enabled bool) error {
+	user, ok := r.users[userID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	user.TOTPSecret = secret
+	user.TOTPEnabled = enabled
+	r.users[userID] = user
+	return nil
+}
+
+func (r *fakeUserRepo) UseTOTPCounter(userID string, counter int64) error {
+	if r.totpCounters == nil {
+		r.totpCounters = make(map[string]int64)
+	}
+	if last, ok := r.totpCounters[userID]; ok && last >= counter {
+		return domain.ErrConflict
+	}
+	r.totpCounters[userID] = counter
+	return nil
+}
+
+type fakeRecoveryCodeRepo struct {
+	codes map[string]domain.RecoveryCode
+}
+
+func (r *fakeRecoveryCodeRepo) Replace(userID string, codes []domain.RecoveryCode) error {
+	if err := r.DeleteAllForUser(userID); err != nil {
+		return err
+	}
+	for _, code := range codes {
+		r.codes[code.ID] = code
+	}
+	return nil
+}
+
+func (r *fakeRecoveryCodeRepo) ListUnused(userID string) ([]domain.RecoveryCode, error) {
+	var result []domain.RecoveryCode
+	for _, code := range r.codes {
+		if code.UserID == userID && code.UsedAt == nil {
+			result = append(result, code)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeRecoveryCodeRepo) MarkUsed(id string, usedAt time.Time) error {
+	code, ok := r.codes[id]
+	if !ok || code.UsedAt != nil {
+		return domain.ErrConflict
+	}
+	code.UsedAt = &usedAt
+	r.codes[id] = code
+	return nil
+}
+
+func (r *fakeRecoveryCodeRepo) DeleteAllForUser(userID string) error {
+	for id, code := range r.codes {
+		if code.UserID == userID {
+			delete(r.codes, id)
+		}
+	}
+	return nil
+}
+
+// totpCode возвращает код шага, отстоящего от текущего на offset; offset 0 и 1
+// остаются в окне totpSkew, даже если шаг сменится посреди теста
+func totpCode(t *testing.T, secret string, offset int64) string {
+	t.Helper()
+
+	key, err := totpEncoding.DecodeString(secret)
+	if err != nil {
+		t.Fatalf("decode secret: %v", err)
+	}
+
+	counter := time.Now().Unix()/int64(totpPeriod.Seconds()) + offset
+	return hotp(key, uint64(counter), totpDigits)
+}
+
+func newTestTwoFactorService(auth testAuth) TwoFactorService {
+	n := 0
+	return NewTwoFactorService(auth.users, auth.recoveryCodes, plainHasher{}, auth.service.limiter, func() string {
+		n++
+		return "code-" + strconv.Itoa(n)
+	})
+}
+
+// enableTwoFactor включает 2FA пользователю user-1, потратив код текущего шага
+func enableTwoFactor(t *testing.T, auth testAuth) (secret string, recoveryCodes []string) {
+	t.Helper()
+
+	service := newTestTwoFactorService(auth)
+
+	setup, err := service.Setup("user-1")
+	if err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+
+	recoveryCodes, err = service.Verify("user-1", totpCode(t, setup.Secret, 0))
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+
+	return setup.Secret, recoveryCodes
+}
+
+func TestTwoFactorSetupAndVerify(t *testing.T) {
+	auth := newTestAuthService()
+	service := newTestTwoFactorService(auth)
+
+	if _, err := service.Verify("user-1", "123456"); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput before setup, got %v", err)
+	}
+
+	setup, err := service.Setup("user-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(setup.URI, "secret="+setup.Secret) {
+		t.Errorf("expected secret in URI, got %s", setup.URI)
+	}
+	if user := auth.users.users["user-1"]; user.TOTPSecret != setup.Secret || user.TOTPEnabled {
+		t.Errorf("expected pending secret, got %+v", user)
+	}
+
+	if _, err := service.Verify("user-1", "000000"); !errors.Is(err, domain.ErrInvalidCredentials) {
+		t.Errorf("expected ErrInvalidCredentials for wrong code, got %v", err)
+	}
+
+	codes, err := service.Verify("user-1", totpCode(t, setup.Secret, 0))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(codes) != recoveryCodeCount {
+		t.Errorf("expected %d recovery codes, got %d", recoveryCodeCount, len(codes))
+	}
+	if !auth.users.users["user-1"].TOTPEnabled {
+		t.Error("expected 2FA to be enabled")
+	}
+
+	stored, _ := auth.recoveryCodes.ListUnused("user-1")
+	if len(stored) != recoveryCodeCount {
+		t.Errorf("expected %d stored codes, got %d", recoveryCodeCount, len(stored))
+	}
+	for _, code := range stored {
+		for _, plain := range codes {
+			if code.CodeHash == plain {
+				t.Fatal("recovery codes must be stored hashed")
+			}
+		}
+	}
+
+	if _, err := service.Setup("user-1"); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("expected ErrConflict for repeated setup, got %v", err)
+	}
+	if _, err := service.Verify("user-1", totpCode(t, setup.Secret, 1)); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("expected ErrConflict for repeated verify, got %v", err)
+	}
+}
+
+func TestLoginTwoFactor(t *testing.T) {
+	auth := newTestAuthService()
+	secret, recoveryCodes := enableTwoFactor(t, auth)
+
+	challenge := func() string {
+		t.Helper()
+		result, err := auth.service.Login("user@example.com", testPassword, ClientInfo{IP: "10.0.0.1"})
+		if err != nil {
+			t.Fatalf("login: %v", err)
+		}
+		if result.ChallengeToken == "" || result.Tokens.AccessToken != "" {
+			t.Fatalf("expected only a challenge token, got %+v", result)
+		}
+		return result.ChallengeToken
+	}
+
+	nextCode := totpCode(t, secret, 1)
+
+	cases := []struct {
+		name      string
+		challenge string
+		code      string
+		wantErr   error
+	}{
+		{"code used to enable 2FA", challenge(), totpCode(t, secret, 0), domain.ErrInvalidCredentials},
+		{"wrong code", challenge(), "000000", domain.ErrInvalidCredentials},
+		{"bad challenge", "access|user-1|1", nextCode, domain.ErrInvalidCredentials},
+		{"fresh code", challenge(), nextCode, nil},
+		{"replayed code", challenge(), nextCode, domain.ErrInvalidCredentials},
+		{"recovery code", challenge(), strings.ToLower(recoveryCodes[0]), nil},
+		{"used recovery code", challenge(), recoveryCodes[0], domain.ErrInvalidCredentials},
+		{"another recovery code", challenge(), recoveryCodes[1], nil},
+	}
+
+	for _, tc := range cases {
+		tokens, err := auth.service.LoginTwoFactor(tc.challenge, tc.code, ClientInfo{IP: "10.0.0.1"})
+		if !errors.Is(err, tc.wantErr) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.wantErr, err)
+		}
+		if err == nil && tokens.RefreshToken == "" {
+			t.Errorf("%s: expected tokens", tc.name)
+		}
+	}
+
+	// 2FA отключили, пока challenge-токен был жив
+	pending := challenge()
+	if err := auth.users.UpdateTOTP("user-1", "", false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := auth.service.LoginTwoFactor(pending, recoveryCodes[2], ClientInfo{}); !errors.Is(err, domain.ErrInvalidCredentials) {
+		t.Errorf("expected ErrInvalidCredentials after 2FA was disabled, got %v", err)
+	}
+}
+
+func TestDisableTwoFactor(t *testing.T) {
+	auth := newTestAuthService()
+	_, recoveryCodes := enableTwoFactor(t, auth)
+	service := newTestTwoFactorService(auth)
+
+	if err := service.Disable("user-1", "000000", ClientInfo{}); !errors.Is(err, domain.ErrInvalidCredentials) {
+		t.Errorf("expected ErrInvalidCredentials, got %v", err)
+	}
+	if !auth.users.users["user-1"].TOTPEnabled {
+		t.Fatal("2FA must stay enabled after a wrong code")
+	}
+
+	if err := service.Disable("user-1", recoveryCodes[0], ClientInfo{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if user := auth.users.users["user-1"]; user.TOTPEnabled || user.TOTPSecret != "" {
+		t.Errorf("expected 2FA to be cleared, got %+v", user)
+	}
+	if len(auth.recoveryCodes.codes) != 0 {
+		t.Errorf("expected recovery codes to be deleted, got %d", len(auth.recoveryCodes.codes))
+	}
+
+	if err := service.Disable("user-1", recoveryCodes[1], ClientInfo{}); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("expected ErrConflict when 2FA is off, got %v", err)
+	}
+
+	// после отключения вход снова по одному паролю
+	result, err := auth.service.Login("user@example.com", testPassword, ClientInfo{})
+	if err != nil || result.Tokens.AccessToken == "" {
+		t.Errorf("expected plain login, got %+v, %v", result, err)
+	}
+}
+
+func TestDisableTwoFactorIsRateLimited(t *testing.T) {
+	auth := newTestAuthService()
+	_, recoveryCodes := enableTwoFactor(t, auth)
+	service := newTestTwoFactorService(auth)
+	client := ClientInfo{IP: "10.0.0.1"}
+
+	for i := 0; i < loginAccountFreeAttempts-1; i++ {
+		if err := service.Disable("user-1", "000000", client); !errors.Is(err, domain.ErrInvalidCredentials) {
+			t.Fatalf("attempt %d: expected ErrInvalidCredentials, got %v", i+1, err)
+		}
+	}
+	if err := service.Disable("user-1", "000000", client); !errors.Is(err, domain.ErrTooManyRequests) {
+		t.Fatalf("expected rate limit on the last free attempt, got %v", err)
+	}
+
+	// во время блокировки не принимается даже верный код
+	if err := service.Disable("user-1", recoveryCodes[0], client); !errors.Is(err, domain.ErrTooManyRequests) {
+		t.Errorf("expected ErrTooManyRequests with a valid code, got %v", err)
+	}
+	if !auth.users.users["user-1"].TOTPEnabled {
+		t.Error("2FA must stay enabled while locked")
+	}
+
+	// подбор кода блокирует и вход по паролю того же аккаунта
+	if _, err := auth.service.Login("user@example.com", testPassword, client); !errors.Is(err, domain.ErrTooManyRequests) {
+		t.Errorf("expected login to be locked too, got %v", err)
+	}
+}
+
+// вход через внешнего провайдера не заменяет второй фактор
+func TestSessionStarterRequiresSecondFactor(t *testing.T) {
+	auth := newTestAuthService()
+	starter := NewSessionStarter(auth.refreshTokens, auth.sessions, &fakeJWT{}, func() string { return "session-1" })
+
+	result, err := starter.StartSession(auth.users.users["user-1"], ClientInfo{})
+	if err != nil || result.Tokens.RefreshToken == "" || result.ChallengeToken != "" {
+		t.Fatalf("expected tokens without 2FA, got %+v, %v", result, err)
+	}
+
+	secret, _ := enableTwoFactor(t, auth)
+	sessions := len(auth.sessions.sessions)
+
+	result, err = starter.StartSession(auth.users.users["user-1"], ClientInfo{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ChallengeToken == "" || result.Tokens.AccessToken != "" {
+		t.Fatalf("expected only a challenge token, got %+v", result)
+	}
+	if len(auth.sessions.sessions) != sessions {
+		t.Error("session must not be started before the second factor")
+	}
+
+	if _, err := auth.service.LoginTwoFactor(result.ChallengeToken, totpCode(t, secret, 1), ClientInfo{}); err != nil {
+		t.Errorf("expected challenge to be accepted by LoginTwoFactor, got %v", err)
+	}
+}
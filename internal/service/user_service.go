@@ -0,0 +1,131 @@
+package service
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/storage"
+)
+
+const (
+	maxDisplayNameLength = 100
+	maxAvatarURLLength   = 2048
+
+	userSearchMinQuery = 2
+	userSearchLimit    = 20
+)
+
+// BCP 47 в упрощённом виде: "ru", "en-US", "zh-Hant-TW"
+var localePattern = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+type UserService interface {
+	GetMe(userID string) (domain.User, error)
+	UpdateProfile(userID string, input ProfileInput) (domain.User, error)
+	// Search ищет только среди тех, с кем у пользователя есть общая доска
+	Search(requesterID, query string) ([]domain.UserProfile, error)
+}
+
+type ProfileInput struct {
+	DisplayName string
+	AvatarURL   string
+	Timezone    string
+	Locale      string
+}
+
+type userService struct {
+	userRepo storage.UserRepository
+}
+
+func NewUserService(userRepo storage.UserRepository) UserService {
+	return &userService{userRepo: userRepo}
+}
+
+func (s *userService) GetMe(userID string) (domain.User, error) {
+	if userID == "" {
+		return domain.User{}, domain.ErrInvalidInput
+	}
+
+	return s.userRepo.GetByID(userID)
+}
+
+func (s *userService) UpdateProfile(userID string, input ProfileInput) (domain.User, error) {
+	if userID == "" {
+		return domain.User{}, domain.ErrInvalidInput
+	}
+
+	input.DisplayName = strings.TrimSpace(input.DisplayName)
+	input.AvatarURL = strings.TrimSpace(input.AvatarURL)
+
+	if err := validateProfile(input); err != nil {
+		return domain.User{}, err
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	user.DisplayName = input.DisplayName
+	user.AvatarURL = input.AvatarURL
+	user.Timezone = input.Timezone
+	user.Locale = input.Locale
+
+	if err := s.userRepo.UpdateProfile(user); err != nil {
+		return domain.User{}, err
+	}
+
+	return user, nil
+}
+
+func (s *userService) Search(requesterID, query string) ([]domain.UserProfile, error) {
+	query = strings.TrimSpace(query)
+
+	if requesterID == "" || utf8.RuneCountInString(query) < userSearchMinQuery {
+		return nil, domain.ErrInvalidInput
+	}
+
+	users, err := s.userRepo.SearchCoMembers(requesterID, query, userSearchLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.UserProfile, 0, len(users))
+	for _, u := range users {
+		result = append(result, u.Profile())
+	}
+
+	return result, nil
+}
+
+// пустые значения допустимы и означают «не задано»
+func validateProfile(input ProfileInput) error {
+	if utf8.RuneCountInString(input.DisplayName) > maxDisplayNameLength {
+		return domain.ErrInvalidInput
+	}
+
+	if input.AvatarURL != "" {
+		if len(input.AvatarURL) > maxAvatarURLLength {
+			return domain.ErrInvalidInput
+		}
+		u, err := url.Parse(input.AvatarURL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return domain.ErrInvalidInput
+		}
+	}
+
+	if input.Timezone != "" {
+		if _, err := time.LoadLocation(input.Timezone); err != nil {
+			return domain.ErrInvalidInput
+		}
+	}
+
+	if input.Locale != "" && !localePattern.MatchString(input.Locale) {
+		return domain.ErrInvalidInput
+	}
+
+	return nil
+}
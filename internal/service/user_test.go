@@ -0,0 +1,33 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+func TestValidateProfile(t *testing.T) {
+	cases := []struct {
+		name  string
+		input ProfileInput
+		ok    bool
+	}{
+		{"empty", ProfileInput{}, true},
+		{"full", ProfileInput{"Анна", "https://cdn.example.com/a.png", "Europe/Moscow", "ru-RU"}, true},
+		{"javascript avatar", ProfileInput{AvatarURL: "javascript:alert(1)"}, false},
+		{"relative avatar", ProfileInput{AvatarURL: "/a.png"}, false},
+		{"unknown timezone", ProfileInput{Timezone: "Mars/Olympus"}, false},
+		{"bad locale", ProfileInput{Locale: "русский"}, false},
+	}
+
+	for _, tc := range cases {
+		err := validateProfile(tc.input)
+		if tc.ok && err != nil {
+			t.Errorf("%s: unexpected error %v", tc.name, err)
+		}
+		if !tc.ok && !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("%s: expected ErrInvalidInput, got %v", tc.name, err)
+		}
+	}
+}
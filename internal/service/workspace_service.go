@@ -0,0 +1,286 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/storage"
+)
+
+// WorkspaceService управляет пространствами: их составом и досками.
+// Права на сами доски по-прежнему проверяет BoardPolicy.
+type WorkspaceService interface {
+	Create(userID, name string) (domain.Workspace, error)
+	GetAll(userID string) ([]domain.Workspace, error)
+
+	GetMembers(requesterID, workspaceID string) ([]domain.WorkspaceMember, error)
+	AddMember(adminID, workspaceID, userID string, role domain.WorkspaceRole) error
+	RemoveMember(adminID, workspaceID, userID string) error
+
+	GetBoards(userID, workspaceID string) ([]domain.Board, error)
+	CreateBoard(userID, workspaceID, name string) (domain.Board, error)
+	// MoveBoard переносит доску в пространство, пустой workspaceID делает её личной
+	MoveBoard(userID, boardID, workspaceID string) (domain.Board, error)
+}
+
+type workspaceService struct {
+	workspaceRepo       storage.WorkspaceRepository
+	workspaceMemberRepo storage.WorkspaceMemberRepository
+	boardRepo           storage.BoardRepository
+	boardMemberRepo     storage.BoardMemberRepository
+	assigneeRepo        storage.TaskAssigneeRepository
+	userRepo            storage.UserRepository
+	policy              BoardPolicy
+	events              EventPublisher
+	generateID          func() string
+}
+
+func NewWorkspaceService(
+	workspaceRepo storage.WorkspaceRepository,
+	workspaceMemberRepo storage.WorkspaceMemberRepository,
+	boardRepo storage.BoardRepository,
+	boardMemberRepo storage.BoardMemberRepository,
+	assigneeRepo storage.TaskAssigneeRepository,
+	userRepo storage.UserRepository,
+	policy BoardPolicy,
+	events EventPublisher,
+	generateID func() string,
+) WorkspaceService {
+	return &workspaceService{
+		workspaceRepo:       workspaceRepo,
+		workspaceMemberRepo: workspaceMemberRepo,
+		boardRepo:           boardRepo,
+		boardMemberRepo:     boardMemberRepo,
+		assigneeRepo:        assigneeRepo,
+		userRepo:            userRepo,
+		policy:              policy,
+		events:              events,
+		generateID:          generateID,
+	}
+}
+
+func (s *workspaceService) Create(userID, name string) (domain.Workspace, error) {
+	if userID == "" || name == "" {
+		return domain.Workspace{}, domain.ErrInvalidInput
+	}
+
+	now := time.Now()
+
+	workspace := domain.Workspace{
+		ID:        s.generateID(),
+		Name:      name,
+		CreatedAt: now,
+	}
+
+	admin := domain.WorkspaceMember{
+		ID:          s.generateID(),
+		WorkspaceID: workspace.ID,
+		UserID:      userID,
+		Role:        domain.WorkspaceRoleAdmin,
+		CreatedAt:   now,
+	}
+
+	if err := s.workspaceRepo.Create(workspace, admin); err != nil {
+		return domain.Workspace{}, err
+	}
+
+	return workspace, nil
+}
+
+func (s *workspaceService) GetAll(userID string) ([]domain.Workspace, error) {
+	if userID == "" {
+		return nil, domain.ErrInvalidInput
+	}
+
+	return s.workspaceRepo.ListByUser(userID)
+}
+
+func (s *workspaceService) GetMembers(requesterID, workspaceID string) ([]domain.WorkspaceMember, error) {
+	if workspaceID == "" {
+		return nil, domain.ErrInvalidInput
+	}
+
+	if _, err := s.requireMember(workspaceID, requesterID); err != nil {
+		return nil, err
+	}
+
+	return s.workspaceMemberRepo.GetMembers(workspaceID)
+}
+
+func (s *workspaceService) AddMember(adminID, workspaceID, userID string, role domain.WorkspaceRole) error {
+	if workspaceID == "" || userID == "" {
+		return domain.ErrInvalidInput
+	}
+	if role != domain.WorkspaceRoleAdmin && role != domain.WorkspaceRoleMember {
+		return domain.ErrInvalidInput
+	}
+
+	if err := s.requireAdmin(workspaceID, adminID); err != nil {
+		return err
+	}
+
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return err
+	}
+
+	return s.workspaceMemberRepo.Add(domain.WorkspaceMember{
+		ID:          s.generateID(),
+		WorkspaceID: workspaceID,
+		UserID:      userID,
+		Role:        role,
+		CreatedAt:   time.Now(),
+	})
+}
+
+func (s *workspaceService) RemoveMember(adminID, workspaceID, userID string) error {
+	if workspaceID == "" || userID == "" {
+		return domain.ErrInvalidInput
+	}
+
+	if err := s.requireAdmin(workspaceID, adminID); err != nil {
+		return err
+	}
+
+	// администратор не удаляет себя сам, поэтому в пространстве
+	// всегда остаётся хотя бы один администратор
+	if adminID == userID {
+		return domain.ErrForbidden
+	}
+
+	role, err := s.workspaceMemberRepo.GetRole(workspaceID, userID)
+	if err != nil {
+		return err
+	}
+
+	// доски пространства администратору доступны без членства, поэтому
+	// их потоки событий должны перепроверить его доступ после удаления
+	var boards []domain.Board
+	if role == domain.WorkspaceRoleAdmin {
+		boards, err = s.boardRepo.ListByWorkspace(workspaceID, userID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := s.workspaceMemberRepo.Remove(workspaceID, userID); err != nil {
+		return err
+	}
+
+	for _, board := range boards {
+		unassignFormerMember(s.boardMemberRepo, s.assigneeRepo, board.ID, userID)
+
+		s.events.Publish(newBoardEvent(domain.EventWorkspaceMemberRemoved, board.ID, adminID, map[string]string{
+			"workspace_id": workspaceID,
+			"user_id":      userID,
+		}))
+	}
+
+	return nil
+}
+
+func (s *workspaceService) GetBoards(userID, workspaceID string) ([]domain.Board, error) {
+	if workspaceID == "" {
+		return nil, domain.ErrInvalidInput
+	}
+
+	if _, err := s.requireMember(workspaceID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.boardRepo.ListByWorkspace(workspaceID, userID)
+}
+
+func (s *workspaceService) CreateBoard(userID, workspaceID, name string) (domain.Board, error) {
+	if workspaceID == "" || name == "" {
+		return domain.Board{}, domain.ErrInvalidInput
+	}
+
+	if _, err := s.requireMember(workspaceID, userID); err != nil {
+		return domain.Board{}, err
+	}
+
+	board := domain.Board{
+		ID:          s.generateID(),
+		Name:        name,
+		WorkspaceID: &workspaceID,
+		CreatedAt:   time.Now(),
+	}
+
+	created, err := s.boardRepo.Create(board)
+	if err != nil {
+		return domain.Board{}, err
+	}
+
+	member := domain.BoardMember{
+		ID:        s.generateID(),
+		BoardID:   created.ID,
+		UserID:    userID,
+		Role:      domain.BoardRoleOwner,
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.boardMemberRepo.Add(member); err != nil {
+		return domain.Board{}, err
+	}
+
+	return created, nil
+}
+
+func (s *workspaceService) MoveBoard(userID, boardID, workspaceID string) (domain.Board, error) {
+	if boardID == "" {
+		return domain.Board{}, domain.ErrInvalidInput
+	}
+
+	// переносить доску может тот, кто может её удалить:
+	// владелец или администратор текущего пространства
+	if _, err := s.policy.Authorize(boardID, userID, domain.ActionBoardDelete); err != nil {
+		return domain.Board{}, err
+	}
+
+	var target *string
+	if workspaceID != "" {
+		if _, err := s.requireMember(workspaceID, userID); err != nil {
+			return domain.Board{}, err
+		}
+		target = &workspaceID
+	}
+
+	if err := s.boardRepo.SetWorkspace(boardID, target); err != nil {
+		return domain.Board{}, err
+	}
+
+	board, err := s.boardRepo.GetByID(boardID)
+	if err != nil {
+		return domain.Board{}, err
+	}
+
+	s.events.Publish(newBoardEvent(domain.EventBoardUpdated, boardID, userID, board))
+
+	return board, nil
+}
+
+func (s *workspaceService) requireMember(workspaceID, userID string) (domain.WorkspaceRole, error) {
+	role, err := s.workspaceMemberRepo.GetRole(workspaceID, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return "", domain.ErrForbidden
+		}
+		return "", err
+	}
+
+	return role, nil
+}
+
+func (s *workspaceService) requireAdmin(workspaceID, userID string) error {
+	role, err := s.requireMember(workspaceID, userID)
+	if err != nil {
+		return err
+	}
+
+	if role != domain.WorkspaceRoleAdmin {
+		return domain.ErrForbidden
+	}
+
+	return nil
+}
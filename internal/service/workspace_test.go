@@ -0,0 +1,258 @@
+package service
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+	"github.com/ovk741/TasksStream/internal/storage"
+)
+
+type fakeWorkspaceMemberRepo struct {
+	storage.WorkspaceMemberRepository
+	roles map[string]domain.WorkspaceRole
+	// доска -> пространство
+	boards map[string]string
+}
+
+func newFakeWorkspaceMemberRepo() *fakeWorkspaceMemberRepo {
+	return &fakeWorkspaceMemberRepo{
+		roles:  make(map[string]domain.WorkspaceRole),
+		boards: make(map[string]string),
+	}
+}
+
+func (r *fakeWorkspaceMemberRepo) Add(member domain.WorkspaceMember) error {
+	key := member.WorkspaceID + "|" + member.UserID
+	if _, ok := r.roles[key]; ok {
+		return domain.ErrUserAlreadyExists
+	}
+	r.roles[key] = member.Role
+	return nil
+}
+
+func (r *fakeWorkspaceMemberRepo) GetRole(workspaceID, userID string) (domain.WorkspaceRole, error) {
+	role, ok := r.roles[workspaceID+"|"+userID]
+	if !ok {
+		return "", domain.ErrNotFound
+	}
+	return role, nil
+}
+
+func (r *fakeWorkspaceMemberRepo) GetRoleByBoard(boardID, userID string) (domain.WorkspaceRole, error) {
+	workspaceID, ok := r.boards[boardID]
+	if !ok {
+		return "", domain.ErrNotFound
+	}
+	return r.GetRole(workspaceID, userID)
+}
+
+func (r *fakeWorkspaceMemberRepo) Remove(workspaceID, userID string) error {
+	key := workspaceID + "|" + userID
+	if _, ok := r.roles[key]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(r.roles, key)
+	return nil
+}
+
+type fakeWorkspaceRepo struct {
+	storage.WorkspaceRepository
+	members *fakeWorkspaceMemberRepo
+}
+
+func (r *fakeWorkspaceRepo) Create(workspace domain.Workspace, admin domain.WorkspaceMember) error {
+	return r.members.Add(admin)
+}
+
+type fakeWorkspaceBoardRepo struct {
+	storage.BoardRepository
+	boards  map[string]domain.Board
+	members *fakeWorkspaceMemberRepo
+}
+
+func (r *fakeWorkspaceBoardRepo) Create(board domain.Board) (domain.Board, error) {
+	r.boards[board.ID] = board
+	if board.WorkspaceID != nil {
+		r.members.boards[board.ID] = *board.WorkspaceID
+	}
+	return board, nil
+}
+
+func (r *fakeWorkspaceBoardRepo) GetByID(boardID string) (domain.Board, error) {
+	board, ok := r.boards[boardID]
+	if !ok {
+		return domain.Board{}, domain.ErrNotFound
+	}
+	return board, nil
+}
+
+func (r *fakeWorkspaceBoardRepo) SetWorkspace(boardID string, workspaceID *string) error {
+	board, ok := r.boards[boardID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	board.WorkspaceID = workspaceID
+	r.boards[boardID] = board
+
+	delete(r.members.boards, boardID)
+	if workspaceID != nil {
+		r.members.boards[boardID] = *workspaceID
+	}
+	return nil
+}
+
+func (r *fakeWorkspaceBoardRepo) ListByWorkspace(workspaceID, userID string) ([]domain.Board, error) {
+	var result []domain.Board
+	for _, board := range r.boards {
+		if board.WorkspaceID != nil && *board.WorkspaceID == workspaceID {
+			result = append(result, board)
+		}
+	}
+	return result, nil
+}
+
+func newTestWorkspaceService() (WorkspaceService, *fakeWorkspaceMemberRepo, BoardPolicy, *recordingEvents) {
+	workspaceMembers := newFakeWorkspaceMemberRepo()
+	boardMembers := &fakeMemberRepo{roles: make(map[string]domain.BoardRole)}
+	policy := NewBoardPolicy(boardMembers, newFakeRoleRepo(), workspaceMembers)
+	events := &recordingEvents{}
+
+	users := &fakeUserRepo{users: map[string]domain.User{
+		"anna":  {ID: "anna"},
+		"boris": {ID: "boris"},
+	}}
+
+	n := 0
+	generateID := func() string {
+		n++
+		return "id-" + strconv.Itoa(n)
+	}
+
+	service := NewWorkspaceService(
+		&fakeWorkspaceRepo{members: workspaceMembers},
+		workspaceMembers,
+		&fakeWorkspaceBoardRepo{boards: make(map[string]domain.Board), members: workspaceMembers},
+		boardMembers,
+		&fakeAssigneeRepo{assigned: map[string]bool{}},
+		users,
+		policy,
+		events,
+		generateID,
+	)
+
+	return service, workspaceMembers, policy, events
+}
+
+func TestWorkspaceAdminGetsAccessToWorkspaceBoards(t *testing.T) {
+	service, _, policy, _ := newTestWorkspaceService()
+
+	workspace, err := service.Create("anna", "Acme")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := service.AddMember("anna", workspace.ID, "boris", domain.WorkspaceRoleMember); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// доску создаёт обычный участник и становится её владельцем
+	board, err := service.CreateBoard("boris", workspace.ID, "Roadmap")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if board.WorkspaceID == nil || *board.WorkspaceID != workspace.ID {
+		t.Fatalf("expected board in workspace %s, got %v", workspace.ID, board.WorkspaceID)
+	}
+
+	if _, err := policy.Authorize(board.ID, "anna", domain.ActionTaskWrite); err != nil {
+		t.Errorf("expected workspace admin to edit tasks, got %v", err)
+	}
+
+	// после переноса в личные доски администратор доступ теряет
+	if _, err := service.MoveBoard("boris", board.ID, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := policy.Authorize(board.ID, "anna", domain.ActionBoardRead); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("expected ErrForbidden after move, got %v", err)
+	}
+}
+
+func TestWorkspaceMoveBoardRequiresTargetMembership(t *testing.T) {
+	service, _, _, _ := newTestWorkspaceService()
+
+	own, _ := service.Create("anna", "Acme")
+	foreign, _ := service.Create("boris", "Globex")
+
+	board, err := service.CreateBoard("anna", own.ID, "Roadmap")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := service.MoveBoard("anna", board.ID, foreign.ID); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden, got %v", err)
+	}
+
+	if _, err := service.MoveBoard("boris", board.ID, foreign.ID); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden for non-owner, got %v", err)
+	}
+}
+
+func TestWorkspaceMembersManagedByAdmins(t *testing.T) {
+	service, members, _, _ := newTestWorkspaceService()
+
+	workspace, _ := service.Create("anna", "Acme")
+
+	if err := service.AddMember("anna", workspace.ID, "boris", "owner"); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput for unknown role, got %v", err)
+	}
+	if err := service.AddMember("anna", workspace.ID, "ghost", domain.WorkspaceRoleMember); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for unknown user, got %v", err)
+	}
+	if err := service.AddMember("anna", workspace.ID, "boris", domain.WorkspaceRoleMember); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := service.AddMember("anna", workspace.ID, "boris", domain.WorkspaceRoleMember); !errors.Is(err, domain.ErrUserAlreadyExists) {
+		t.Errorf("expected ErrUserAlreadyExists, got %v", err)
+	}
+
+	if err := service.RemoveMember("boris", workspace.ID, "anna"); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("expected member to be unable to remove admin, got %v", err)
+	}
+	if err := service.RemoveMember("anna", workspace.ID, "anna"); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("expected admin to be unable to remove themselves, got %v", err)
+	}
+	if err := service.RemoveMember("anna", workspace.ID, "boris"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := members.GetRole(workspace.ID, "boris"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected boris to be removed, got %v", err)
+	}
+}
+
+func TestWorkspaceAdminRemovalNotifiesWorkspaceBoards(t *testing.T) {
+	service, _, policy, events := newTestWorkspaceService()
+
+	workspace, _ := service.Create("anna", "Acme")
+	if err := service.AddMember("anna", workspace.ID, "boris", domain.WorkspaceRoleAdmin); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	board, err := service.CreateBoard("anna", workspace.ID, "Roadmap")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := service.RemoveMember("anna", workspace.ID, "boris"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// по этому событию потоки доски перепроверяют доступ boris
+	last := events.events[len(events.events)-1]
+	if last.Type != domain.EventWorkspaceMemberRemoved || last.BoardID != board.ID {
+		t.Errorf("expected workspace.member_removed on %s, got %+v", board.ID, last)
+	}
+	if _, err := policy.Authorize(board.ID, "boris", domain.ActionBoardRead); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("expected boris to lose access, got %v", err)
+	}
+}
@@ -0,0 +1,8 @@
+package storage
+
+import "github.com/ovk741/TasksStream/internal/domain"
+
+type BoardEventRepository interface {
+	Append(event domain.BoardEvent) (domain.BoardEvent, error)
+	ListAfter(boardID string, afterID int64) ([]domain.BoardEvent, error)
+}
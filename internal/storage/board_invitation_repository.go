@@ -0,0 +1,25 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+type BoardInvitationRepository interface {
+	Create(invitation domain.BoardInvitation) error
+	GetByID(id string) (domain.BoardInvitation, error)
+	GetByTokenHash(tokenHash string) (domain.BoardInvitation, error)
+	ListPendingByBoard(boardID string, now time.Time) ([]domain.BoardInvitation, error)
+	ListPendingByEmail(email string, now time.Time) ([]domain.BoardInvitation, error)
+	// SetStatus меняет статус только у приглашения в статусе pending,
+	// иначе возвращает ErrConflict
+	SetStatus(id string, status domain.InvitationStatus, at time.Time) error
+	// Accept атомарно переводит ожидающее приглашение в accepted и добавляет
+	// участника. Возвращает ErrConflict, если приглашение уже не pending.
+	// Если пользователь уже участник, приглашение всё равно принимается,
+	// а added равно false.
+	Accept(id string, member domain.BoardMember, at time.Time) (added bool, err error)
+	// RevokePending отзывает ожидающие приглашения адреса в доску
+	RevokePending(boardID, email string, at time.Time) error
+}
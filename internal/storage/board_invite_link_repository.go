@@ -0,0 +1,20 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+type BoardInviteLinkRepository interface {
+	Create(link domain.BoardInviteLink) error
+	GetByID(id string) (domain.BoardInviteLink, error)
+	GetByTokenHash(tokenHash string) (domain.BoardInviteLink, error)
+	ListActiveByBoard(boardID string, now time.Time) ([]domain.BoardInviteLink, error)
+	Revoke(boardID, linkID string, revokedAt time.Time) error
+	// Redeem атомарно засчитывает использование ссылки и добавляет участника.
+	// Возвращает ErrConflict, если ссылка отозвана, истекла или исчерпана,
+	// и ErrUserAlreadyExists, если пользователь уже участник — тогда
+	// использование не засчитывается.
+	Redeem(linkID string, member domain.BoardMember, now time.Time) error
+}
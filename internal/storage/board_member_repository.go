@@ -4,8 +4,27 @@ import "github.com/ovk741/TasksStream/internal/domain"
 
 type BoardMemberRepository interface {
 	Add(member domain.BoardMember) error
+	// GetRole и IsMember учитывают и прямое участие, и роли команд
+	// пользователя: возвращается наибольшая из них
 	GetRole(boardID, userID string) (domain.BoardRole, error)
 	IsMember(boardID, userID string) (bool, error)
+	// GetDirectRole возвращает роль только из прямого участия;
+	// ErrNotFound — пользователь не участник доски напрямую
+	GetDirectRole(boardID, userID string) (domain.BoardRole, error)
+	// Remove и UpdateRole не трогают владельца доски и в этом случае
+	// возвращают ErrNotFound: владелец меняется только через TransferOwnership,
+	// поэтому доска не может остаться без него
 	Remove(boardID, userID string) error
+	UpdateRole(boardID, userID string, role domain.BoardRole) error
+	// TransferOwnership в одной транзакции делает участника toUserID
+	// владельцем, а fromUserID — участником с ролью previousOwnerRole.
+	// ErrNotFound — toUserID не участник, ErrConflict — fromUserID уже не владелец.
+	TransferOwnership(boardID, fromUserID, toUserID string, previousOwnerRole domain.BoardRole) error
+	// GetMembers возвращает прямых участников и участников команд доски,
+	// каждого один раз с итоговой ролью
 	GetMembers(boardID string) ([]domain.BoardMember, error)
+	// ReassignOwner в одной транзакции делает owner.UserID владельцем доски,
+	// добавляя его при необходимости, а прежнего владельца, если он есть, —
+	// участником с ролью previousOwnerRole
+	ReassignOwner(owner domain.BoardMember, previousOwnerRole domain.BoardRole) error
 }
@@ -4,8 +4,16 @@ import "github.com/ovk741/TasksStream/internal/domain"
 
 type BoardRepository interface {
 	Create(domain.Board) (domain.Board, error)
-	GetAll() ([]domain.Board, error)
+	// ListByUser возвращает доски, где пользователь участник напрямую или
+	// через команду, и доски пространств, в которых он администратор
+	ListByUser(userID string) ([]domain.Board, error)
+	// ListByWorkspace возвращает доски пространства, доступные пользователю
+	ListByWorkspace(workspaceID, userID string) ([]domain.Board, error)
+	// ListOverview возвращает все доски инстанса с владельцами для администратора
+	ListOverview(limit, offset int) ([]domain.BoardOverview, error)
 	GetByID(boardID string) (domain.Board, error)
 	Update(board domain.Board) (domain.Board, error)
+	// SetWorkspace переносит доску в пространство, nil делает её личной
+	SetWorkspace(boardID string, workspaceID *string) error
 	Delete(boardID string) error
 }
@@ -0,0 +1,13 @@
+package storage
+
+import "github.com/ovk741/TasksStream/internal/domain"
+
+type BoardRoleRepository interface {
+	Create(role domain.BoardRoleDefinition) error
+	GetByName(boardID string, name domain.BoardRole) (domain.BoardRoleDefinition, error)
+	ListByBoard(boardID string) ([]domain.BoardRoleDefinition, error)
+	UpdatePermissions(boardID string, name domain.BoardRole, permissions []domain.Action) error
+	// Delete удаляет роль, только если она не назначена ни одному участнику,
+	// иначе возвращает ErrConflict
+	Delete(boardID string, name domain.BoardRole) error
+}
@@ -0,0 +1,11 @@
+package storage
+
+import "github.com/ovk741/TasksStream/internal/domain"
+
+type BoardShareRepository interface {
+	// Save включает публикацию доски или заменяет токен уже опубликованной
+	Save(share domain.BoardShare) error
+	GetByBoard(boardID string) (domain.BoardShare, error)
+	GetByTokenHash(tokenHash string) (domain.BoardShare, error)
+	Delete(boardID string) error
+}
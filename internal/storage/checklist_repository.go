@@ -0,0 +1,22 @@
+package storage
+
+import "github.com/ovk741/TasksStream/internal/domain"
+
+type ChecklistRepository interface {
+	Create(checklist domain.Checklist) error
+	// GetByID и ListByTask возвращают чек-листы вместе с пунктами по порядку
+	GetByID(id string) (domain.Checklist, error)
+	ListByTask(taskID string) ([]domain.Checklist, error)
+	Rename(id, title string) error
+	Delete(id string) error
+
+	AddItem(item domain.ChecklistItem) error
+	GetItem(id string) (domain.ChecklistItem, error)
+	UpdateItem(item domain.ChecklistItem) error
+	// MoveItem сдвигает пункты целевого чек-листа начиная с position,
+	// как TaskRepository.Move
+	MoveItem(itemID, checklistID string, position int) (domain.ChecklistItem, error)
+	DeleteItem(id string) error
+	// ConvertItem в одной транзакции создаёт задачу из пункта и удаляет пункт
+	ConvertItem(itemID string, task domain.Task) (domain.Task, error)
+}
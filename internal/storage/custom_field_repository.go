@@ -0,0 +1,16 @@
+package storage
+
+import "github.com/ovk741/TasksStream/internal/domain"
+
+type CustomFieldRepository interface {
+	// Create и Update возвращают ErrConflict, если на доске уже есть поле
+	// с таким именем
+	Create(field domain.CustomField) error
+	GetByID(id string) (domain.CustomField, error)
+	ListByBoard(boardID string) ([]domain.CustomField, error)
+	// Update меняет имя и варианты поля; значения задач с удалёнными
+	// вариантами очищаются
+	Update(field domain.CustomField) error
+	// Delete удаляет поле вместе со значениями во всех задачах
+	Delete(id string) error
+}
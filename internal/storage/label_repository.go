@@ -0,0 +1,18 @@
+package storage
+
+import "github.com/ovk741/TasksStream/internal/domain"
+
+type LabelRepository interface {
+	// Create и Update возвращают ErrConflict, если на доске уже есть метка
+	// с таким именем
+	Create(label domain.Label) error
+	GetByID(id string) (domain.Label, error)
+	ListByBoard(boardID string) ([]domain.Label, error)
+	Update(label domain.Label) error
+	// Delete снимает метку со всех задач
+	Delete(id string) error
+
+	// Attach не считает ошибкой повторное прикрепление той же метки
+	Attach(taskID, labelID string) error
+	Detach(taskID, labelID string) error
+}
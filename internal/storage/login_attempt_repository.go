@@ -0,0 +1,19 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+type LoginAttemptRepository interface {
+	Get(key string) (domain.LoginAttempt, error)
+	// RecordFailure одной атомарной операцией увеличивает счётчик и, если
+	// попытки по policy закончились, ставит блокировку. Уже стоящая блокировка
+	// только продлевается. Если последняя неудача была раньше now-policy.Window,
+	// счёт начинается заново
+	RecordFailure(key string, now time.Time, policy domain.LockoutPolicy) (domain.LoginAttempt, error)
+	Reset(key string) error
+
+	RecordLockout(lockout domain.LoginLockout) error
+}
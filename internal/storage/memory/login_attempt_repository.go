@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+// LoginAttemptRepository хранит счётчики в памяти процесса.
+// Подходит для одного экземпляра сервиса и для тестов.
+type LoginAttemptRepository struct {
+	mu       sync.Mutex
+	attempts map[string]domain.LoginAttempt
+	lockouts []domain.LoginLockout
+}
+
+func NewLoginAttemptRepository() *LoginAttemptRepository {
+	return &LoginAttemptRepository{
+		attempts: make(map[string]domain.LoginAttempt),
+	}
+}
+
+func (r *LoginAttemptRepository) Get(key string) (domain.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempt, ok := r.attempts[key]
+	if !ok {
+		return domain.LoginAttempt{}, domain.ErrNotFound
+	}
+	return attempt, nil
+}
+
+func (r *LoginAttemptRepository) RecordFailure(key string, now time.Time, policy domain.LockoutPolicy) (domain.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempt, ok := r.attempts[key]
+	if !ok || attempt.LastFailureAt.Before(now.Add(-policy.Window)) {
+		attempt.Key = key
+		attempt.Failures = 0
+	}
+
+	attempt.Failures++
+	attempt.LastFailureAt = now
+
+	if lockout := policy.Lockout(attempt.Failures); lockout > 0 {
+		until := now.Add(lockout)
+		if attempt.LockedUntil == nil || attempt.LockedUntil.Before(until) {
+			attempt.LockedUntil = &until
+		}
+	}
+
+	r.attempts[key] = attempt
+
+	return attempt, nil
+}
+
+func (r *LoginAttemptRepository) Reset(key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.attempts, key)
+	return nil
+}
+
+func (r *LoginAttemptRepository) RecordLockout(lockout domain.LoginLockout) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lockouts = append(r.lockouts, lockout)
+	return nil
+}
+
+func (r *LoginAttemptRepository) Lockouts() []domain.LoginLockout {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]domain.LoginLockout(nil), r.lockouts...)
+}
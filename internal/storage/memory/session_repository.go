@@ -0,0 +1,110 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+type SessionRepository struct {
+	mu       sync.RWMutex
+	sessions map[string]domain.Session
+}
+
+func NewSessionRepository() *SessionRepository {
+	return &SessionRepository{
+		sessions: make(map[string]domain.Session),
+	}
+}
+
+func (r *SessionRepository) Create(session domain.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.sessions[session.ID]; ok {
+		return domain.ErrConflict
+	}
+
+	r.sessions[session.ID] = session
+	return nil
+}
+
+func (r *SessionRepository) GetByID(id string) (domain.Session, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	session, ok := r.sessions[id]
+	if !ok {
+		return domain.Session{}, domain.ErrNotFound
+	}
+	return session, nil
+}
+
+func (r *SessionRepository) ListActiveByUser(userID string, now time.Time) ([]domain.Session, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]domain.Session, 0)
+	for _, session := range r.sessions {
+		if session.UserID == userID && session.RevokedAt == nil && session.ExpiresAt.After(now) {
+			result = append(result, session)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].LastUsedAt.After(result[j].LastUsedAt)
+	})
+
+	return result, nil
+}
+
+func (r *SessionRepository) Touch(session domain.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.sessions[session.ID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+
+	stored.UserAgent = session.UserAgent
+	stored.IP = session.IP
+	stored.LastUsedAt = session.LastUsedAt
+	stored.ExpiresAt = session.ExpiresAt
+	r.sessions[session.ID] = stored
+
+	return nil
+}
+
+func (r *SessionRepository) Revoke(id string, revokedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, ok := r.sessions[id]
+	if !ok {
+		return domain.ErrNotFound
+	}
+
+	if session.RevokedAt == nil {
+		session.RevokedAt = &revokedAt
+		r.sessions[id] = session
+	}
+
+	return nil
+}
+
+func (r *SessionRepository) RevokeAllForUser(userID string, revokedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, session := range r.sessions {
+		if session.UserID == userID && session.RevokedAt == nil {
+			session.RevokedAt = &revokedAt
+			r.sessions[id] = session
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,14 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+type OIDCStateRepository interface {
+	Create(state domain.OIDCState) error
+	// Consume удаляет и возвращает ещё не истёкшее состояние,
+	// поэтому каждый state можно использовать только один раз
+	Consume(id string, now time.Time) (domain.OIDCState, error)
+}
@@ -0,0 +1,15 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+type PersonalTokenRepository interface {
+	Create(token domain.PersonalAccessToken) error
+	GetByHash(tokenHash string) (domain.PersonalAccessToken, error)
+	ListByUser(userID string) ([]domain.PersonalAccessToken, error)
+	Revoke(userID, tokenID string, revokedAt time.Time) error
+	TouchLastUsed(tokenID string, usedAt time.Time) error
+}
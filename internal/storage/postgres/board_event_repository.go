@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+// максимальное число событий, отдаваемых за один запрос истории
+const boardEventsReplayLimit = 1000
+
+type BoardEventRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewBoardEventRepository(db *pgxpool.Pool) *BoardEventRepository {
+	return &BoardEventRepository{db: db}
+}
+
+func (r *BoardEventRepository) Append(event domain.BoardEvent) (domain.BoardEvent, error) {
+	payload, err := json.Marshal(event.Payload)
+	if err != nil {
+		return domain.BoardEvent{}, domain.ErrInternal
+	}
+
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return domain.BoardEvent{}, domain.ErrInternal
+	}
+	defer tx.Rollback(ctx)
+
+	// сериализуем запись событий одной доски, чтобы номера шли без пропусков
+	if _, err := tx.Exec(ctx,
+		`SELECT pg_advisory_xact_lock(hashtext($1))`,
+		event.BoardID,
+	); err != nil {
+		return domain.BoardEvent{}, domain.ErrInternal
+	}
+
+	err = tx.QueryRow(ctx,
+		`INSERT INTO board_events (board_id, id, type, actor_id, payload, created_at)
+		 SELECT $1, COALESCE(MAX(id), 0) + 1, $2, $3, $4, $5
+		 FROM board_events
+		 WHERE board_id = $1
+		 RETURNING id`,
+		event.BoardID,
+		string(event.Type),
+		event.ActorID,
+		payload,
+		event.CreatedAt,
+	).Scan(&event.ID)
+	if err != nil {
+		return domain.BoardEvent{}, domain.ErrInternal
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return domain.BoardEvent{}, domain.ErrInternal
+	}
+
+	return event, nil
+}
+
+func (r *BoardEventRepository) ListAfter(boardID string, afterID int64) ([]domain.BoardEvent, error) {
+	rows, err := r.db.Query(
+		context.Background(),
+		`SELECT id, board_id, type, actor_id, payload, created_at
+		 FROM board_events
+		 WHERE board_id = $1 AND id > $2
+		 ORDER BY id
+		 LIMIT $3`,
+		boardID,
+		afterID,
+		boardEventsReplayLimit,
+	)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	defer rows.Close()
+
+	events := make([]domain.BoardEvent, 0)
+
+	for rows.Next() {
+		var (
+			e         domain.BoardEvent
+			eventType string
+			payload   []byte
+		)
+		if err := rows.Scan(
+			&e.ID,
+			&e.BoardID,
+			&eventType,
+			&e.ActorID,
+			&payload,
+			&e.CreatedAt,
+		); err != nil {
+			return nil, domain.ErrInternal
+		}
+
+		e.Type = domain.EventType(eventType)
+		e.Payload = json.RawMessage(payload)
+
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return events, nil
+}
@@ -0,0 +1,229 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+type BoardInvitationRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewBoardInvitationRepository(db *pgxpool.Pool) *BoardInvitationRepository {
+	return &BoardInvitationRepository{db: db}
+}
+
+const invitationColumns = `id, board_id, email, role, invited_by, token_hash,
+	status, created_at, expires_at, responded_at`
+
+func (r *BoardInvitationRepository) Create(invitation domain.BoardInvitation) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		`INSERT INTO board_invitations (`+invitationColumns+`)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		invitation.ID,
+		invitation.BoardID,
+		invitation.Email,
+		string(invitation.Role),
+		invitation.InvitedBy,
+		invitation.TokenHash,
+		string(invitation.Status),
+		invitation.CreatedAt,
+		invitation.ExpiresAt,
+		invitation.RespondedAt,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (r *BoardInvitationRepository) GetByID(id string) (domain.BoardInvitation, error) {
+	return r.getOne(`WHERE id = $1`, id)
+}
+
+func (r *BoardInvitationRepository) GetByTokenHash(tokenHash string) (domain.BoardInvitation, error) {
+	return r.getOne(`WHERE token_hash = $1`, tokenHash)
+}
+
+func (r *BoardInvitationRepository) ListPendingByBoard(boardID string, now time.Time) ([]domain.BoardInvitation, error) {
+	return r.list(
+		`WHERE board_id = $1 AND status = 'pending' AND expires_at > $2
+		 ORDER BY created_at DESC`,
+		boardID,
+		now,
+	)
+}
+
+func (r *BoardInvitationRepository) ListPendingByEmail(email string, now time.Time) ([]domain.BoardInvitation, error) {
+	return r.list(
+		`WHERE lower(email) = lower($1) AND status = 'pending' AND expires_at > $2
+		 ORDER BY created_at DESC`,
+		email,
+		now,
+	)
+}
+
+func (r *BoardInvitationRepository) SetStatus(id string, status domain.InvitationStatus, at time.Time) error {
+	result, err := r.db.Exec(
+		context.Background(),
+		`UPDATE board_invitations
+		 SET status = $2, responded_at = $3
+		 WHERE id = $1 AND status = 'pending'`,
+		id,
+		string(status),
+		at,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrConflict
+	}
+
+	return nil
+}
+
+func (r *BoardInvitationRepository) Accept(id string, member domain.BoardMember, at time.Time) (bool, error) {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, domain.ErrInternal
+	}
+	defer tx.Rollback(ctx)
+
+	// из двух параллельных принятий статус сменит только одно
+	result, err := tx.Exec(ctx,
+		`UPDATE board_invitations
+		 SET status = 'accepted', responded_at = $2
+		 WHERE id = $1 AND status = 'pending'`,
+		id,
+		at,
+	)
+	if err != nil {
+		return false, domain.ErrInternal
+	}
+	if result.RowsAffected() == 0 {
+		return false, domain.ErrConflict
+	}
+
+	result, err = tx.Exec(ctx,
+		`INSERT INTO board_members (id, board_id, user_id, role, created_at)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (board_id, user_id) DO NOTHING`,
+		member.ID,
+		member.BoardID,
+		member.UserID,
+		string(member.Role),
+		member.CreatedAt,
+	)
+	if err != nil {
+		return false, domain.ErrInternal
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, domain.ErrInternal
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+func (r *BoardInvitationRepository) RevokePending(boardID, email string, at time.Time) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		`UPDATE board_invitations
+		 SET status = 'revoked', responded_at = $3
+		 WHERE board_id = $1 AND lower(email) = lower($2) AND status = 'pending'`,
+		boardID,
+		email,
+		at,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (r *BoardInvitationRepository) getOne(where string, arg any) (domain.BoardInvitation, error) {
+	row := r.db.QueryRow(
+		context.Background(),
+		`SELECT `+invitationColumns+` FROM board_invitations `+where,
+		arg,
+	)
+
+	invitation, err := scanInvitation(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.BoardInvitation{}, domain.ErrNotFound
+		}
+		return domain.BoardInvitation{}, domain.ErrInternal
+	}
+
+	return invitation, nil
+}
+
+func (r *BoardInvitationRepository) list(where string, args ...any) ([]domain.BoardInvitation, error) {
+	rows, err := r.db.Query(
+		context.Background(),
+		`SELECT `+invitationColumns+` FROM board_invitations `+where,
+		args...,
+	)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	defer rows.Close()
+
+	invitations := make([]domain.BoardInvitation, 0)
+
+	for rows.Next() {
+		invitation, err := scanInvitation(rows)
+		if err != nil {
+			return nil, domain.ErrInternal
+		}
+		invitations = append(invitations, invitation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return invitations, nil
+}
+
+func scanInvitation(row pgx.Row) (domain.BoardInvitation, error) {
+	var (
+		i      domain.BoardInvitation
+		role   string
+		status string
+	)
+
+	err := row.Scan(
+		&i.ID,
+		&i.BoardID,
+		&i.Email,
+		&role,
+		&i.InvitedBy,
+		&i.TokenHash,
+		&status,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.RespondedAt,
+	)
+	if err != nil {
+		return domain.BoardInvitation{}, err
+	}
+
+	i.Role = domain.BoardRole(role)
+	i.Status = domain.InvitationStatus(status)
+
+	return i, nil
+}
@@ -0,0 +1,209 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+type BoardInviteLinkRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewBoardInviteLinkRepository(db *pgxpool.Pool) *BoardInviteLinkRepository {
+	return &BoardInviteLinkRepository{db: db}
+}
+
+const inviteLinkColumns = `id, board_id, role, created_by, token_hash,
+	max_uses, uses, created_at, expires_at, revoked_at`
+
+func (r *BoardInviteLinkRepository) Create(link domain.BoardInviteLink) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		`INSERT INTO board_invite_links (`+inviteLinkColumns+`)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		link.ID,
+		link.BoardID,
+		string(link.Role),
+		link.CreatedBy,
+		link.TokenHash,
+		link.MaxUses,
+		link.Uses,
+		link.CreatedAt,
+		link.ExpiresAt,
+		link.RevokedAt,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (r *BoardInviteLinkRepository) GetByID(id string) (domain.BoardInviteLink, error) {
+	return r.getOne(`WHERE id = $1`, id)
+}
+
+func (r *BoardInviteLinkRepository) GetByTokenHash(tokenHash string) (domain.BoardInviteLink, error) {
+	return r.getOne(`WHERE token_hash = $1`, tokenHash)
+}
+
+func (r *BoardInviteLinkRepository) getOne(where string, arg string) (domain.BoardInviteLink, error) {
+	row := r.db.QueryRow(
+		context.Background(),
+		`SELECT `+inviteLinkColumns+`
+		 FROM board_invite_links
+		 `+where,
+		arg,
+	)
+
+	link, err := scanInviteLink(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.BoardInviteLink{}, domain.ErrNotFound
+		}
+		return domain.BoardInviteLink{}, domain.ErrInternal
+	}
+
+	return link, nil
+}
+
+func (r *BoardInviteLinkRepository) ListActiveByBoard(boardID string, now time.Time) ([]domain.BoardInviteLink, error) {
+	rows, err := r.db.Query(
+		context.Background(),
+		`SELECT `+inviteLinkColumns+`
+		 FROM board_invite_links
+		 WHERE board_id = $1
+		   AND revoked_at IS NULL
+		   AND (expires_at IS NULL OR expires_at > $2)
+		   AND (max_uses IS NULL OR uses < max_uses)
+		 ORDER BY created_at DESC`,
+		boardID,
+		now,
+	)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	defer rows.Close()
+
+	links := make([]domain.BoardInviteLink, 0)
+
+	for rows.Next() {
+		link, err := scanInviteLink(rows)
+		if err != nil {
+			return nil, domain.ErrInternal
+		}
+		links = append(links, link)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return links, nil
+}
+
+func (r *BoardInviteLinkRepository) Revoke(boardID, linkID string, revokedAt time.Time) error {
+	result, err := r.db.Exec(
+		context.Background(),
+		`UPDATE board_invite_links
+		 SET revoked_at = $3
+		 WHERE id = $1 AND board_id = $2 AND revoked_at IS NULL`,
+		linkID,
+		boardID,
+		revokedAt,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *BoardInviteLinkRepository) Redeem(linkID string, member domain.BoardMember, now time.Time) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return domain.ErrInternal
+	}
+	defer tx.Rollback(ctx)
+
+	// условие проверяется в самом UPDATE: параллельные погашения берут
+	// блокировку строки по очереди и последнее место достаётся только одному
+	result, err := tx.Exec(ctx,
+		`UPDATE board_invite_links
+		 SET uses = uses + 1
+		 WHERE id = $1
+		   AND revoked_at IS NULL
+		   AND (expires_at IS NULL OR expires_at > $2)
+		   AND (max_uses IS NULL OR uses < max_uses)`,
+		linkID,
+		now,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrConflict
+	}
+
+	result, err = tx.Exec(ctx,
+		`INSERT INTO board_members (id, board_id, user_id, role, created_at)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (board_id, user_id) DO NOTHING`,
+		member.ID,
+		member.BoardID,
+		member.UserID,
+		string(member.Role),
+		member.CreatedAt,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+	// уже участник: откатываем, чтобы не тратить использование
+	if result.RowsAffected() == 0 {
+		return domain.ErrUserAlreadyExists
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func scanInviteLink(row pgx.Row) (domain.BoardInviteLink, error) {
+	var (
+		l    domain.BoardInviteLink
+		role string
+	)
+
+	err := row.Scan(
+		&l.ID,
+		&l.BoardID,
+		&role,
+		&l.CreatedBy,
+		&l.TokenHash,
+		&l.MaxUses,
+		&l.Uses,
+		&l.CreatedAt,
+		&l.ExpiresAt,
+		&l.RevokedAt,
+	)
+	if err != nil {
+		return domain.BoardInviteLink{}, err
+	}
+
+	l.Role = domain.BoardRole(role)
+
+	return l, nil
+}
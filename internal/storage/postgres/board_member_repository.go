@@ -35,17 +35,68 @@ func (r *BoardMemberRepository) Add(member domain.BoardMember) error {
 	return nil
 }
 
+// boardAccess — все основания доступа к доске $1: прямое участие
+// и участие в командах, которым выдана роль на доске
+const boardAccess = `
+	SELECT m.id, m.user_id, m.role, NULL::text AS team_id, NULL::text AS team_name, m.created_at
+	FROM board_members m
+	WHERE m.board_id = $1
+	UNION ALL
+	SELECT NULL, tm.user_id, g.role, t.id, t.name, g.created_at
+	FROM board_team_grants g
+	JOIN teams t ON t.id = g.team_id
+	JOIN team_members tm ON tm.team_id = g.team_id
+	WHERE g.board_id = $1
+`
+
 func (r *BoardMemberRepository) GetRole(boardID, userID string) (domain.BoardRole, error) {
+	rows, err := r.db.Query(
+		context.Background(),
+		`SELECT a.role FROM (`+boardAccess+`) a WHERE a.user_id = $2`,
+		boardID,
+		userID,
+	)
+	if err != nil {
+		return "", domain.ErrInternal
+	}
+	defer rows.Close()
+
+	var (
+		effective domain.BoardRole
+		found     bool
+	)
+
+	for rows.Next() {
+		var role domain.BoardRole
+		if err := rows.Scan(&role); err != nil {
+			return "", domain.ErrInternal
+		}
+		if !found || role.Rank() > effective.Rank() {
+			effective = role
+			found = true
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return "", domain.ErrInternal
+	}
+
+	if !found {
+		return "", domain.ErrNotFound
+	}
+
+	return effective, nil
+}
+
+func (r *BoardMemberRepository) GetDirectRole(boardID, userID string) (domain.BoardRole, error) {
 	row := r.db.QueryRow(
 		context.Background(),
-		`SELECT role
-		 FROM board_members
-		 WHERE board_id = $1 AND user_id = $2`,
+		`SELECT role FROM board_members WHERE board_id = $1 AND user_id = $2`,
 		boardID,
 		userID,
 	)
 
-	var role string
+	var role domain.BoardRole
 	if err := row.Scan(&role); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return "", domain.ErrNotFound
@@ -53,35 +104,34 @@ func (r *BoardMemberRepository) GetRole(boardID, userID string) (domain.BoardRol
 		return "", domain.ErrInternal
 	}
 
-	return domain.BoardRole(role), nil
+	return role, nil
 }
 
 func (r *BoardMemberRepository) IsMember(boardID, userID string) (bool, error) {
 	row := r.db.QueryRow(
 		context.Background(),
-		`SELECT 1
-		 FROM board_members
-		 WHERE board_id = $1 AND user_id = $2`,
+		`SELECT EXISTS (SELECT 1 FROM (`+boardAccess+`) a WHERE a.user_id = $2)`,
 		boardID,
 		userID,
 	)
 
-	var dummy int
-	if err := row.Scan(&dummy); err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return false, nil
-		}
+	var isMember bool
+	if err := row.Scan(&isMember); err != nil {
 		return false, domain.ErrInternal
 	}
 
-	return true, nil
+	return isMember, nil
 }
 
+// GetMembers возвращает участников вместе с профилями пользователей.
+// Участник нескольких команд и прямой участник попадает в список один раз.
 func (r *BoardMemberRepository) GetMembers(boardID string) ([]domain.BoardMember, error) {
 	query := `
-		SELECT board_id, user_id, role
-		FROM board_members
-		WHERE board_id = $1
+		SELECT a.id, a.user_id, a.role, a.team_id, a.team_name, a.created_at,
+		       u.email, u.display_name, u.avatar_url, u.timezone, u.locale
+		FROM (` + boardAccess + `) a
+		JOIN users u ON u.id = a.user_id
+		ORDER BY a.created_at
 	`
 
 	rows, err := r.db.Query(context.Background(), query, boardID)
@@ -90,14 +140,55 @@ func (r *BoardMemberRepository) GetMembers(boardID string) ([]domain.BoardMember
 	}
 	defer rows.Close()
 
-	var members []domain.BoardMember
+	members := make([]domain.BoardMember, 0)
+	index := make(map[string]int)
 
 	for rows.Next() {
-		var m domain.BoardMember
-		if err := rows.Scan(&m.BoardID, &m.UserID, &m.Role); err != nil {
+		var (
+			id       *string
+			teamID   *string
+			teamName *string
+			m        domain.BoardMember
+			profile  domain.UserProfile
+		)
+		if err := rows.Scan(
+			&id,
+			&m.UserID,
+			&m.Role,
+			&teamID,
+			&teamName,
+			&m.CreatedAt,
+			&profile.Email,
+			&profile.DisplayName,
+			&profile.AvatarURL,
+			&profile.Timezone,
+			&profile.Locale,
+		); err != nil {
 			return nil, err
 		}
-		members = append(members, m)
+
+		i, seen := index[m.UserID]
+		if !seen {
+			profile.ID = m.UserID
+			m.BoardID = boardID
+			m.Access = domain.MemberAccessTeam
+			m.User = &profile
+			members = append(members, m)
+			i = len(members) - 1
+			index[m.UserID] = i
+		}
+
+		member := &members[i]
+		if m.Role.Rank() > member.Role.Rank() {
+			member.Role = m.Role
+		}
+		if id != nil {
+			member.ID = *id
+			member.Access = domain.MemberAccessDirect
+		}
+		if teamID != nil {
+			member.Teams = append(member.Teams, domain.TeamRef{ID: *teamID, Name: *teamName})
+		}
 	}
 
 	if err := rows.Err(); err != nil {
@@ -110,7 +201,7 @@ func (r *BoardMemberRepository) GetMembers(boardID string) ([]domain.BoardMember
 func (r *BoardMemberRepository) Remove(boardID, userID string) error {
 	query := `
 		DELETE FROM board_members
-		WHERE board_id = $1 AND user_id = $2
+		WHERE board_id = $1 AND user_id = $2 AND role <> 'owner'
 	`
 
 	result, err := r.db.Exec(context.Background(), query, boardID, userID)
@@ -124,3 +215,119 @@ func (r *BoardMemberRepository) Remove(boardID, userID string) error {
 
 	return nil
 }
+
+func (r *BoardMemberRepository) UpdateRole(boardID, userID string, role domain.BoardRole) error {
+	result, err := r.db.Exec(
+		context.Background(),
+		`UPDATE board_members
+		 SET role = $3
+		 WHERE board_id = $1 AND user_id = $2 AND role <> 'owner'`,
+		boardID,
+		userID,
+		string(role),
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *BoardMemberRepository) TransferOwnership(
+	boardID, fromUserID, toUserID string,
+	previousOwnerRole domain.BoardRole,
+) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return domain.ErrInternal
+	}
+	defer tx.Rollback(ctx)
+
+	// сначала снимаем права с текущего владельца: строка блокируется,
+	// и параллельная передача той же доски дождётся коммита и не найдёт владельца
+	result, err := tx.Exec(ctx,
+		`UPDATE board_members
+		 SET role = $3
+		 WHERE board_id = $1 AND user_id = $2 AND role = 'owner'`,
+		boardID,
+		fromUserID,
+		string(previousOwnerRole),
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrConflict
+	}
+
+	result, err = tx.Exec(ctx,
+		`UPDATE board_members
+		 SET role = 'owner'
+		 WHERE board_id = $1 AND user_id = $2`,
+		boardID,
+		toUserID,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (r *BoardMemberRepository) ReassignOwner(
+	owner domain.BoardMember,
+	previousOwnerRole domain.BoardRole,
+) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return domain.ErrInternal
+	}
+	defer tx.Rollback(ctx)
+
+	// у доски может не быть владельца, если его учётную запись удалили
+	_, err = tx.Exec(ctx,
+		`UPDATE board_members
+		 SET role = $3
+		 WHERE board_id = $1 AND user_id <> $2 AND role = 'owner'`,
+		owner.BoardID,
+		owner.UserID,
+		string(previousOwnerRole),
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO board_members (id, board_id, user_id, role, created_at)
+		 VALUES ($1, $2, $3, 'owner', $4)
+		 ON CONFLICT (board_id, user_id) DO UPDATE SET role = 'owner'`,
+		owner.ID,
+		owner.BoardID,
+		owner.UserID,
+		owner.CreatedAt,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
@@ -3,12 +3,34 @@ package postgres
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/ovk741/TasksStream/internal/domain"
 )
 
+const boardColumns = `b.id, b.name, b.workspace_id, b.created_at`
+
+// boardVisibleTo — условие доступа к доске b для пользователя, заданного
+// SQL-выражением (параметром вроде "$1" или столбцом): он участник доски,
+// участник команды с ролью на доске или администратор её пространства
+const boardVisibleTo = `(
+	EXISTS (
+		SELECT 1 FROM board_members m
+		WHERE m.board_id = b.id AND m.user_id = %s
+	)
+	OR EXISTS (
+		SELECT 1 FROM board_team_grants g
+		JOIN team_members tm ON tm.team_id = g.team_id
+		WHERE g.board_id = b.id AND tm.user_id = %[1]s
+	)
+	OR EXISTS (
+		SELECT 1 FROM workspace_members w
+		WHERE w.workspace_id = b.workspace_id AND w.user_id = %[1]s AND w.role = 'admin'
+	)
+)`
+
 type BoardRepository struct {
 	db *pgxpool.Pool
 }
@@ -20,31 +42,46 @@ func NewBoardRepository(db *pgxpool.Pool) *BoardRepository {
 func (r *BoardRepository) Create(board domain.Board) (domain.Board, error) {
 	row := r.db.QueryRow(
 		context.Background(),
-		`INSERT INTO boards (id, name, created_at)
-		 VALUES ($1, $2, $3)
-		 RETURNING id, name, created_at`,
+		`INSERT INTO boards AS b (id, name, workspace_id, created_at)
+		 VALUES ($1, $2, $3, $4)
+		 RETURNING `+boardColumns,
 		board.ID,
 		board.Name,
+		board.WorkspaceID,
 		board.CreatedAt,
 	)
 
-	var created domain.Board
-	if err := row.Scan(
-		&created.ID,
-		&created.Name,
-		&created.CreatedAt,
-	); err != nil {
+	created, err := scanBoard(row)
+	if err != nil {
 		return domain.Board{}, domain.ErrInternal
 	}
 
 	return created, nil
 }
 
-func (r *BoardRepository) GetAll() ([]domain.Board, error) {
-	rows, err := r.db.Query(
-		context.Background(),
-		`SELECT id, name, created_at FROM boards`,
+func (r *BoardRepository) ListByUser(userID string) ([]domain.Board, error) {
+	return r.list(
+		`SELECT `+boardColumns+`
+		 FROM boards b
+		 WHERE `+fmt.Sprintf(boardVisibleTo, "$1")+`
+		 ORDER BY b.created_at`,
+		userID,
 	)
+}
+
+func (r *BoardRepository) ListByWorkspace(workspaceID, userID string) ([]domain.Board, error) {
+	return r.list(
+		`SELECT `+boardColumns+`
+		 FROM boards b
+		 WHERE b.workspace_id = $1 AND `+fmt.Sprintf(boardVisibleTo, "$2")+`
+		 ORDER BY b.created_at`,
+		workspaceID,
+		userID,
+	)
+}
+
+func (r *BoardRepository) list(query string, args ...any) ([]domain.Board, error) {
+	rows, err := r.db.Query(context.Background(), query, args...)
 	if err != nil {
 		return nil, domain.ErrInternal
 	}
@@ -53,8 +90,8 @@ func (r *BoardRepository) GetAll() ([]domain.Board, error) {
 	boards := []domain.Board{}
 
 	for rows.Next() {
-		var b domain.Board
-		if err := rows.Scan(&b.ID, &b.Name, &b.CreatedAt); err != nil {
+		b, err := scanBoard(rows)
+		if err != nil {
 			return nil, domain.ErrInternal
 		}
 		boards = append(boards, b)
@@ -67,12 +104,78 @@ func (r *BoardRepository) GetAll() ([]domain.Board, error) {
 	return boards, nil
 }
 
+func (r *BoardRepository) ListOverview(limit, offset int) ([]domain.BoardOverview, error) {
+	rows, err := r.db.Query(
+		context.Background(),
+		`SELECT `+boardColumns+`,
+		        u.id, u.email, u.display_name, u.avatar_url, u.timezone, u.locale,
+		        (SELECT count(*) FROM board_members m WHERE m.board_id = b.id),
+		        (SELECT count(*) FROM tasks t JOIN columns c ON c.id = t.column_id WHERE c.board_id = b.id)
+		 FROM boards b
+		 LEFT JOIN board_members o ON o.board_id = b.id AND o.role = 'owner'
+		 LEFT JOIN users u ON u.id = o.user_id
+		 ORDER BY b.created_at
+		 LIMIT $1 OFFSET $2`,
+		limit,
+		offset,
+	)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	defer rows.Close()
+
+	boards := make([]domain.BoardOverview, 0)
+
+	for rows.Next() {
+		var (
+			o       domain.BoardOverview
+			ownerID *string
+			profile domain.UserProfile
+		)
+		// у доски без владельца все поля профиля NULL
+		var email, displayName, avatarURL, timezone, locale *string
+		if err := rows.Scan(
+			&o.ID,
+			&o.Name,
+			&o.WorkspaceID,
+			&o.CreatedAt,
+			&ownerID,
+			&email,
+			&displayName,
+			&avatarURL,
+			&timezone,
+			&locale,
+			&o.MemberCount,
+			&o.TaskCount,
+		); err != nil {
+			return nil, domain.ErrInternal
+		}
+
+		if ownerID != nil {
+			profile.ID = *ownerID
+			profile.Email = *email
+			profile.DisplayName = *displayName
+			profile.AvatarURL = *avatarURL
+			profile.Timezone = *timezone
+			profile.Locale = *locale
+			o.Owner = &profile
+		}
+
+		boards = append(boards, o)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return boards, nil
+}
+
 func (r *BoardRepository) GetByID(boardID string) (domain.Board, error) {
 	row := r.db.QueryRow(context.Background(),
-		`SELECT id, name, created_at FROM boards WHERE id = $1`, boardID)
+		`SELECT `+boardColumns+` FROM boards b WHERE b.id = $1`, boardID)
 
-	var b domain.Board
-	err := row.Scan(&b.ID, &b.Name, &b.CreatedAt)
+	b, err := scanBoard(row)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return domain.Board{}, domain.ErrNotFound
@@ -86,20 +189,16 @@ func (r *BoardRepository) GetByID(boardID string) (domain.Board, error) {
 func (r *BoardRepository) Update(board domain.Board) (domain.Board, error) {
 	row := r.db.QueryRow(
 		context.Background(),
-		`UPDATE boards
+		`UPDATE boards AS b
 		 SET name = $1
-		 WHERE id = $2
-		 RETURNING id, name, created_at`,
+		 WHERE b.id = $2
+		 RETURNING `+boardColumns,
 		board.Name,
 		board.ID,
 	)
 
-	var updated domain.Board
-	if err := row.Scan(
-		&updated.ID,
-		&updated.Name,
-		&updated.CreatedAt,
-	); err != nil {
+	updated, err := scanBoard(row)
+	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return domain.Board{}, domain.ErrNotFound
 		}
@@ -109,6 +208,24 @@ func (r *BoardRepository) Update(board domain.Board) (domain.Board, error) {
 	return updated, nil
 }
 
+func (r *BoardRepository) SetWorkspace(boardID string, workspaceID *string) error {
+	result, err := r.db.Exec(
+		context.Background(),
+		`UPDATE boards SET workspace_id = $2 WHERE id = $1`,
+		boardID,
+		workspaceID,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
 func (r *BoardRepository) Delete(boardID string) error {
 	row := r.db.QueryRow(
 		context.Background(),
@@ -126,3 +243,13 @@ func (r *BoardRepository) Delete(boardID string) error {
 
 	return nil
 }
+
+func scanBoard(row pgx.Row) (domain.Board, error) {
+	var b domain.Board
+
+	if err := row.Scan(&b.ID, &b.Name, &b.WorkspaceID, &b.CreatedAt); err != nil {
+		return domain.Board{}, err
+	}
+
+	return b, nil
+}
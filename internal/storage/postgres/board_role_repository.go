@@ -0,0 +1,170 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+type BoardRoleRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewBoardRoleRepository(db *pgxpool.Pool) *BoardRoleRepository {
+	return &BoardRoleRepository{db: db}
+}
+
+func (r *BoardRoleRepository) Create(role domain.BoardRoleDefinition) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		`INSERT INTO board_roles (id, board_id, name, permissions, created_at)
+		 VALUES ($1, $2, $3, $4, $5)`,
+		role.ID,
+		role.BoardID,
+		string(role.Name),
+		actionsToStrings(role.Permissions),
+		role.CreatedAt,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return domain.ErrConflict
+		}
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (r *BoardRoleRepository) GetByName(boardID string, name domain.BoardRole) (domain.BoardRoleDefinition, error) {
+	row := r.db.QueryRow(
+		context.Background(),
+		`SELECT id, board_id, name, permissions, created_at
+		 FROM board_roles
+		 WHERE board_id = $1 AND name = $2`,
+		boardID,
+		string(name),
+	)
+
+	role, err := scanBoardRole(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.BoardRoleDefinition{}, domain.ErrNotFound
+		}
+		return domain.BoardRoleDefinition{}, domain.ErrInternal
+	}
+
+	return role, nil
+}
+
+func (r *BoardRoleRepository) ListByBoard(boardID string) ([]domain.BoardRoleDefinition, error) {
+	rows, err := r.db.Query(
+		context.Background(),
+		`SELECT id, board_id, name, permissions, created_at
+		 FROM board_roles
+		 WHERE board_id = $1
+		 ORDER BY name`,
+		boardID,
+	)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	defer rows.Close()
+
+	roles := make([]domain.BoardRoleDefinition, 0)
+
+	for rows.Next() {
+		role, err := scanBoardRole(rows)
+		if err != nil {
+			return nil, domain.ErrInternal
+		}
+		roles = append(roles, role)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return roles, nil
+}
+
+func (r *BoardRoleRepository) UpdatePermissions(boardID string, name domain.BoardRole, permissions []domain.Action) error {
+	result, err := r.db.Exec(
+		context.Background(),
+		`UPDATE board_roles
+		 SET permissions = $3
+		 WHERE board_id = $1 AND name = $2`,
+		boardID,
+		string(name),
+		actionsToStrings(permissions),
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *BoardRoleRepository) Delete(boardID string, name domain.BoardRole) error {
+	result, err := r.db.Exec(
+		context.Background(),
+		`DELETE FROM board_roles r
+		 WHERE r.board_id = $1 AND r.name = $2
+		   AND NOT EXISTS (
+		     SELECT 1 FROM board_members m
+		     WHERE m.board_id = r.board_id AND m.role = r.name
+		   )`,
+		boardID,
+		string(name),
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if result.RowsAffected() > 0 {
+		return nil
+	}
+
+	// роль либо не существует, либо ещё назначена участникам
+	if _, err := r.GetByName(boardID, name); err != nil {
+		return err
+	}
+
+	return domain.ErrConflict
+}
+
+func scanBoardRole(row pgx.Row) (domain.BoardRoleDefinition, error) {
+	var (
+		role        domain.BoardRoleDefinition
+		name        string
+		permissions []string
+	)
+
+	if err := row.Scan(&role.ID, &role.BoardID, &name, &permissions, &role.CreatedAt); err != nil {
+		return domain.BoardRoleDefinition{}, err
+	}
+
+	role.Name = domain.BoardRole(name)
+	role.Permissions = make([]domain.Action, 0, len(permissions))
+	for _, p := range permissions {
+		role.Permissions = append(role.Permissions, domain.Action(p))
+	}
+
+	return role, nil
+}
+
+func actionsToStrings(actions []domain.Action) []string {
+	result := make([]string, 0, len(actions))
+	for _, a := range actions {
+		result = append(result, string(a))
+	}
+	return result
+}
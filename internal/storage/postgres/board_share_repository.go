@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+type BoardShareRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewBoardShareRepository(db *pgxpool.Pool) *BoardShareRepository {
+	return &BoardShareRepository{db: db}
+}
+
+func (r *BoardShareRepository) Save(share domain.BoardShare) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		`INSERT INTO board_shares (board_id, token_hash, created_by, created_at)
+		 VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (board_id) DO UPDATE
+		 SET token_hash = EXCLUDED.token_hash,
+		     created_by = EXCLUDED.created_by,
+		     created_at = EXCLUDED.created_at`,
+		share.BoardID,
+		share.TokenHash,
+		share.CreatedBy,
+		share.CreatedAt,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (r *BoardShareRepository) GetByBoard(boardID string) (domain.BoardShare, error) {
+	return r.get(`WHERE board_id = $1`, boardID)
+}
+
+func (r *BoardShareRepository) GetByTokenHash(tokenHash string) (domain.BoardShare, error) {
+	return r.get(`WHERE token_hash = $1`, tokenHash)
+}
+
+func (r *BoardShareRepository) get(where string, arg string) (domain.BoardShare, error) {
+	row := r.db.QueryRow(
+		context.Background(),
+		`SELECT board_id, token_hash, created_by, created_at
+		 FROM board_shares `+where,
+		arg,
+	)
+
+	var s domain.BoardShare
+	if err := row.Scan(&s.BoardID, &s.TokenHash, &s.CreatedBy, &s.CreatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.BoardShare{}, domain.ErrNotFound
+		}
+		return domain.BoardShare{}, domain.ErrInternal
+	}
+
+	return s, nil
+}
+
+func (r *BoardShareRepository) Delete(boardID string) error {
+	result, err := r.db.Exec(
+		context.Background(),
+		`DELETE FROM board_shares WHERE board_id = $1`,
+		boardID,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
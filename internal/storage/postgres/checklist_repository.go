@@ -0,0 +1,346 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+type ChecklistRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewChecklistRepository(db *pgxpool.Pool) *ChecklistRepository {
+	return &ChecklistRepository{db: db}
+}
+
+func (r *ChecklistRepository) Create(checklist domain.Checklist) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		`INSERT INTO checklists (id, task_id, title, position, created_at)
+		 VALUES ($1, $2, $3, $4, $5)`,
+		checklist.ID,
+		checklist.TaskID,
+		checklist.Title,
+		checklist.Position,
+		checklist.CreatedAt,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (r *ChecklistRepository) GetByID(id string) (domain.Checklist, error) {
+	checklists, err := r.list(
+		`SELECT id, task_id, title, position, created_at
+		 FROM checklists
+		 WHERE id = $1`,
+		`SELECT id, checklist_id, title, done, position, created_at
+		 FROM checklist_items
+		 WHERE checklist_id = $1
+		 ORDER BY position, created_at`,
+		id,
+	)
+	if err != nil {
+		return domain.Checklist{}, err
+	}
+	if len(checklists) == 0 {
+		return domain.Checklist{}, domain.ErrNotFound
+	}
+
+	return checklists[0], nil
+}
+
+func (r *ChecklistRepository) ListByTask(taskID string) ([]domain.Checklist, error) {
+	return r.list(
+		`SELECT id, task_id, title, position, created_at
+		 FROM checklists
+		 WHERE task_id = $1
+		 ORDER BY position, created_at`,
+		`SELECT i.id, i.checklist_id, i.title, i.done, i.position, i.created_at
+		 FROM checklist_items i
+		 JOIN checklists c ON c.id = i.checklist_id
+		 WHERE c.task_id = $1
+		 ORDER BY i.position, i.created_at`,
+		taskID,
+	)
+}
+
+func (r *ChecklistRepository) Rename(id, title string) error {
+	result, err := r.db.Exec(
+		context.Background(),
+		`UPDATE checklists
+		 SET title = $1
+		 WHERE id = $2`,
+		title,
+		id,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *ChecklistRepository) Delete(id string) error {
+	result, err := r.db.Exec(
+		context.Background(),
+		`DELETE FROM checklists
+		 WHERE id = $1`,
+		id,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *ChecklistRepository) AddItem(item domain.ChecklistItem) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		`INSERT INTO checklist_items (id, checklist_id, title, done, position, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6)`,
+		item.ID,
+		item.ChecklistID,
+		item.Title,
+		item.Done,
+		item.Position,
+		item.CreatedAt,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (r *ChecklistRepository) GetItem(id string) (domain.ChecklistItem, error) {
+	row := r.db.QueryRow(
+		context.Background(),
+		`SELECT id, checklist_id, title, done, position, created_at
+		 FROM checklist_items
+		 WHERE id = $1`,
+		id,
+	)
+
+	item, err := scanChecklistItem(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ChecklistItem{}, domain.ErrNotFound
+		}
+		return domain.ChecklistItem{}, domain.ErrInternal
+	}
+
+	return item, nil
+}
+
+func (r *ChecklistRepository) UpdateItem(item domain.ChecklistItem) error {
+	result, err := r.db.Exec(
+		context.Background(),
+		`UPDATE checklist_items
+		 SET title = $1, done = $2
+		 WHERE id = $3`,
+		item.Title,
+		item.Done,
+		item.ID,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *ChecklistRepository) MoveItem(itemID, checklistID string, position int) (domain.ChecklistItem, error) {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return domain.ChecklistItem{}, domain.ErrInternal
+	}
+	defer tx.Rollback(ctx)
+
+	var exists bool
+	err = tx.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM checklist_items WHERE id = $1)`,
+		itemID,
+	).Scan(&exists)
+	if err != nil {
+		return domain.ChecklistItem{}, domain.ErrInternal
+	}
+	if !exists {
+		return domain.ChecklistItem{}, domain.ErrNotFound
+	}
+
+	_, err = tx.Exec(ctx,
+		`UPDATE checklist_items
+		 SET position = position + 1
+		 WHERE checklist_id = $1
+		   AND position >= $2`,
+		checklistID, position,
+	)
+	if err != nil {
+		return domain.ChecklistItem{}, domain.ErrInternal
+	}
+
+	item, err := scanChecklistItem(tx.QueryRow(ctx,
+		`UPDATE checklist_items
+		 SET checklist_id = $1, position = $2
+		 WHERE id = $3
+		 RETURNING id, checklist_id, title, done, position, created_at`,
+		checklistID, position, itemID,
+	))
+	if err != nil {
+		return domain.ChecklistItem{}, domain.ErrInternal
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return domain.ChecklistItem{}, domain.ErrInternal
+	}
+
+	return item, nil
+}
+
+func (r *ChecklistRepository) DeleteItem(id string) error {
+	result, err := r.db.Exec(
+		context.Background(),
+		`DELETE FROM checklist_items
+		 WHERE id = $1`,
+		id,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *ChecklistRepository) ConvertItem(itemID string, task domain.Task) (domain.Task, error) {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return domain.Task{}, domain.ErrInternal
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx,
+		`DELETE FROM checklist_items
+		 WHERE id = $1`,
+		itemID,
+	)
+	if err != nil {
+		return domain.Task{}, domain.ErrInternal
+	}
+	if result.RowsAffected() == 0 {
+		return domain.Task{}, domain.ErrNotFound
+	}
+
+	created, err := scanTask(tx.QueryRow(ctx,
+		`INSERT INTO tasks AS t (id, title, description, column_id, position, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 RETURNING `+taskColumns,
+		task.ID,
+		task.Title,
+		task.Description,
+		task.ColumnID,
+		task.Position,
+		task.CreatedAt,
+	))
+	if err != nil {
+		return domain.Task{}, domain.ErrInternal
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return domain.Task{}, domain.ErrInternal
+	}
+
+	return created, nil
+}
+
+// list читает чек-листы первым запросом и раскладывает по ним пункты
+// из второго; оба запроса принимают одни и те же аргументы
+func (r *ChecklistRepository) list(checklistsQuery, itemsQuery string, args ...any) ([]domain.Checklist, error) {
+	ctx := context.Background()
+
+	rows, err := r.db.Query(ctx, checklistsQuery, args...)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	checklists := make([]domain.Checklist, 0)
+	index := make(map[string]int)
+
+	for rows.Next() {
+		var c domain.Checklist
+		if err := rows.Scan(&c.ID, &c.TaskID, &c.Title, &c.Position, &c.CreatedAt); err != nil {
+			rows.Close()
+			return nil, domain.ErrInternal
+		}
+		c.Items = make([]domain.ChecklistItem, 0)
+		index[c.ID] = len(checklists)
+		checklists = append(checklists, c)
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return nil, domain.ErrInternal
+	}
+	if len(checklists) == 0 {
+		return checklists, nil
+	}
+
+	rows, err = r.db.Query(ctx, itemsQuery, args...)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanChecklistItem(rows)
+		if err != nil {
+			return nil, domain.ErrInternal
+		}
+		if i, ok := index[item.ChecklistID]; ok {
+			checklists[i].Items = append(checklists[i].Items, item)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return checklists, nil
+}
+
+func scanChecklistItem(row pgx.Row) (domain.ChecklistItem, error) {
+	var item domain.ChecklistItem
+
+	if err := row.Scan(&item.ID, &item.ChecklistID, &item.Title, &item.Done, &item.Position, &item.CreatedAt); err != nil {
+		return domain.ChecklistItem{}, err
+	}
+
+	return item, nil
+}
@@ -0,0 +1,204 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+type CustomFieldRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewCustomFieldRepository(db *pgxpool.Pool) *CustomFieldRepository {
+	return &CustomFieldRepository{db: db}
+}
+
+func (r *CustomFieldRepository) Create(field domain.CustomField) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		`INSERT INTO custom_fields (id, board_id, name, type, options, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6)`,
+		field.ID,
+		field.BoardID,
+		field.Name,
+		string(field.Type),
+		fieldOptions(field),
+		field.CreatedAt,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return domain.ErrConflict
+		}
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (r *CustomFieldRepository) GetByID(id string) (domain.CustomField, error) {
+	row := r.db.QueryRow(
+		context.Background(),
+		`SELECT id, board_id, name, type, options, created_at
+		 FROM custom_fields
+		 WHERE id = $1`,
+		id,
+	)
+
+	field, err := scanCustomField(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.CustomField{}, domain.ErrNotFound
+		}
+		return domain.CustomField{}, domain.ErrInternal
+	}
+
+	return field, nil
+}
+
+func (r *CustomFieldRepository) ListByBoard(boardID string) ([]domain.CustomField, error) {
+	rows, err := r.db.Query(
+		context.Background(),
+		`SELECT id, board_id, name, type, options, created_at
+		 FROM custom_fields
+		 WHERE board_id = $1
+		 ORDER BY created_at, id`,
+		boardID,
+	)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	defer rows.Close()
+
+	fields := make([]domain.CustomField, 0)
+
+	for rows.Next() {
+		field, err := scanCustomField(rows)
+		if err != nil {
+			return nil, domain.ErrInternal
+		}
+		fields = append(fields, field)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return fields, nil
+}
+
+func (r *CustomFieldRepository) Update(field domain.CustomField) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return domain.ErrInternal
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx,
+		`UPDATE custom_fields
+		 SET name = $1, options = $2
+		 WHERE id = $3`,
+		field.Name,
+		fieldOptions(field),
+		field.ID,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return domain.ErrConflict
+		}
+		return domain.ErrInternal
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	switch field.Type {
+	case domain.CustomFieldSelect:
+		_, err = tx.Exec(ctx,
+			`DELETE FROM task_field_values
+			 WHERE field_id = $1 AND NOT (value #>> '{}' = ANY($2))`,
+			field.ID,
+			field.Options,
+		)
+	case domain.CustomFieldMultiSelect:
+		// из списка убираются удалённые варианты, опустевшие значения удаляются
+		_, err = tx.Exec(ctx,
+			`UPDATE task_field_values
+			 SET value = COALESCE((
+			     SELECT jsonb_agg(o) FROM jsonb_array_elements_text(value) o
+			     WHERE o = ANY($2)
+			 ), '[]'::jsonb)
+			 WHERE field_id = $1`,
+			field.ID,
+			field.Options,
+		)
+		if err == nil {
+			_, err = tx.Exec(ctx,
+				`DELETE FROM task_field_values
+				 WHERE field_id = $1 AND value = '[]'::jsonb`,
+				field.ID,
+			)
+		}
+	}
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (r *CustomFieldRepository) Delete(id string) error {
+	result, err := r.db.Exec(
+		context.Background(),
+		`DELETE FROM custom_fields
+		 WHERE id = $1`,
+		id,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func scanCustomField(row pgx.Row) (domain.CustomField, error) {
+	var (
+		field     domain.CustomField
+		fieldType string
+	)
+
+	if err := row.Scan(&field.ID, &field.BoardID, &field.Name, &fieldType, &field.Options, &field.CreatedAt); err != nil {
+		return domain.CustomField{}, err
+	}
+
+	field.Type = domain.CustomFieldType(fieldType)
+	if len(field.Options) == 0 {
+		field.Options = nil
+	}
+
+	return field, nil
+}
+
+// fieldOptions не даёт записать NULL в колонку options
+func fieldOptions(field domain.CustomField) []string {
+	if field.Options == nil {
+		return []string{}
+	}
+	return field.Options
+}
@@ -0,0 +1,180 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+type LabelRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewLabelRepository(db *pgxpool.Pool) *LabelRepository {
+	return &LabelRepository{db: db}
+}
+
+func (r *LabelRepository) Create(label domain.Label) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		`INSERT INTO labels (id, board_id, name, color, created_at)
+		 VALUES ($1, $2, $3, $4, $5)`,
+		label.ID,
+		label.BoardID,
+		label.Name,
+		label.Color,
+		label.CreatedAt,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return domain.ErrConflict
+		}
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (r *LabelRepository) GetByID(id string) (domain.Label, error) {
+	row := r.db.QueryRow(
+		context.Background(),
+		`SELECT id, board_id, name, color, created_at
+		 FROM labels
+		 WHERE id = $1`,
+		id,
+	)
+
+	label, err := scanLabel(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Label{}, domain.ErrNotFound
+		}
+		return domain.Label{}, domain.ErrInternal
+	}
+
+	return label, nil
+}
+
+func (r *LabelRepository) ListByBoard(boardID string) ([]domain.Label, error) {
+	rows, err := r.db.Query(
+		context.Background(),
+		`SELECT id, board_id, name, color, created_at
+		 FROM labels
+		 WHERE board_id = $1
+		 ORDER BY name`,
+		boardID,
+	)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	defer rows.Close()
+
+	labels := make([]domain.Label, 0)
+
+	for rows.Next() {
+		label, err := scanLabel(rows)
+		if err != nil {
+			return nil, domain.ErrInternal
+		}
+		labels = append(labels, label)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return labels, nil
+}
+
+func (r *LabelRepository) Update(label domain.Label) error {
+	result, err := r.db.Exec(
+		context.Background(),
+		`UPDATE labels
+		 SET name = $1, color = $2
+		 WHERE id = $3`,
+		label.Name,
+		label.Color,
+		label.ID,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return domain.ErrConflict
+		}
+		return domain.ErrInternal
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *LabelRepository) Delete(id string) error {
+	result, err := r.db.Exec(
+		context.Background(),
+		`DELETE FROM labels
+		 WHERE id = $1`,
+		id,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *LabelRepository) Attach(taskID, labelID string) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		`INSERT INTO task_labels (task_id, label_id)
+		 VALUES ($1, $2)
+		 ON CONFLICT (task_id, label_id) DO NOTHING`,
+		taskID,
+		labelID,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (r *LabelRepository) Detach(taskID, labelID string) error {
+	result, err := r.db.Exec(
+		context.Background(),
+		`DELETE FROM task_labels
+		 WHERE task_id = $1 AND label_id = $2`,
+		taskID,
+		labelID,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func scanLabel(row pgx.Row) (domain.Label, error) {
+	var label domain.Label
+
+	if err := row.Scan(&label.ID, &label.BoardID, &label.Name, &label.Color, &label.CreatedAt); err != nil {
+		return domain.Label{}, err
+	}
+
+	return label, nil
+}
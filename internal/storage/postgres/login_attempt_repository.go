@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+type LoginAttemptRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewLoginAttemptRepository(db *pgxpool.Pool) *LoginAttemptRepository {
+	return &LoginAttemptRepository{db: db}
+}
+
+func (r *LoginAttemptRepository) Get(key string) (domain.LoginAttempt, error) {
+	row := r.db.QueryRow(
+		context.Background(),
+		`SELECT key, failures, last_failure_at, locked_until
+		 FROM login_attempts
+		 WHERE key = $1`,
+		key,
+	)
+
+	var a domain.LoginAttempt
+	err := row.Scan(&a.Key, &a.Failures, &a.LastFailureAt, &a.LockedUntil)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.LoginAttempt{}, domain.ErrNotFound
+		}
+		return domain.LoginAttempt{}, domain.ErrInternal
+	}
+
+	return a, nil
+}
+
+// RecordFailure выполняется одним upsert: счётчик и блокировка считаются
+// в одном запросе, поэтому параллельные неудачи, в том числе с нескольких
+// экземпляров сервиса, не теряют блокировку и не укорачивают её.
+// Новое значение failures в SET недоступно, поэтому выражение повторяется.
+func (r *LoginAttemptRepository) RecordFailure(key string, now time.Time, policy domain.LockoutPolicy) (domain.LoginAttempt, error) {
+	row := r.db.QueryRow(
+		context.Background(),
+		`INSERT INTO login_attempts (key, failures, last_failure_at, locked_until)
+		 VALUES (
+		     $1, 1, $2::timestamp,
+		     CASE WHEN 1 >= $4::int
+		         THEN $2::timestamp + make_interval(secs => LEAST($5::float8, $6::float8))
+		     END
+		 )
+		 ON CONFLICT (key) DO UPDATE SET
+		     failures = CASE
+		         WHEN login_attempts.last_failure_at < $3::timestamp THEN 1
+		         ELSE login_attempts.failures + 1
+		     END,
+		     last_failure_at = $2::timestamp,
+		     locked_until = CASE
+		         WHEN login_attempts.last_failure_at >= $3::timestamp
+		             AND login_attempts.failures + 1 >= $4::int
+		         THEN GREATEST(
+		             COALESCE(login_attempts.locked_until, $2::timestamp),
+		             $2::timestamp + make_interval(secs => LEAST(
+		                 $5::float8 * power(2, LEAST(login_attempts.failures + 1 - $4::int, 30)),
+		                 $6::float8
+		             ))
+		         )
+		         WHEN login_attempts.last_failure_at < $3::timestamp AND 1 >= $4::int
+		         THEN $2::timestamp + make_interval(secs => LEAST($5::float8, $6::float8))
+		         ELSE login_attempts.locked_until
+		     END
+		 RETURNING key, failures, last_failure_at, locked_until`,
+		key,
+		now,
+		now.Add(-policy.Window),
+		policy.FreeAttempts,
+		policy.BaseLockout.Seconds(),
+		policy.MaxLockout.Seconds(),
+	)
+
+	var a domain.LoginAttempt
+	if err := row.Scan(&a.Key, &a.Failures, &a.LastFailureAt, &a.LockedUntil); err != nil {
+		return domain.LoginAttempt{}, domain.ErrInternal
+	}
+
+	return a, nil
+}
+
+func (r *LoginAttemptRepository) Reset(key string) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		`DELETE FROM login_attempts WHERE key = $1`,
+		key,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (r *LoginAttemptRepository) RecordLockout(lockout domain.LoginLockout) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		`INSERT INTO login_lockouts (id, key, failures, ip, locked_until, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6)`,
+		lockout.ID,
+		lockout.Key,
+		lockout.Failures,
+		lockout.IP,
+		lockout.LockedUntil,
+		lockout.CreatedAt,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+type OIDCStateRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewOIDCStateRepository(db *pgxpool.Pool) *OIDCStateRepository {
+	return &OIDCStateRepository{db: db}
+}
+
+func (r *OIDCStateRepository) Create(state domain.OIDCState) error {
+	ctx := context.Background()
+
+	// попутно чистим брошенные входы
+	if _, err := r.db.Exec(ctx,
+		`DELETE FROM oidc_states WHERE expires_at < $1`,
+		state.CreatedAt,
+	); err != nil {
+		return domain.ErrInternal
+	}
+
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO oidc_states (id, nonce, code_verifier, created_at, expires_at)
+		 VALUES ($1, $2, $3, $4, $5)`,
+		state.ID,
+		state.Nonce,
+		state.CodeVerifier,
+		state.CreatedAt,
+		state.ExpiresAt,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (r *OIDCStateRepository) Consume(id string, now time.Time) (domain.OIDCState, error) {
+	row := r.db.QueryRow(
+		context.Background(),
+		`DELETE FROM oidc_states
+		 WHERE id = $1
+		 RETURNING id, nonce, code_verifier, created_at, expires_at`,
+		id,
+	)
+
+	var s domain.OIDCState
+	err := row.Scan(&s.ID, &s.Nonce, &s.CodeVerifier, &s.CreatedAt, &s.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.OIDCState{}, domain.ErrNotFound
+		}
+		return domain.OIDCState{}, domain.ErrInternal
+	}
+
+	if !s.ExpiresAt.After(now) {
+		return domain.OIDCState{}, domain.ErrNotFound
+	}
+
+	return s, nil
+}
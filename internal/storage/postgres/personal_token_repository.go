@@ -0,0 +1,164 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+type PersonalTokenRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPersonalTokenRepository(db *pgxpool.Pool) *PersonalTokenRepository {
+	return &PersonalTokenRepository{db: db}
+}
+
+func (r *PersonalTokenRepository) Create(token domain.PersonalAccessToken) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		`INSERT INTO personal_access_tokens (id, user_id, name, token_hash, scopes, created_at, expires_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		token.ID,
+		token.UserID,
+		token.Name,
+		token.TokenHash,
+		scopesToStrings(token.Scopes),
+		token.CreatedAt,
+		token.ExpiresAt,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (r *PersonalTokenRepository) GetByHash(tokenHash string) (domain.PersonalAccessToken, error) {
+	row := r.db.QueryRow(
+		context.Background(),
+		`SELECT id, user_id, name, token_hash, scopes, created_at, last_used_at, expires_at, revoked_at
+		 FROM personal_access_tokens
+		 WHERE token_hash = $1`,
+		tokenHash,
+	)
+
+	token, err := scanPersonalToken(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.PersonalAccessToken{}, domain.ErrNotFound
+		}
+		return domain.PersonalAccessToken{}, domain.ErrInternal
+	}
+
+	return token, nil
+}
+
+func (r *PersonalTokenRepository) ListByUser(userID string) ([]domain.PersonalAccessToken, error) {
+	rows, err := r.db.Query(
+		context.Background(),
+		`SELECT id, user_id, name, token_hash, scopes, created_at, last_used_at, expires_at, revoked_at
+		 FROM personal_access_tokens
+		 WHERE user_id = $1 AND revoked_at IS NULL
+		 ORDER BY created_at DESC`,
+		userID,
+	)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	defer rows.Close()
+
+	tokens := make([]domain.PersonalAccessToken, 0)
+
+	for rows.Next() {
+		token, err := scanPersonalToken(rows)
+		if err != nil {
+			return nil, domain.ErrInternal
+		}
+		tokens = append(tokens, token)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return tokens, nil
+}
+
+func (r *PersonalTokenRepository) Revoke(userID, tokenID string, revokedAt time.Time) error {
+	result, err := r.db.Exec(
+		context.Background(),
+		`UPDATE personal_access_tokens
+		 SET revoked_at = $3
+		 WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`,
+		tokenID,
+		userID,
+		revokedAt,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *PersonalTokenRepository) TouchLastUsed(tokenID string, usedAt time.Time) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		`UPDATE personal_access_tokens
+		 SET last_used_at = $2
+		 WHERE id = $1`,
+		tokenID,
+		usedAt,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func scanPersonalToken(row pgx.Row) (domain.PersonalAccessToken, error) {
+	var (
+		t      domain.PersonalAccessToken
+		scopes []string
+	)
+
+	err := row.Scan(
+		&t.ID,
+		&t.UserID,
+		&t.Name,
+		&t.TokenHash,
+		&scopes,
+		&t.CreatedAt,
+		&t.LastUsedAt,
+		&t.ExpiresAt,
+		&t.RevokedAt,
+	)
+	if err != nil {
+		return domain.PersonalAccessToken{}, err
+	}
+
+	t.Scopes = make([]domain.TokenScope, 0, len(scopes))
+	for _, s := range scopes {
+		t.Scopes = append(t.Scopes, domain.TokenScope(s))
+	}
+
+	return t, nil
+}
+
+func scopesToStrings(scopes []domain.TokenScope) []string {
+	result := make([]string, 0, len(scopes))
+	for _, s := range scopes {
+		result = append(result, string(s))
+	}
+	return result
+}
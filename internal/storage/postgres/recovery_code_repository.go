@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+type RecoveryCodeRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewRecoveryCodeRepository(db *pgxpool.Pool) *RecoveryCodeRepository {
+	return &RecoveryCodeRepository{db: db}
+}
+
+func (r *RecoveryCodeRepository) Replace(userID string, codes []domain.RecoveryCode) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return domain.ErrInternal
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx,
+		`DELETE FROM recovery_codes WHERE user_id = $1`,
+		userID,
+	); err != nil {
+		return domain.ErrInternal
+	}
+
+	for _, code := range codes {
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO recovery_codes (id, user_id, code_hash, created_at)
+			 VALUES ($1, $2, $3, $4)`,
+			code.ID,
+			userID,
+			code.CodeHash,
+			code.CreatedAt,
+		); err != nil {
+			return domain.ErrInternal
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (r *RecoveryCodeRepository) ListUnused(userID string) ([]domain.RecoveryCode, error) {
+	rows, err := r.db.Query(
+		context.Background(),
+		`SELECT id, user_id, code_hash, created_at, used_at
+		 FROM recovery_codes
+		 WHERE user_id = $1 AND used_at IS NULL
+		 ORDER BY created_at`,
+		userID,
+	)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	defer rows.Close()
+
+	codes := make([]domain.RecoveryCode, 0)
+
+	for rows.Next() {
+		var c domain.RecoveryCode
+		if err := rows.Scan(
+			&c.ID,
+			&c.UserID,
+			&c.CodeHash,
+			&c.CreatedAt,
+			&c.UsedAt,
+		); err != nil {
+			return nil, domain.ErrInternal
+		}
+		codes = append(codes, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return codes, nil
+}
+
+// MarkUsed возвращает ErrConflict, если код уже был использован,
+// в том числе параллельным запросом.
+func (r *RecoveryCodeRepository) MarkUsed(id string, usedAt time.Time) error {
+	result, err := r.db.Exec(
+		context.Background(),
+		`UPDATE recovery_codes
+		 SET used_at = $2
+		 WHERE id = $1 AND used_at IS NULL`,
+		id,
+		usedAt,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrConflict
+	}
+
+	return nil
+}
+
+func (r *RecoveryCodeRepository) DeleteAllForUser(userID string) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		`DELETE FROM recovery_codes WHERE user_id = $1`,
+		userID,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+type RefreshTokenRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewRefreshTokenRepository(db *pgxpool.Pool) *RefreshTokenRepository {
+	return &RefreshTokenRepository{db: db}
+}
+
+func (r *RefreshTokenRepository) Create(token domain.RefreshToken) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		`INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6)`,
+		token.ID,
+		token.UserID,
+		token.FamilyID,
+		token.TokenHash,
+		token.ExpiresAt,
+		token.CreatedAt,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (r *RefreshTokenRepository) GetByHash(tokenHash string) (domain.RefreshToken, error) {
+	row := r.db.QueryRow(
+		context.Background(),
+		`SELECT id, user_id, family_id, token_hash, expires_at, created_at, used_at, revoked_at
+		 FROM refresh_tokens
+		 WHERE token_hash = $1`,
+		tokenHash,
+	)
+
+	var t domain.RefreshToken
+	err := row.Scan(
+		&t.ID,
+		&t.UserID,
+		&t.FamilyID,
+		&t.TokenHash,
+		&t.ExpiresAt,
+		&t.CreatedAt,
+		&t.UsedAt,
+		&t.RevokedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.RefreshToken{}, domain.ErrNotFound
+		}
+		return domain.RefreshToken{}, domain.ErrInternal
+	}
+
+	return t, nil
+}
+
+// MarkUsed помечает токен использованным. Если токен уже был использован
+// или отозван (в том числе параллельным запросом), возвращается ErrConflict.
+func (r *RefreshTokenRepository) MarkUsed(id string, usedAt time.Time) error {
+	result, err := r.db.Exec(
+		context.Background(),
+		`UPDATE refresh_tokens
+		 SET used_at = $2
+		 WHERE id = $1 AND used_at IS NULL AND revoked_at IS NULL`,
+		id,
+		usedAt,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrConflict
+	}
+
+	return nil
+}
+
+func (r *RefreshTokenRepository) RevokeFamily(familyID string, revokedAt time.Time) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		`UPDATE refresh_tokens
+		 SET revoked_at = $2
+		 WHERE family_id = $1 AND revoked_at IS NULL`,
+		familyID,
+		revokedAt,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (r *RefreshTokenRepository) RevokeAllForUser(userID string, revokedAt time.Time) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		`UPDATE refresh_tokens
+		 SET revoked_at = $2
+		 WHERE user_id = $1 AND revoked_at IS NULL`,
+		userID,
+		revokedAt,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
@@ -0,0 +1,170 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+type SessionRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewSessionRepository(db *pgxpool.Pool) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+func (r *SessionRepository) Create(session domain.Session) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		`INSERT INTO sessions (id, user_id, user_agent, ip, created_at, last_used_at, expires_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		session.ID,
+		session.UserID,
+		session.UserAgent,
+		session.IP,
+		session.CreatedAt,
+		session.LastUsedAt,
+		session.ExpiresAt,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (r *SessionRepository) GetByID(id string) (domain.Session, error) {
+	row := r.db.QueryRow(
+		context.Background(),
+		`SELECT id, user_id, user_agent, ip, created_at, last_used_at, expires_at, revoked_at
+		 FROM sessions
+		 WHERE id = $1`,
+		id,
+	)
+
+	var s domain.Session
+	err := row.Scan(
+		&s.ID,
+		&s.UserID,
+		&s.UserAgent,
+		&s.IP,
+		&s.CreatedAt,
+		&s.LastUsedAt,
+		&s.ExpiresAt,
+		&s.RevokedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Session{}, domain.ErrNotFound
+		}
+		return domain.Session{}, domain.ErrInternal
+	}
+
+	return s, nil
+}
+
+func (r *SessionRepository) ListActiveByUser(userID string, now time.Time) ([]domain.Session, error) {
+	rows, err := r.db.Query(
+		context.Background(),
+		`SELECT id, user_id, user_agent, ip, created_at, last_used_at, expires_at, revoked_at
+		 FROM sessions
+		 WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2
+		 ORDER BY last_used_at DESC`,
+		userID,
+		now,
+	)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	defer rows.Close()
+
+	sessions := make([]domain.Session, 0)
+
+	for rows.Next() {
+		var s domain.Session
+		if err := rows.Scan(
+			&s.ID,
+			&s.UserID,
+			&s.UserAgent,
+			&s.IP,
+			&s.CreatedAt,
+			&s.LastUsedAt,
+			&s.ExpiresAt,
+			&s.RevokedAt,
+		); err != nil {
+			return nil, domain.ErrInternal
+		}
+
+		sessions = append(sessions, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return sessions, nil
+}
+
+func (r *SessionRepository) Touch(session domain.Session) error {
+	result, err := r.db.Exec(
+		context.Background(),
+		`UPDATE sessions
+		 SET user_agent = $2, ip = $3, last_used_at = $4, expires_at = $5
+		 WHERE id = $1`,
+		session.ID,
+		session.UserAgent,
+		session.IP,
+		session.LastUsedAt,
+		session.ExpiresAt,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *SessionRepository) Revoke(id string, revokedAt time.Time) error {
+	result, err := r.db.Exec(
+		context.Background(),
+		`UPDATE sessions
+		 SET revoked_at = COALESCE(revoked_at, $2)
+		 WHERE id = $1`,
+		id,
+		revokedAt,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *SessionRepository) RevokeAllForUser(userID string, revokedAt time.Time) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		`UPDATE sessions
+		 SET revoked_at = $2
+		 WHERE user_id = $1 AND revoked_at IS NULL`,
+		userID,
+		revokedAt,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
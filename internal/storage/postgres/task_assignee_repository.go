@@ -0,0 +1,173 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+type TaskAssigneeRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewTaskAssigneeRepository(db *pgxpool.Pool) *TaskAssigneeRepository {
+	return &TaskAssigneeRepository{db: db}
+}
+
+func (r *TaskAssigneeRepository) Add(assignee domain.TaskAssignee) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		`INSERT INTO task_assignees (task_id, user_id, assigned_by, created_at)
+		 VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (task_id, user_id) DO NOTHING`,
+		assignee.TaskID,
+		assignee.UserID,
+		assignee.AssignedBy,
+		assignee.CreatedAt,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (r *TaskAssigneeRepository) Remove(taskID, userID string) error {
+	result, err := r.db.Exec(
+		context.Background(),
+		`DELETE FROM task_assignees
+		 WHERE task_id = $1 AND user_id = $2`,
+		taskID,
+		userID,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *TaskAssigneeRepository) ListByTask(taskID string) ([]domain.TaskAssignee, error) {
+	rows, err := r.db.Query(
+		context.Background(),
+		`SELECT a.task_id, a.user_id, a.assigned_by, a.created_at,
+		        u.email, u.display_name, u.avatar_url, u.timezone, u.locale
+		 FROM task_assignees a
+		 JOIN users u ON u.id = a.user_id
+		 WHERE a.task_id = $1
+		 ORDER BY a.created_at`,
+		taskID,
+	)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	defer rows.Close()
+
+	assignees := make([]domain.TaskAssignee, 0)
+
+	for rows.Next() {
+		var (
+			a       domain.TaskAssignee
+			profile domain.UserProfile
+		)
+		if err := rows.Scan(
+			&a.TaskID,
+			&a.UserID,
+			&a.AssignedBy,
+			&a.CreatedAt,
+			&profile.Email,
+			&profile.DisplayName,
+			&profile.AvatarURL,
+			&profile.Timezone,
+			&profile.Locale,
+		); err != nil {
+			return nil, domain.ErrInternal
+		}
+		profile.ID = a.UserID
+		a.User = &profile
+		assignees = append(assignees, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return assignees, nil
+}
+
+func (r *TaskAssigneeRepository) ListByUser(userID string) ([]domain.AssignedTask, error) {
+	return r.listAssigned(
+		`SELECT `+taskColumns+`, b.id, b.name, c.title, c.position
+		 FROM task_assignees a
+		 JOIN tasks t ON t.id = a.task_id
+		 JOIN columns c ON c.id = t.column_id
+		 JOIN boards b ON b.id = c.board_id
+		 WHERE a.user_id = $1 AND `+fmt.Sprintf(boardVisibleTo, "$1")+`
+		 ORDER BY b.created_at, b.id, c.position, t.position`,
+		userID,
+	)
+}
+
+func (r *TaskAssigneeRepository) ListDueByUser(userID string, from *time.Time, to time.Time) ([]domain.AssignedTask, error) {
+	return r.listAssigned(
+		`SELECT `+taskColumns+`, b.id, b.name, c.title, c.position
+		 FROM task_assignees a
+		 JOIN tasks t ON t.id = a.task_id
+		 JOIN columns c ON c.id = t.column_id
+		 JOIN boards b ON b.id = c.board_id
+		 WHERE a.user_id = $1 AND `+fmt.Sprintf(boardVisibleTo, "$1")+`
+		   AND `+fmt.Sprintf(taskDueBetween, 2, 3)+`
+		 ORDER BY t.due_at, b.created_at, b.id, c.position, t.position`,
+		userID,
+		from,
+		to,
+	)
+}
+
+func (r *TaskAssigneeRepository) listAssigned(query string, args ...any) ([]domain.AssignedTask, error) {
+	rows, err := r.db.Query(context.Background(), query, args...)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	defer rows.Close()
+
+	tasks := make([]domain.AssignedTask, 0)
+
+	for rows.Next() {
+		var t domain.AssignedTask
+		fields := append(taskFields(&t.Task), &t.BoardID, &t.BoardName, &t.ColumnTitle, &t.ColumnPosition)
+		if err := rows.Scan(fields...); err != nil {
+			return nil, domain.ErrInternal
+		}
+		tasks = append(tasks, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return tasks, nil
+}
+
+func (r *TaskAssigneeRepository) RemoveFromBoard(boardID, userID string) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		`DELETE FROM task_assignees a
+		 USING tasks t, columns c
+		 WHERE a.task_id = t.id AND t.column_id = c.id
+		   AND c.board_id = $1 AND a.user_id = $2`,
+		boardID,
+		userID,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
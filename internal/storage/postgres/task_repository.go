@@ -2,13 +2,52 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/ovk741/TasksStream/internal/domain"
 )
 
+// taskColumns — поля задачи t в порядке taskFields; последними идут
+// ID исполнителей в порядке назначения, ID меток, значения полей доски
+// и число отмеченных и всех пунктов чек-листов
+const taskColumns = `t.id, t.column_id, t.title, t.description, t.position,
+	t.created_at, t.start_at, t.due_at,
+	ARRAY(
+		SELECT a.user_id FROM task_assignees a
+		WHERE a.task_id = t.id
+		ORDER BY a.created_at
+	),
+	ARRAY(
+		SELECT tl.label_id FROM task_labels tl
+		WHERE tl.task_id = t.id
+		ORDER BY tl.label_id
+	),
+	COALESCE((
+		SELECT jsonb_object_agg(v.field_id, v.value) FROM task_field_values v
+		WHERE v.task_id = t.id
+	), '{}'::jsonb),
+	(
+		SELECT COUNT(*) FILTER (WHERE i.done) FROM checklist_items i
+		JOIN checklists cl ON cl.id = i.checklist_id
+		WHERE cl.task_id = t.id
+	),
+	(
+		SELECT COUNT(*) FROM checklist_items i
+		JOIN checklists cl ON cl.id = i.checklist_id
+		WHERE cl.task_id = t.id
+	)`
+
+// taskDueBetween — условие на срок задачи t: не раньше $N, если он задан,
+// и строго раньше $N+1
+const taskDueBetween = `t.due_at IS NOT NULL
+	AND ($%d::timestamptz IS NULL OR t.due_at >= $%[1]d)
+	AND t.due_at < $%d`
+
 type TaskRepository struct {
 	db *pgxpool.Pool
 }
@@ -18,28 +57,45 @@ func NewTaskRepository(db *pgxpool.Pool) *TaskRepository {
 }
 
 func (r *TaskRepository) Create(task domain.Task) (domain.Task, error) {
-	row := r.db.QueryRow(
-		context.Background(),
-		`INSERT INTO tasks (id, title, description, column_id, position, created_at)
-		 VALUES ($1, $2, $3, $4, $5, $6)
-		 RETURNING id, title, description, column_id, position, created_at`,
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return domain.Task{}, domain.ErrInternal
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO tasks (id, title, description, column_id, position, created_at, start_at, due_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
 		task.ID,
 		task.Title,
 		task.Description,
 		task.ColumnID,
 		task.Position,
 		task.CreatedAt,
+		task.StartAt,
+		task.DueAt,
 	)
+	if err != nil {
+		return domain.Task{}, domain.ErrInternal
+	}
 
-	var created domain.Task
-	if err := row.Scan(
-		&created.ID,
-		&created.Title,
-		&created.Description,
-		&created.ColumnID,
-		&created.Position,
-		&created.CreatedAt,
-	); err != nil {
+	if err := replaceFieldValues(ctx, tx, task.ID, task.CustomFields); err != nil {
+		return domain.Task{}, err
+	}
+
+	created, err := scanTask(tx.QueryRow(ctx,
+		`SELECT `+taskColumns+`
+		 FROM tasks t
+		 WHERE t.id = $1`,
+		task.ID,
+	))
+	if err != nil {
+		return domain.Task{}, domain.ErrInternal
+	}
+
+	if err := tx.Commit(ctx); err != nil {
 		return domain.Task{}, domain.ErrInternal
 	}
 
@@ -47,61 +103,37 @@ func (r *TaskRepository) Create(task domain.Task) (domain.Task, error) {
 }
 
 func (r *TaskRepository) GetByColumnID(columnID string) ([]domain.Task, error) {
-	rows, err := r.db.Query(
-		context.Background(),
-		`SELECT id, title, position, description, column_id, created_at 
-		FROM tasks 
-		WHERE column_id = $1 
-		ORDER BY position`,
+	return r.list(
+		`SELECT `+taskColumns+`
+		 FROM tasks t
+		 WHERE t.column_id = $1
+		 ORDER BY t.position`,
 		columnID,
 	)
-	if err != nil {
-		return nil, domain.ErrInternal
-	}
-	defer rows.Close()
-
-	tasks := make([]domain.Task, 0)
-
-	for rows.Next() {
-		var t domain.Task
-		if err := rows.Scan(
-			&t.ID,
-			&t.Title,
-			&t.Position,
-			&t.Description,
-			&t.ColumnID,
-			&t.CreatedAt,
-		); err != nil {
-			return nil, domain.ErrInternal
-		}
-
-		tasks = append(tasks, t)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, domain.ErrInternal
-	}
+}
 
-	return tasks, nil
+func (r *TaskRepository) ListDueByBoard(boardID string, from *time.Time, to time.Time) ([]domain.Task, error) {
+	return r.list(
+		`SELECT `+taskColumns+`
+		 FROM tasks t
+		 JOIN columns c ON c.id = t.column_id
+		 WHERE c.board_id = $1 AND `+fmt.Sprintf(taskDueBetween, 2, 3)+`
+		 ORDER BY t.due_at, c.position, t.position`,
+		boardID,
+		from,
+		to,
+	)
 }
 
 func (r *TaskRepository) GetByID(id string) (domain.Task, error) {
 	row := r.db.QueryRow(context.Background(),
-		`SELECT id, title, description, position, column_id, created_at 
-		FROM tasks 
-		WHERE id = $1`,
+		`SELECT `+taskColumns+`
+		 FROM tasks t
+		 WHERE t.id = $1`,
 		id,
 	)
 
-	var t domain.Task
-	err := row.Scan(
-		&t.ID,
-		&t.Title,
-		&t.Description,
-		&t.Position,
-		&t.ColumnID,
-		&t.CreatedAt,
-	)
+	t, err := scanTask(row)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return domain.Task{}, domain.ErrNotFound
@@ -112,31 +144,49 @@ func (r *TaskRepository) GetByID(id string) (domain.Task, error) {
 	return t, nil
 }
 
+// Update заменяет и значения полей доски: незаданные в task.CustomFields
+// значения удаляются
 func (r *TaskRepository) Update(task domain.Task) (domain.Task, error) {
-	row := r.db.QueryRow(
-		context.Background(),
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return domain.Task{}, domain.ErrInternal
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx,
 		`UPDATE tasks
-		 SET title = $1, description = $2
-		 WHERE id = $3
-		 RETURNING id, column_id, title, description, position, created_at`,
+		 SET title = $1, description = $2, start_at = $3, due_at = $4
+		 WHERE id = $5`,
 		task.Title,
 		task.Description,
+		task.StartAt,
+		task.DueAt,
 		task.ID,
 	)
+	if err != nil {
+		return domain.Task{}, domain.ErrInternal
+	}
+	if result.RowsAffected() == 0 {
+		return domain.Task{}, domain.ErrNotFound
+	}
 
-	var updated domain.Task
-	err := row.Scan(
-		&updated.ID,
-		&updated.ColumnID,
-		&updated.Title,
-		&updated.Description,
-		&updated.Position,
-		&updated.CreatedAt,
-	)
+	if err := replaceFieldValues(ctx, tx, task.ID, task.CustomFields); err != nil {
+		return domain.Task{}, err
+	}
+
+	updated, err := scanTask(tx.QueryRow(ctx,
+		`SELECT `+taskColumns+`
+		 FROM tasks t
+		 WHERE t.id = $1`,
+		task.ID,
+	))
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return domain.Task{}, domain.ErrNotFound
-		}
+		return domain.Task{}, domain.ErrInternal
+	}
+
+	if err := tx.Commit(ctx); err != nil {
 		return domain.Task{}, domain.ErrInternal
 	}
 
@@ -179,26 +229,17 @@ func (r *TaskRepository) Move(
 	}
 	defer tx.Rollback(ctx)
 
-	var task domain.Task
+	var exists bool
 	err = tx.QueryRow(ctx,
-		`SELECT id, title, column_id, position, description, created_at
-		 FROM tasks
-		 WHERE id = $1`,
+		`SELECT EXISTS (SELECT 1 FROM tasks WHERE id = $1)`,
 		taskID,
-	).Scan(
-		&task.ID,
-		&task.Title,
-		&task.ColumnID,
-		&task.Position,
-		&task.Description,
-		&task.CreatedAt,
-	)
+	).Scan(&exists)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return domain.Task{}, domain.ErrNotFound
-		}
 		return domain.Task{}, domain.ErrInternal
 	}
+	if !exists {
+		return domain.Task{}, domain.ErrNotFound
+	}
 
 	_, err = tx.Exec(ctx,
 		`UPDATE tasks
@@ -211,20 +252,13 @@ func (r *TaskRepository) Move(
 		return domain.Task{}, domain.ErrInternal
 	}
 
-	err = tx.QueryRow(ctx,
-		`UPDATE tasks
+	task, err := scanTask(tx.QueryRow(ctx,
+		`UPDATE tasks AS t
 		 SET column_id = $1, position = $2
 		 WHERE id = $3
-		 RETURNING id, title, column_id, position, description, created_at`,
+		 RETURNING `+taskColumns,
 		columnID, position, taskID,
-	).Scan(
-		&task.ID,
-		&task.Title,
-		&task.ColumnID,
-		&task.Position,
-		&task.Description,
-		&task.CreatedAt,
-	)
+	))
 	if err != nil {
 		return domain.Task{}, domain.ErrInternal
 	}
@@ -235,3 +269,86 @@ func (r *TaskRepository) Move(
 
 	return task, nil
 }
+
+func replaceFieldValues(ctx context.Context, tx pgx.Tx, taskID string, values domain.CustomFieldValues) error {
+	if _, err := tx.Exec(ctx,
+		`DELETE FROM task_field_values
+		 WHERE task_id = $1`,
+		taskID,
+	); err != nil {
+		return domain.ErrInternal
+	}
+
+	for fieldID, value := range values {
+		// строку pgx передал бы в jsonb как готовый JSON, поэтому кодируем сами
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return domain.ErrInternal
+		}
+
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO task_field_values (task_id, field_id, value)
+			 VALUES ($1, $2, $3)`,
+			taskID,
+			fieldID,
+			encoded,
+		); err != nil {
+			return domain.ErrInternal
+		}
+	}
+
+	return nil
+}
+
+func (r *TaskRepository) list(query string, args ...any) ([]domain.Task, error) {
+	rows, err := r.db.Query(context.Background(), query, args...)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	defer rows.Close()
+
+	tasks := make([]domain.Task, 0)
+
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			return nil, domain.ErrInternal
+		}
+		tasks = append(tasks, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return tasks, nil
+}
+
+// taskFields — адреса для Scan в порядке taskColumns
+func taskFields(t *domain.Task) []any {
+	return []any{
+		&t.ID,
+		&t.ColumnID,
+		&t.Title,
+		&t.Description,
+		&t.Position,
+		&t.CreatedAt,
+		&t.StartAt,
+		&t.DueAt,
+		&t.AssigneeIDs,
+		&t.LabelIDs,
+		&t.CustomFields,
+		&t.Checklist.Done,
+		&t.Checklist.Total,
+	}
+}
+
+func scanTask(row pgx.Row) (domain.Task, error) {
+	var t domain.Task
+
+	if err := row.Scan(taskFields(&t)...); err != nil {
+		return domain.Task{}, err
+	}
+
+	return t, nil
+}
@@ -0,0 +1,323 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+type TeamRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewTeamRepository(db *pgxpool.Pool) *TeamRepository {
+	return &TeamRepository{db: db}
+}
+
+func (r *TeamRepository) Create(team domain.Team, maintainer domain.TeamMember) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return domain.ErrInternal
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO teams (id, name, created_at)
+		 VALUES ($1, $2, $3)`,
+		team.ID,
+		team.Name,
+		team.CreatedAt,
+	); err != nil {
+		return domain.ErrInternal
+	}
+
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO team_members (id, team_id, user_id, role, created_at)
+		 VALUES ($1, $2, $3, $4, $5)`,
+		maintainer.ID,
+		maintainer.TeamID,
+		maintainer.UserID,
+		string(maintainer.Role),
+		maintainer.CreatedAt,
+	); err != nil {
+		return domain.ErrInternal
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (r *TeamRepository) GetByID(teamID string) (domain.Team, error) {
+	row := r.db.QueryRow(
+		context.Background(),
+		`SELECT id, name, created_at FROM teams WHERE id = $1`,
+		teamID,
+	)
+
+	var w domain.Team
+	if err := row.Scan(&w.ID, &w.Name, &w.CreatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Team{}, domain.ErrNotFound
+		}
+		return domain.Team{}, domain.ErrInternal
+	}
+
+	return w, nil
+}
+
+func (r *TeamRepository) ListByUser(userID string) ([]domain.Team, error) {
+	rows, err := r.db.Query(
+		context.Background(),
+		`SELECT w.id, w.name, w.created_at
+		 FROM teams w
+		 JOIN team_members m ON m.team_id = w.id
+		 WHERE m.user_id = $1
+		 ORDER BY w.created_at`,
+		userID,
+	)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	defer rows.Close()
+
+	teams := make([]domain.Team, 0)
+
+	for rows.Next() {
+		var w domain.Team
+		if err := rows.Scan(&w.ID, &w.Name, &w.CreatedAt); err != nil {
+			return nil, domain.ErrInternal
+		}
+		teams = append(teams, w)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return teams, nil
+}
+
+type TeamMemberRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewTeamMemberRepository(db *pgxpool.Pool) *TeamMemberRepository {
+	return &TeamMemberRepository{db: db}
+}
+
+func (r *TeamMemberRepository) Add(member domain.TeamMember) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		`INSERT INTO team_members (id, team_id, user_id, role, created_at)
+		 VALUES ($1, $2, $3, $4, $5)`,
+		member.ID,
+		member.TeamID,
+		member.UserID,
+		string(member.Role),
+		member.CreatedAt,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return domain.ErrUserAlreadyExists
+		}
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (r *TeamMemberRepository) GetRole(teamID, userID string) (domain.TeamRole, error) {
+	row := r.db.QueryRow(
+		context.Background(),
+		`SELECT role
+		 FROM team_members
+		 WHERE team_id = $1 AND user_id = $2`,
+		teamID,
+		userID,
+	)
+
+	return scanTeamRole(row)
+}
+
+// GetMembers возвращает участников вместе с профилями пользователей
+func (r *TeamMemberRepository) GetMembers(teamID string) ([]domain.TeamMember, error) {
+	rows, err := r.db.Query(
+		context.Background(),
+		`SELECT m.id, m.team_id, m.user_id, m.role, m.created_at,
+		        u.email, u.display_name, u.avatar_url, u.timezone, u.locale
+		 FROM team_members m
+		 JOIN users u ON u.id = m.user_id
+		 WHERE m.team_id = $1
+		 ORDER BY m.created_at`,
+		teamID,
+	)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	defer rows.Close()
+
+	members := make([]domain.TeamMember, 0)
+
+	for rows.Next() {
+		var (
+			m       domain.TeamMember
+			profile domain.UserProfile
+		)
+		if err := rows.Scan(
+			&m.ID,
+			&m.TeamID,
+			&m.UserID,
+			&m.Role,
+			&m.CreatedAt,
+			&profile.Email,
+			&profile.DisplayName,
+			&profile.AvatarURL,
+			&profile.Timezone,
+			&profile.Locale,
+		); err != nil {
+			return nil, domain.ErrInternal
+		}
+		profile.ID = m.UserID
+		m.User = &profile
+		members = append(members, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return members, nil
+}
+
+func (r *TeamMemberRepository) Remove(teamID, userID string) error {
+	result, err := r.db.Exec(
+		context.Background(),
+		`DELETE FROM team_members
+		 WHERE team_id = $1 AND user_id = $2`,
+		teamID,
+		userID,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func scanTeamRole(row pgx.Row) (domain.TeamRole, error) {
+	var role string
+	if err := row.Scan(&role); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", domain.ErrNotFound
+		}
+		return "", domain.ErrInternal
+	}
+
+	return domain.TeamRole(role), nil
+}
+
+type BoardTeamRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewBoardTeamRepository(db *pgxpool.Pool) *BoardTeamRepository {
+	return &BoardTeamRepository{db: db}
+}
+
+func (r *BoardTeamRepository) Grant(grant domain.BoardTeamGrant) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		`INSERT INTO board_team_grants (board_id, team_id, role, granted_by, created_at)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (board_id, team_id) DO UPDATE SET role = EXCLUDED.role`,
+		grant.BoardID,
+		grant.TeamID,
+		string(grant.Role),
+		grant.GrantedBy,
+		grant.CreatedAt,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (r *BoardTeamRepository) Revoke(boardID, teamID string) error {
+	result, err := r.db.Exec(
+		context.Background(),
+		`DELETE FROM board_team_grants
+		 WHERE board_id = $1 AND team_id = $2`,
+		boardID,
+		teamID,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *BoardTeamRepository) ListByBoard(boardID string) ([]domain.BoardTeamGrant, error) {
+	return r.list(
+		`SELECT g.board_id, g.team_id, t.name, g.role, g.granted_by, g.created_at
+		 FROM board_team_grants g
+		 JOIN teams t ON t.id = g.team_id
+		 WHERE g.board_id = $1
+		 ORDER BY g.created_at`,
+		boardID,
+	)
+}
+
+func (r *BoardTeamRepository) ListByTeam(teamID string) ([]domain.BoardTeamGrant, error) {
+	return r.list(
+		`SELECT g.board_id, g.team_id, t.name, g.role, g.granted_by, g.created_at
+		 FROM board_team_grants g
+		 JOIN teams t ON t.id = g.team_id
+		 WHERE g.team_id = $1
+		 ORDER BY g.created_at`,
+		teamID,
+	)
+}
+
+func (r *BoardTeamRepository) list(query string, args ...any) ([]domain.BoardTeamGrant, error) {
+	rows, err := r.db.Query(context.Background(), query, args...)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	defer rows.Close()
+
+	grants := make([]domain.BoardTeamGrant, 0)
+
+	for rows.Next() {
+		var g domain.BoardTeamGrant
+		if err := rows.Scan(&g.BoardID, &g.TeamID, &g.TeamName, &g.Role, &g.GrantedBy, &g.CreatedAt); err != nil {
+			return nil, domain.ErrInternal
+		}
+		grants = append(grants, g)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return grants, nil
+}
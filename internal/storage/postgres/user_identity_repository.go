@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+type UserIdentityRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewUserIdentityRepository(db *pgxpool.Pool) *UserIdentityRepository {
+	return &UserIdentityRepository{db: db}
+}
+
+func (r *UserIdentityRepository) Create(identity domain.UserIdentity) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		`INSERT INTO user_identities (provider, subject, user_id, email, created_at)
+		 VALUES ($1, $2, $3, $4, $5)`,
+		identity.Provider,
+		identity.Subject,
+		identity.UserID,
+		identity.Email,
+		identity.CreatedAt,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return domain.ErrConflict
+		}
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (r *UserIdentityRepository) Get(provider, subject string) (domain.UserIdentity, error) {
+	row := r.db.QueryRow(
+		context.Background(),
+		`SELECT provider, subject, user_id, email, created_at
+		 FROM user_identities
+		 WHERE provider = $1 AND subject = $2`,
+		provider,
+		subject,
+	)
+
+	var i domain.UserIdentity
+	err := row.Scan(&i.Provider, &i.Subject, &i.UserID, &i.Email, &i.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.UserIdentity{}, domain.ErrNotFound
+		}
+		return domain.UserIdentity{}, domain.ErrInternal
+	}
+
+	return i, nil
+}
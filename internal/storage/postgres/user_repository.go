@@ -3,6 +3,9 @@ package postgres
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -20,12 +23,18 @@ func NewUserRepository(db *pgxpool.Pool) *UserRepository {
 func (r *UserRepository) Create(user domain.User) error {
 	_, err := r.db.Exec(
 		context.Background(),
-		`INSERT INTO users (id, email, password_hash, created_at)
-		 VALUES ($1, $2, $3, $4)`,
+		`INSERT INTO users (id, email, password_hash, created_at, email_verified,
+		                    display_name, avatar_url, timezone, locale)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
 		user.ID,
 		user.Email,
 		user.PasswordHash,
 		user.CreatedAt,
+		user.EmailVerified,
+		user.DisplayName,
+		user.AvatarURL,
+		user.Timezone,
+		user.Locale,
 	)
 	if err != nil {
 		return domain.ErrInternal
@@ -34,22 +43,20 @@ func (r *UserRepository) Create(user domain.User) error {
 	return nil
 }
 
+const userColumns = `id, email, password_hash, created_at, email_verified,
+	totp_secret, totp_enabled, display_name, avatar_url, timezone, locale,
+	is_admin, disabled_at`
+
 func (r *UserRepository) GetByEmail(email string) (domain.User, error) {
 	row := r.db.QueryRow(
 		context.Background(),
-		`SELECT id, email, password_hash, created_at
+		`SELECT `+userColumns+`
 		 FROM users
 		 WHERE email = $1`,
 		email,
 	)
 
-	var u domain.User
-	err := row.Scan(
-		&u.ID,
-		&u.Email,
-		&u.PasswordHash,
-		&u.CreatedAt,
-	)
+	u, err := scanUser(row)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return domain.User{}, domain.ErrNotFound
@@ -63,19 +70,13 @@ func (r *UserRepository) GetByEmail(email string) (domain.User, error) {
 func (r *UserRepository) GetByID(id string) (domain.User, error) {
 	row := r.db.QueryRow(
 		context.Background(),
-		`SELECT id, email, password_hash, created_at
+		`SELECT `+userColumns+`
 		 FROM users
 		 WHERE id = $1`,
 		id,
 	)
 
-	var u domain.User
-	err := row.Scan(
-		&u.ID,
-		&u.Email,
-		&u.PasswordHash,
-		&u.CreatedAt,
-	)
+	u, err := scanUser(row)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return domain.User{}, domain.ErrNotFound
@@ -85,3 +86,207 @@ func (r *UserRepository) GetByID(id string) (domain.User, error) {
 
 	return u, nil
 }
+
+func (r *UserRepository) UpdateTOTP(userID, secret string, enabled bool) error {
+	result, err := r.db.Exec(
+		context.Background(),
+		`UPDATE users
+		 SET totp_secret = $2, totp_enabled = $3
+		 WHERE id = $1`,
+		userID,
+		secret,
+		enabled,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *UserRepository) UseTOTPCounter(userID string, counter int64) error {
+	result, err := r.db.Exec(
+		context.Background(),
+		`UPDATE users
+		 SET totp_last_counter = $2
+		 WHERE id = $1 AND (totp_last_counter IS NULL OR totp_last_counter < $2)`,
+		userID,
+		counter,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrConflict
+	}
+
+	return nil
+}
+
+func (r *UserRepository) UpdatePassword(userID, passwordHash string) error {
+	result, err := r.db.Exec(
+		context.Background(),
+		`UPDATE users
+		 SET password_hash = $2
+		 WHERE id = $1`,
+		userID,
+		passwordHash,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *UserRepository) MarkEmailVerified(userID string) error {
+	result, err := r.db.Exec(
+		context.Background(),
+		`UPDATE users
+		 SET email_verified = TRUE
+		 WHERE id = $1`,
+		userID,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *UserRepository) UpdateProfile(user domain.User) error {
+	result, err := r.db.Exec(
+		context.Background(),
+		`UPDATE users
+		 SET display_name = $2, avatar_url = $3, timezone = $4, locale = $5
+		 WHERE id = $1`,
+		user.ID,
+		user.DisplayName,
+		user.AvatarURL,
+		user.Timezone,
+		user.Locale,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *UserRepository) SearchCoMembers(requesterID, prefix string, limit int) ([]domain.User, error) {
+	return r.list(
+		`SELECT `+userColumns+`
+		 FROM users u
+		 WHERE EXISTS (
+		     SELECT 1 FROM boards b
+		     WHERE `+fmt.Sprintf(boardVisibleTo, "$1")+`
+		       AND `+fmt.Sprintf(boardVisibleTo, "u.id")+`
+		 )
+		 AND (lower(email) LIKE $2 OR lower(display_name) LIKE $2)
+		 ORDER BY email
+		 LIMIT $3`,
+		requesterID,
+		escapeLike(strings.ToLower(prefix))+"%",
+		limit,
+	)
+}
+
+func (r *UserRepository) Search(query string, limit, offset int) ([]domain.User, error) {
+	return r.list(
+		`SELECT `+userColumns+`
+		 FROM users
+		 WHERE lower(email) LIKE $1 OR lower(display_name) LIKE $1
+		 ORDER BY created_at
+		 LIMIT $2 OFFSET $3`,
+		"%"+escapeLike(strings.ToLower(query))+"%",
+		limit,
+		offset,
+	)
+}
+
+func (r *UserRepository) SetDisabled(userID string, disabledAt *time.Time) error {
+	result, err := r.db.Exec(
+		context.Background(),
+		`UPDATE users
+		 SET disabled_at = $2
+		 WHERE id = $1`,
+		userID,
+		disabledAt,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *UserRepository) list(query string, args ...any) ([]domain.User, error) {
+	rows, err := r.db.Query(context.Background(), query, args...)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	defer rows.Close()
+
+	users := make([]domain.User, 0)
+
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, domain.ErrInternal
+		}
+		users = append(users, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return users, nil
+}
+
+func scanUser(row pgx.Row) (domain.User, error) {
+	var u domain.User
+	err := row.Scan(
+		&u.ID,
+		&u.Email,
+		&u.PasswordHash,
+		&u.CreatedAt,
+		&u.EmailVerified,
+		&u.TOTPSecret,
+		&u.TOTPEnabled,
+		&u.DisplayName,
+		&u.AvatarURL,
+		&u.Timezone,
+		&u.Locale,
+		&u.IsAdmin,
+		&u.DisabledAt,
+	)
+	return u, err
+}
+
+// escapeLike экранирует спецсимволы LIKE (escape-символ по умолчанию — "\"),
+// чтобы "%" и "_" в запросе искались буквально
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+type UserTokenRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewUserTokenRepository(db *pgxpool.Pool) *UserTokenRepository {
+	return &UserTokenRepository{db: db}
+}
+
+func (r *UserTokenRepository) Create(token domain.UserToken) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		`INSERT INTO user_tokens (id, user_id, purpose, token_hash, expires_at, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6)`,
+		token.ID,
+		token.UserID,
+		string(token.Purpose),
+		token.TokenHash,
+		token.ExpiresAt,
+		token.CreatedAt,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (r *UserTokenRepository) GetByHash(purpose domain.UserTokenPurpose, tokenHash string) (domain.UserToken, error) {
+	row := r.db.QueryRow(
+		context.Background(),
+		`SELECT id, user_id, purpose, token_hash, expires_at, created_at, used_at
+		 FROM user_tokens
+		 WHERE purpose = $1 AND token_hash = $2`,
+		string(purpose),
+		tokenHash,
+	)
+
+	var (
+		t             domain.UserToken
+		storedPurpose string
+	)
+	err := row.Scan(
+		&t.ID,
+		&t.UserID,
+		&storedPurpose,
+		&t.TokenHash,
+		&t.ExpiresAt,
+		&t.CreatedAt,
+		&t.UsedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.UserToken{}, domain.ErrNotFound
+		}
+		return domain.UserToken{}, domain.ErrInternal
+	}
+	t.Purpose = domain.UserTokenPurpose(storedPurpose)
+
+	return t, nil
+}
+
+// MarkUsed возвращает ErrConflict, если токен уже был использован.
+func (r *UserTokenRepository) MarkUsed(id string, usedAt time.Time) error {
+	result, err := r.db.Exec(
+		context.Background(),
+		`UPDATE user_tokens
+		 SET used_at = $2
+		 WHERE id = $1 AND used_at IS NULL`,
+		id,
+		usedAt,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrConflict
+	}
+
+	return nil
+}
+
+func (r *UserTokenRepository) InvalidateForUser(userID string, purpose domain.UserTokenPurpose, usedAt time.Time) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		`UPDATE user_tokens
+		 SET used_at = $3
+		 WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL`,
+		userID,
+		string(purpose),
+		usedAt,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
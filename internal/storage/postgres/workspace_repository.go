@@ -0,0 +1,245 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+type WorkspaceRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewWorkspaceRepository(db *pgxpool.Pool) *WorkspaceRepository {
+	return &WorkspaceRepository{db: db}
+}
+
+func (r *WorkspaceRepository) Create(workspace domain.Workspace, admin domain.WorkspaceMember) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return domain.ErrInternal
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO workspaces (id, name, created_at)
+		 VALUES ($1, $2, $3)`,
+		workspace.ID,
+		workspace.Name,
+		workspace.CreatedAt,
+	); err != nil {
+		return domain.ErrInternal
+	}
+
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO workspace_members (id, workspace_id, user_id, role, created_at)
+		 VALUES ($1, $2, $3, $4, $5)`,
+		admin.ID,
+		admin.WorkspaceID,
+		admin.UserID,
+		string(admin.Role),
+		admin.CreatedAt,
+	); err != nil {
+		return domain.ErrInternal
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (r *WorkspaceRepository) GetByID(workspaceID string) (domain.Workspace, error) {
+	row := r.db.QueryRow(
+		context.Background(),
+		`SELECT id, name, created_at FROM workspaces WHERE id = $1`,
+		workspaceID,
+	)
+
+	var w domain.Workspace
+	if err := row.Scan(&w.ID, &w.Name, &w.CreatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Workspace{}, domain.ErrNotFound
+		}
+		return domain.Workspace{}, domain.ErrInternal
+	}
+
+	return w, nil
+}
+
+func (r *WorkspaceRepository) ListByUser(userID string) ([]domain.Workspace, error) {
+	rows, err := r.db.Query(
+		context.Background(),
+		`SELECT w.id, w.name, w.created_at
+		 FROM workspaces w
+		 JOIN workspace_members m ON m.workspace_id = w.id
+		 WHERE m.user_id = $1
+		 ORDER BY w.created_at`,
+		userID,
+	)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	defer rows.Close()
+
+	workspaces := make([]domain.Workspace, 0)
+
+	for rows.Next() {
+		var w domain.Workspace
+		if err := rows.Scan(&w.ID, &w.Name, &w.CreatedAt); err != nil {
+			return nil, domain.ErrInternal
+		}
+		workspaces = append(workspaces, w)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return workspaces, nil
+}
+
+type WorkspaceMemberRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewWorkspaceMemberRepository(db *pgxpool.Pool) *WorkspaceMemberRepository {
+	return &WorkspaceMemberRepository{db: db}
+}
+
+func (r *WorkspaceMemberRepository) Add(member domain.WorkspaceMember) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		`INSERT INTO workspace_members (id, workspace_id, user_id, role, created_at)
+		 VALUES ($1, $2, $3, $4, $5)`,
+		member.ID,
+		member.WorkspaceID,
+		member.UserID,
+		string(member.Role),
+		member.CreatedAt,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return domain.ErrUserAlreadyExists
+		}
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (r *WorkspaceMemberRepository) GetRole(workspaceID, userID string) (domain.WorkspaceRole, error) {
+	row := r.db.QueryRow(
+		context.Background(),
+		`SELECT role
+		 FROM workspace_members
+		 WHERE workspace_id = $1 AND user_id = $2`,
+		workspaceID,
+		userID,
+	)
+
+	return scanWorkspaceRole(row)
+}
+
+func (r *WorkspaceMemberRepository) GetRoleByBoard(boardID, userID string) (domain.WorkspaceRole, error) {
+	row := r.db.QueryRow(
+		context.Background(),
+		`SELECT m.role
+		 FROM boards b
+		 JOIN workspace_members m ON m.workspace_id = b.workspace_id
+		 WHERE b.id = $1 AND m.user_id = $2`,
+		boardID,
+		userID,
+	)
+
+	return scanWorkspaceRole(row)
+}
+
+// GetMembers возвращает участников вместе с профилями пользователей
+func (r *WorkspaceMemberRepository) GetMembers(workspaceID string) ([]domain.WorkspaceMember, error) {
+	rows, err := r.db.Query(
+		context.Background(),
+		`SELECT m.id, m.workspace_id, m.user_id, m.role, m.created_at,
+		        u.email, u.display_name, u.avatar_url, u.timezone, u.locale
+		 FROM workspace_members m
+		 JOIN users u ON u.id = m.user_id
+		 WHERE m.workspace_id = $1
+		 ORDER BY m.created_at`,
+		workspaceID,
+	)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	defer rows.Close()
+
+	members := make([]domain.WorkspaceMember, 0)
+
+	for rows.Next() {
+		var (
+			m       domain.WorkspaceMember
+			profile domain.UserProfile
+		)
+		if err := rows.Scan(
+			&m.ID,
+			&m.WorkspaceID,
+			&m.UserID,
+			&m.Role,
+			&m.CreatedAt,
+			&profile.Email,
+			&profile.DisplayName,
+			&profile.AvatarURL,
+			&profile.Timezone,
+			&profile.Locale,
+		); err != nil {
+			return nil, domain.ErrInternal
+		}
+		profile.ID = m.UserID
+		m.User = &profile
+		members = append(members, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return members, nil
+}
+
+func (r *WorkspaceMemberRepository) Remove(workspaceID, userID string) error {
+	result, err := r.db.Exec(
+		context.Background(),
+		`DELETE FROM workspace_members
+		 WHERE workspace_id = $1 AND user_id = $2`,
+		workspaceID,
+		userID,
+	)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func scanWorkspaceRole(row pgx.Row) (domain.WorkspaceRole, error) {
+	var role string
+	if err := row.Scan(&role); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", domain.ErrNotFound
+		}
+		return "", domain.ErrInternal
+	}
+
+	return domain.WorkspaceRole(role), nil
+}
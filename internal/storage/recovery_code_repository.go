@@ -0,0 +1,15 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+type RecoveryCodeRepository interface {
+	// Replace удаляет все коды пользователя и сохраняет новый набор
+	Replace(userID string, codes []domain.RecoveryCode) error
+	ListUnused(userID string) ([]domain.RecoveryCode, error)
+	MarkUsed(id string, usedAt time.Time) error
+	DeleteAllForUser(userID string) error
+}
@@ -0,0 +1,15 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+type RefreshTokenRepository interface {
+	Create(token domain.RefreshToken) error
+	GetByHash(tokenHash string) (domain.RefreshToken, error)
+	MarkUsed(id string, usedAt time.Time) error
+	RevokeFamily(familyID string, revokedAt time.Time) error
+	RevokeAllForUser(userID string, revokedAt time.Time) error
+}
@@ -0,0 +1,16 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+type SessionRepository interface {
+	Create(session domain.Session) error
+	GetByID(id string) (domain.Session, error)
+	ListActiveByUser(userID string, now time.Time) ([]domain.Session, error)
+	Touch(session domain.Session) error
+	Revoke(id string, revokedAt time.Time) error
+	RevokeAllForUser(userID string, revokedAt time.Time) error
+}
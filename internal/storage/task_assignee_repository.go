@@ -0,0 +1,22 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+type TaskAssigneeRepository interface {
+	// Add не считает ошибкой повторное назначение того же пользователя
+	Add(assignee domain.TaskAssignee) error
+	Remove(taskID, userID string) error
+	ListByTask(taskID string) ([]domain.TaskAssignee, error)
+	// ListByUser возвращает назначенные задачи на всех досках, к которым
+	// у пользователя есть доступ
+	ListByUser(userID string) ([]domain.AssignedTask, error)
+	// ListDueByUser — то же, но только задачи со сроком в [from, to)
+	// по возрастанию срока; from == nil — без нижней границы
+	ListDueByUser(userID string, from *time.Time, to time.Time) ([]domain.AssignedTask, error)
+	// RemoveFromBoard снимает пользователя со всех задач доски
+	RemoveFromBoard(boardID, userID string) error
+}
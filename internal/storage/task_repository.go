@@ -1,10 +1,17 @@
 package storage
 
-import "github.com/ovk741/TasksStream/internal/domain"
+import (
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+)
 
 type TaskRepository interface {
 	Create(domain.Task) (domain.Task, error)
 	GetByColumnID(ColumnID string) ([]domain.Task, error)
+	// ListDueByBoard возвращает задачи доски со сроком в [from, to) по возрастанию
+	// срока; from == nil — без нижней границы
+	ListDueByBoard(boardID string, from *time.Time, to time.Time) ([]domain.Task, error)
 	GetByID(id string) (domain.Task, error)
 	Update(task domain.Task) (domain.Task, error)
 	Delete(id string) error
@@ -0,0 +1,26 @@
+package storage
+
+import "github.com/ovk741/TasksStream/internal/domain"
+
+type TeamRepository interface {
+	// Create в одной транзакции создаёт команду и её первого мейнтейнера
+	Create(team domain.Team, maintainer domain.TeamMember) error
+	GetByID(teamID string) (domain.Team, error)
+	ListByUser(userID string) ([]domain.Team, error)
+}
+
+type TeamMemberRepository interface {
+	// Add возвращает ErrUserAlreadyExists, если пользователь уже в команде
+	Add(member domain.TeamMember) error
+	GetRole(teamID, userID string) (domain.TeamRole, error)
+	GetMembers(teamID string) ([]domain.TeamMember, error)
+	Remove(teamID, userID string) error
+}
+
+type BoardTeamRepository interface {
+	// Grant выдаёт команде роль на доске или меняет уже выданную
+	Grant(grant domain.BoardTeamGrant) error
+	Revoke(boardID, teamID string) error
+	ListByBoard(boardID string) ([]domain.BoardTeamGrant, error)
+	ListByTeam(teamID string) ([]domain.BoardTeamGrant, error)
+}
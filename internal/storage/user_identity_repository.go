@@ -0,0 +1,8 @@
+package storage
+
+import "github.com/ovk741/TasksStream/internal/domain"
+
+type UserIdentityRepository interface {
+	Create(identity domain.UserIdentity) error
+	Get(provider, subject string) (domain.UserIdentity, error)
+}
@@ -1,9 +1,28 @@
 package storage
 
-import "github.com/ovk741/TasksStream/internal/domain"
+import (
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+)
 
 type UserRepository interface {
 	Create(user domain.User) error
 	GetByEmail(email string) (domain.User, error)
 	GetByID(id string) (domain.User, error)
+	UpdateTOTP(userID, secret string, enabled bool) error
+	// UseTOTPCounter запоминает шаг принятого TOTP-кода; ErrConflict — код
+	// этого или более позднего шага уже принимался
+	UseTOTPCounter(userID string, counter int64) error
+	UpdatePassword(userID, passwordHash string) error
+	MarkEmailVerified(userID string) error
+	UpdateProfile(user domain.User) error
+	// SearchCoMembers ищет по префиксу email или имени среди пользователей,
+	// у которых есть доступ хотя бы к одной доске, доступной requesterID
+	SearchCoMembers(requesterID, prefix string, limit int) ([]domain.User, error)
+	// Search ищет по подстроке email или имени среди всех пользователей;
+	// пустой запрос возвращает всех
+	Search(query string, limit, offset int) ([]domain.User, error)
+	// SetDisabled отключает пользователя или, при nil, включает обратно
+	SetDisabled(userID string, disabledAt *time.Time) error
 }
@@ -0,0 +1,15 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/ovk741/TasksStream/internal/domain"
+)
+
+type UserTokenRepository interface {
+	Create(token domain.UserToken) error
+	GetByHash(purpose domain.UserTokenPurpose, tokenHash string) (domain.UserToken, error)
+	MarkUsed(id string, usedAt time.Time) error
+	// InvalidateForUser гасит все ещё не использованные токены с этим назначением
+	InvalidateForUser(userID string, purpose domain.UserTokenPurpose, usedAt time.Time) error
+}
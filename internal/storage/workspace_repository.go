@@ -0,0 +1,21 @@
+package storage
+
+import "github.com/ovk741/TasksStream/internal/domain"
+
+type WorkspaceRepository interface {
+	// Create в одной транзакции создаёт пространство и его первого администратора
+	Create(workspace domain.Workspace, admin domain.WorkspaceMember) error
+	GetByID(workspaceID string) (domain.Workspace, error)
+	ListByUser(userID string) ([]domain.Workspace, error)
+}
+
+type WorkspaceMemberRepository interface {
+	// Add возвращает ErrUserAlreadyExists, если пользователь уже в пространстве
+	Add(member domain.WorkspaceMember) error
+	GetRole(workspaceID, userID string) (domain.WorkspaceRole, error)
+	// GetRoleByBoard возвращает роль пользователя в пространстве, которому
+	// принадлежит доска; ErrNotFound — доска личная или он не участник
+	GetRoleByBoard(boardID, userID string) (domain.WorkspaceRole, error)
+	GetMembers(workspaceID string) ([]domain.WorkspaceMember, error)
+	Remove(workspaceID, userID string) error
+}
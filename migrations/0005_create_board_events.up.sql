@@ -0,0 +1,13 @@
+CREATE TABLE board_events (
+    board_id TEXT NOT NULL,
+    id BIGINT NOT NULL,
+    type TEXT NOT NULL,
+    actor_id TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+
+    -- без внешнего ключа на boards: событие board.deleted
+    -- записывается уже после удаления доски
+    CONSTRAINT pk_board_events
+        PRIMARY KEY (board_id, id)
+);
@@ -0,0 +1,24 @@
+CREATE TABLE sessions (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    user_agent TEXT NOT NULL,
+    ip TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    last_used_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+
+    CONSTRAINT fk_sessions_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_sessions_user ON sessions (user_id);
+
+-- сессии для семейств, выданных до появления таблицы
+INSERT INTO sessions (id, user_id, user_agent, ip, created_at, last_used_at, expires_at)
+SELECT family_id, user_id, '', '', MIN(created_at), MAX(created_at), MAX(expires_at)
+FROM refresh_tokens
+WHERE revoked_at IS NULL
+GROUP BY family_id, user_id;
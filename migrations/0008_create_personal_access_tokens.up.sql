@@ -0,0 +1,21 @@
+CREATE TABLE personal_access_tokens (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    token_hash TEXT NOT NULL,
+    scopes TEXT[] NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    last_used_at TIMESTAMP,
+    expires_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+
+    CONSTRAINT fk_personal_access_tokens_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT uniq_personal_access_tokens_hash
+        UNIQUE (token_hash)
+);
+
+CREATE INDEX idx_personal_access_tokens_user ON personal_access_tokens (user_id);
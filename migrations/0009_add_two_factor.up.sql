@@ -0,0 +1,19 @@
+ALTER TABLE users
+    ADD COLUMN totp_secret TEXT NOT NULL DEFAULT '',
+    ADD COLUMN totp_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN totp_last_counter BIGINT;
+
+CREATE TABLE recovery_codes (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    code_hash TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+
+    CONSTRAINT fk_recovery_codes_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_recovery_codes_user ON recovery_codes (user_id);
@@ -0,0 +1,27 @@
+-- существующие пользователи регистрировались без подтверждения адреса и
+-- считаются подтвердившими его, иначе REQUIRE_EMAIL_VERIFICATION закрыл бы им вход
+ALTER TABLE users
+    ADD COLUMN email_verified BOOLEAN NOT NULL DEFAULT TRUE;
+
+ALTER TABLE users
+    ALTER COLUMN email_verified SET DEFAULT FALSE;
+
+CREATE TABLE user_tokens (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    purpose TEXT NOT NULL,
+    token_hash TEXT NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+
+    CONSTRAINT fk_user_tokens_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT uniq_user_tokens_hash
+        UNIQUE (token_hash)
+);
+
+CREATE INDEX idx_user_tokens_user_purpose ON user_tokens (user_id, purpose);
@@ -0,0 +1,17 @@
+CREATE TABLE login_attempts (
+    key TEXT PRIMARY KEY,
+    failures INTEGER NOT NULL,
+    last_failure_at TIMESTAMP NOT NULL,
+    locked_until TIMESTAMP
+);
+
+CREATE TABLE login_lockouts (
+    id TEXT PRIMARY KEY,
+    key TEXT NOT NULL,
+    failures INTEGER NOT NULL,
+    ip TEXT NOT NULL,
+    locked_until TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_login_lockouts_key ON login_lockouts (key, created_at);
@@ -0,0 +1,9 @@
+ALTER TABLE users
+    ADD COLUMN display_name TEXT NOT NULL DEFAULT '',
+    ADD COLUMN avatar_url TEXT NOT NULL DEFAULT '',
+    ADD COLUMN timezone TEXT NOT NULL DEFAULT '',
+    ADD COLUMN locale TEXT NOT NULL DEFAULT '';
+
+-- поиск по префиксу в /users?query=
+CREATE INDEX idx_users_email_prefix ON users (lower(email) text_pattern_ops);
+CREATE INDEX idx_users_display_name_prefix ON users (lower(display_name) text_pattern_ops);
@@ -0,0 +1,23 @@
+CREATE TABLE board_invitations (
+    id TEXT PRIMARY KEY,
+    board_id TEXT NOT NULL,
+    email TEXT NOT NULL,
+    role TEXT NOT NULL,
+    invited_by TEXT NOT NULL,
+    token_hash TEXT NOT NULL,
+    status TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    responded_at TIMESTAMP,
+
+    CONSTRAINT fk_board_invitations_board
+        FOREIGN KEY (board_id)
+        REFERENCES boards(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT uniq_board_invitations_token
+        UNIQUE (token_hash)
+);
+
+CREATE INDEX idx_board_invitations_board ON board_invitations (board_id, status);
+CREATE INDEX idx_board_invitations_email ON board_invitations (lower(email), status);
@@ -0,0 +1,25 @@
+CREATE TABLE board_invite_links (
+    id TEXT PRIMARY KEY,
+    board_id TEXT NOT NULL,
+    role TEXT NOT NULL,
+    created_by TEXT NOT NULL,
+    token_hash TEXT NOT NULL,
+    max_uses INTEGER,
+    uses INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+
+    CONSTRAINT fk_board_invite_links_board
+        FOREIGN KEY (board_id)
+        REFERENCES boards(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT uniq_board_invite_links_token
+        UNIQUE (token_hash),
+
+    CONSTRAINT chk_board_invite_links_uses
+        CHECK (max_uses IS NULL OR uses <= max_uses)
+);
+
+CREATE INDEX idx_board_invite_links_board ON board_invite_links (board_id);
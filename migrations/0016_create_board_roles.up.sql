@@ -0,0 +1,15 @@
+CREATE TABLE board_roles (
+    id TEXT PRIMARY KEY,
+    board_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    permissions TEXT[] NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+
+    CONSTRAINT fk_board_roles_board
+        FOREIGN KEY (board_id)
+        REFERENCES boards(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT uniq_board_roles_name
+        UNIQUE (board_id, name)
+);
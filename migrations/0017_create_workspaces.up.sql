@@ -0,0 +1,36 @@
+CREATE TABLE workspaces (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE workspace_members (
+    id TEXT PRIMARY KEY,
+    workspace_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    role TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+
+    CONSTRAINT fk_workspace_members_workspace
+        FOREIGN KEY (workspace_id)
+        REFERENCES workspaces(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_workspace_members_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT uniq_workspace_user
+        UNIQUE (workspace_id, user_id)
+);
+
+CREATE INDEX idx_workspace_members_user ON workspace_members (user_id);
+
+ALTER TABLE boards
+    ADD COLUMN workspace_id TEXT
+        REFERENCES workspaces(id)
+        ON DELETE SET NULL;
+
+CREATE INDEX idx_boards_workspace ON boards (workspace_id);
+CREATE INDEX idx_board_members_user ON board_members (user_id);
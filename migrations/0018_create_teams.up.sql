@@ -0,0 +1,50 @@
+CREATE TABLE teams (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE team_members (
+    id TEXT PRIMARY KEY,
+    team_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    role TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+
+    CONSTRAINT fk_team_members_team
+        FOREIGN KEY (team_id)
+        REFERENCES teams(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_team_members_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT uniq_team_user
+        UNIQUE (team_id, user_id)
+);
+
+CREATE INDEX idx_team_members_user ON team_members (user_id);
+
+CREATE TABLE board_team_grants (
+    board_id TEXT NOT NULL,
+    team_id TEXT NOT NULL,
+    role TEXT NOT NULL,
+    granted_by TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (board_id, team_id),
+
+    CONSTRAINT fk_board_team_grants_board
+        FOREIGN KEY (board_id)
+        REFERENCES boards(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_board_team_grants_team
+        FOREIGN KEY (team_id)
+        REFERENCES teams(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_board_team_grants_team ON board_team_grants (team_id);
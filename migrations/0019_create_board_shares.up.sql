@@ -0,0 +1,14 @@
+CREATE TABLE board_shares (
+    board_id TEXT PRIMARY KEY,
+    token_hash TEXT NOT NULL,
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+
+    CONSTRAINT fk_board_shares_board
+        FOREIGN KEY (board_id)
+        REFERENCES boards(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT uniq_board_shares_token
+        UNIQUE (token_hash)
+);
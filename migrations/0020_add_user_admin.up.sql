@@ -0,0 +1,3 @@
+ALTER TABLE users
+    ADD COLUMN is_admin BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN disabled_at TIMESTAMP;
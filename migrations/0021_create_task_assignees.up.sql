@@ -0,0 +1,20 @@
+CREATE TABLE task_assignees (
+    task_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    assigned_by TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (task_id, user_id),
+
+    CONSTRAINT fk_task_assignees_task
+        FOREIGN KEY (task_id)
+        REFERENCES tasks(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_task_assignees_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_task_assignees_user ON task_assignees (user_id);
@@ -0,0 +1,5 @@
+ALTER TABLE tasks
+    ADD COLUMN start_at TIMESTAMPTZ,
+    ADD COLUMN due_at TIMESTAMPTZ;
+
+CREATE INDEX idx_tasks_due_at ON tasks (due_at) WHERE due_at IS NOT NULL;
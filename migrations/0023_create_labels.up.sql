@@ -0,0 +1,34 @@
+CREATE TABLE labels (
+    id TEXT PRIMARY KEY,
+    board_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    color TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+
+    CONSTRAINT fk_labels_board
+        FOREIGN KEY (board_id)
+        REFERENCES boards(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT uniq_labels_board_name
+        UNIQUE (board_id, name)
+);
+
+CREATE TABLE task_labels (
+    task_id TEXT NOT NULL,
+    label_id TEXT NOT NULL,
+
+    PRIMARY KEY (task_id, label_id),
+
+    CONSTRAINT fk_task_labels_task
+        FOREIGN KEY (task_id)
+        REFERENCES tasks(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_task_labels_label
+        FOREIGN KEY (label_id)
+        REFERENCES labels(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_task_labels_label ON task_labels (label_id);
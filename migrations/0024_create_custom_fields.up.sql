@@ -0,0 +1,36 @@
+CREATE TABLE custom_fields (
+    id TEXT PRIMARY KEY,
+    board_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    type TEXT NOT NULL,
+    options TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL,
+
+    CONSTRAINT fk_custom_fields_board
+        FOREIGN KEY (board_id)
+        REFERENCES boards(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT uniq_custom_fields_board_name
+        UNIQUE (board_id, name)
+);
+
+CREATE TABLE task_field_values (
+    task_id TEXT NOT NULL,
+    field_id TEXT NOT NULL,
+    value JSONB NOT NULL,
+
+    PRIMARY KEY (task_id, field_id),
+
+    CONSTRAINT fk_task_field_values_task
+        FOREIGN KEY (task_id)
+        REFERENCES tasks(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_task_field_values_field
+        FOREIGN KEY (field_id)
+        REFERENCES custom_fields(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_task_field_values_field ON task_field_values (field_id);
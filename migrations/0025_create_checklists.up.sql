@@ -0,0 +1,30 @@
+CREATE TABLE checklists (
+    id TEXT PRIMARY KEY,
+    task_id TEXT NOT NULL,
+    title TEXT NOT NULL,
+    position INT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+
+    CONSTRAINT fk_checklists_task
+        FOREIGN KEY (task_id)
+        REFERENCES tasks(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_checklists_task ON checklists (task_id);
+
+CREATE TABLE checklist_items (
+    id TEXT PRIMARY KEY,
+    checklist_id TEXT NOT NULL,
+    title TEXT NOT NULL,
+    done BOOLEAN NOT NULL DEFAULT FALSE,
+    position INT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+
+    CONSTRAINT fk_checklist_items_checklist
+        FOREIGN KEY (checklist_id)
+        REFERENCES checklists(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_checklist_items_checklist ON checklist_items (checklist_id);